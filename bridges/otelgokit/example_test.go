@@ -0,0 +1,23 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelgokit_test
+
+import (
+	kitlog "github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"go.opentelemetry.io/otel/log/noop"
+
+	"go.opentelemetry.io/contrib/bridges/otelgokit"
+)
+
+func Example() {
+	// Use a working LoggerProvider implementation instead e.g. using go.opentelemetry.io/otel/sdk/log.
+	provider := noop.NewLoggerProvider()
+
+	// Create an *otelgokit.Logger and use it in your application.
+	var logger kitlog.Logger = otelgokit.NewLogger("my/pkg/name", otelgokit.WithLoggerProvider(provider))
+	logger = kitlog.With(logger, "ts", kitlog.DefaultTimestampUTC, "caller", kitlog.DefaultCaller)
+
+	_ = level.Info(logger).Log("msg", "Hello, World!", "key", "value")
+}
@@ -0,0 +1,337 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package otelgokit provides a [Logger], a [github.com/go-kit/log.Logger]
+// implementation that can be used to bridge between the
+// [github.com/go-kit/log] API and [OpenTelemetry].
+//
+// # Record Conversion
+//
+// The go-kit log events are converted to OpenTelemetry [log.Record] in the
+// following way:
+//
+//   - The value of the message key, "msg" by default, is set as the Body
+//     using an [attribute.StringValue]. See [WithMessageKey].
+//   - The [level.Value] or the string value of the [level.Key] key is
+//     transformed and set as the Severity. The SeverityText is also set.
+//     Events without a level have no severity.
+//   - A [time.Time] value of the "ts" key, or a value formatted with
+//     [time.RFC3339Nano] as added by [github.com/go-kit/log.DefaultTimestamp]
+//     and [github.com/go-kit/log.DefaultTimestampUTC], is set as the
+//     Timestamp.
+//   - A string value of the "caller" key, as added by
+//     [github.com/go-kit/log.DefaultCaller], is set as the code.file.path and
+//     code.line.number attributes.
+//   - An [error] value of the "err" key is set as the record error.
+//   - The [context.Context] values are propagated to OpenTelemetry log
+//     record and not added as attributes. If there are multiple
+//     [context.Context] the last one is used.
+//   - All other key-value pairs are transformed and set as Attributes.
+//
+// The Level is transformed to the OpenTelemetry Severity types in the
+// following way:
+//
+//   - [level.DebugValue] is transformed to [log.SeverityDebug]
+//   - [level.InfoValue] is transformed to [log.SeverityInfo]
+//   - [level.WarnValue] is transformed to [log.SeverityWarn]
+//   - [level.ErrorValue] is transformed to [log.SeverityError]
+//
+// Values are transformed based on their type. The following types are
+// supported:
+//
+//   - bool are transformed to [attribute.BoolValue].
+//   - string are transformed to [attribute.StringValue].
+//   - int, int8, int16, int32, int64 are transformed to
+//     [attribute.Int64Value].
+//   - uint, uint8, uint16, uint32, uint64, uintptr are transformed
+//     to [attribute.Int64Value] or [attribute.StringValue] if the value is too large.
+//   - float32, float64 are transformed to [attribute.Float64Value].
+//   - [time.Duration] are transformed to [attribute.Int64Value] with the nanoseconds.
+//   - complex64, complex128 are transformed to [attribute.MapValue] with the keys
+//     "r" and "i" for the real and imaginary parts. The values are
+//     [attribute.Float64Value].
+//   - [time.Time] are transformed to [attribute.Int64Value] with the nanoseconds.
+//   - []byte are transformed to [attribute.ByteSliceValue].
+//   - error are transformed to [attribute.StringValue] with the error message.
+//   - nil are transformed to an empty [attribute.Value].
+//   - struct are transformed to [attribute.StringValue] with the struct fields.
+//   - slice, array are transformed to [attribute.SliceValue] with the elements.
+//   - map are transformed to [attribute.MapValue] with the key-value pairs.
+//   - pointer, interface are transformed to the dereferenced value.
+//
+// [OpenTelemetry]: https://opentelemetry.io/docs/concepts/signals/logs/
+package otelgokit
+
+import (
+	"context"
+	"encoding"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	kitlog "github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+)
+
+const (
+	timestampKey = "ts"
+	callerKey    = "caller"
+	errorKey     = "err"
+)
+
+type config struct {
+	provider   log.LoggerProvider
+	version    string
+	schemaURL  string
+	attributes []attribute.KeyValue
+
+	messageKey string
+}
+
+func newConfig(options []Option) config {
+	c := config{messageKey: "msg"}
+	for _, opt := range options {
+		c = opt.apply(c)
+	}
+
+	if c.provider == nil {
+		c.provider = global.GetLoggerProvider()
+	}
+
+	return c
+}
+
+// Option configures a [Logger].
+type Option interface {
+	apply(config) config
+}
+
+type optFunc func(config) config
+
+func (f optFunc) apply(c config) config { return f(c) }
+
+// WithVersion returns an [Option] that configures the version of the
+// [log.Logger] used by a [Logger]. The version should be the version of the
+// package that is being logged.
+func WithVersion(version string) Option {
+	return optFunc(func(c config) config {
+		c.version = version
+		return c
+	})
+}
+
+// WithSchemaURL returns an [Option] that configures the semantic convention
+// schema URL of the [log.Logger] used by a [Logger]. The schemaURL should be
+// the schema URL for the semantic conventions used in log records.
+func WithSchemaURL(schemaURL string) Option {
+	return optFunc(func(c config) config {
+		c.schemaURL = schemaURL
+		return c
+	})
+}
+
+// WithAttributes returns an [Option] that configures the instrumentation scope
+// attributes of the [log.Logger] used by a [Logger].
+func WithAttributes(attributes ...attribute.KeyValue) Option {
+	return optFunc(func(c config) config {
+		c.attributes = attributes
+		return c
+	})
+}
+
+// WithLoggerProvider returns an [Option] that configures [log.LoggerProvider]
+// used by a [Logger] to create its [log.Logger].
+//
+// By default if this Option is not provided, the Logger will use the global
+// LoggerProvider.
+func WithLoggerProvider(provider log.LoggerProvider) Option {
+	return optFunc(func(c config) config {
+		c.provider = provider
+		return c
+	})
+}
+
+// WithMessageKey returns an [Option] that configures the key whose value is
+// set as the Body of the log records emitted by a [Logger].
+//
+// By default if this Option is not provided, the "msg" key is used.
+func WithMessageKey(key string) Option {
+	return optFunc(func(c config) config {
+		c.messageKey = key
+		return c
+	})
+}
+
+// NewLogger returns a new [Logger] to be used as a
+// [github.com/go-kit/log.Logger].
+//
+// If [WithLoggerProvider] is not provided, the returned [Logger] will use the
+// global LoggerProvider.
+func NewLogger(name string, options ...Option) *Logger {
+	c := newConfig(options)
+
+	var opts []log.LoggerOption
+	if c.version != "" {
+		opts = append(opts, log.WithInstrumentationVersion(c.version))
+	}
+	if c.schemaURL != "" {
+		opts = append(opts, log.WithSchemaURL(c.schemaURL))
+	}
+	if c.attributes != nil {
+		opts = append(opts, log.WithInstrumentationAttributes(c.attributes...))
+	}
+
+	return &Logger{
+		logger:     c.provider.Logger(name, opts...),
+		messageKey: c.messageKey,
+	}
+}
+
+// Logger is a [github.com/go-kit/log.Logger] that sends all log events it
+// receives to OpenTelemetry. See package documentation for how conversions
+// are made.
+type Logger struct {
+	// Ensure forward compatibility by explicitly making this not comparable.
+	noCmp [0]func() //nolint:unused  // This is indeed used.
+
+	logger     log.Logger
+	messageKey string
+}
+
+// Compile-time check *Logger implements kitlog.Logger.
+var _ kitlog.Logger = (*Logger)(nil)
+
+// Log converts keyvals to a log record and sends it to OpenTelemetry. It
+// always returns nil.
+func (l *Logger) Log(keyvals ...any) error {
+	ctx, record := l.convertKVs(keyvals)
+	if l.logger.Enabled(ctx, log.EnabledParameters{Severity: record.Severity()}) {
+		l.logger.Emit(ctx, record)
+	}
+	return nil
+}
+
+// convertKVs converts a list of key-value pairs to a log record. The last
+// [context.Context] value is returned as the context.
+func (l *Logger) convertKVs(keyvals []any) (context.Context, log.Record) {
+	if len(keyvals)%2 != 0 {
+		// Follow the go-kit convention for an odd number of items.
+		keyvals = append(keyvals, kitlog.ErrMissingValue)
+	}
+
+	ctx := context.Background()
+	var record log.Record
+	kvs := make([]attribute.KeyValue, 0, len(keyvals)/2)
+	for i := 0; i < len(keyvals); i += 2 {
+		k, ok := keyvals[i].(string)
+		if !ok {
+			// Ensure that the key is a string.
+			k = fmt.Sprintf("%v", keyvals[i])
+		}
+
+		switch v := keyvals[i+1].(type) {
+		case context.Context:
+			// Special case when a value is of context.Context type.
+			ctx = v
+			continue
+		case level.Value:
+			record.SetSeverity(convertLevel(v))
+			record.SetSeverityText(v.String())
+			continue
+		case string:
+			switch k {
+			case l.messageKey:
+				record.SetBody(attribute.StringValue(v))
+				continue
+			case level.Key():
+				if lvl, err := level.Parse(v); err == nil {
+					record.SetSeverity(convertLevel(lvl))
+					record.SetSeverityText(v)
+					continue
+				}
+			case callerKey:
+				kvs = append(kvs, callerAttributes(v)...)
+				continue
+			}
+		case time.Time:
+			if k == timestampKey {
+				record.SetTimestamp(v)
+				continue
+			}
+		case error:
+			if k == errorKey {
+				record.SetErr(v)
+				continue
+			}
+		case fmt.Stringer, encoding.TextMarshaler:
+			// The timestamps of kitlog.DefaultTimestamp and
+			// kitlog.DefaultTimestampUTC are formatted with time.RFC3339Nano.
+			if k == timestampKey {
+				if ts, ok := parseTimestamp(v); ok {
+					record.SetTimestamp(ts)
+					continue
+				}
+			}
+		}
+
+		kvs = append(kvs, attribute.KeyValue{
+			Key:   attribute.Key(k),
+			Value: convertValue(keyvals[i+1]),
+		})
+	}
+	record.AddAttributes(kvs...)
+
+	return ctx, record
+}
+
+// parseTimestamp returns the time of a timestamp value formatted with
+// [time.RFC3339Nano].
+func parseTimestamp(v any) (time.Time, bool) {
+	var text string
+	switch v := v.(type) {
+	case encoding.TextMarshaler:
+		b, err := v.MarshalText()
+		if err != nil {
+			return time.Time{}, false
+		}
+		text = string(b)
+	case fmt.Stringer:
+		text = v.String()
+	}
+	ts, err := time.Parse(time.RFC3339Nano, text)
+	return ts, err == nil
+}
+
+// callerAttributes returns the code attributes for a caller formatted by
+// [github.com/go-kit/log.Caller] as file:line.
+func callerAttributes(caller string) []attribute.KeyValue {
+	if i := strings.LastIndexByte(caller, ':'); i > 0 {
+		if line, err := strconv.Atoi(caller[i+1:]); err == nil {
+			return []attribute.KeyValue{
+				attribute.String(string(semconv.CodeFilePathKey), caller[:i]),
+				attribute.Int(string(semconv.CodeLineNumberKey), line),
+			}
+		}
+	}
+	return []attribute.KeyValue{attribute.String(string(semconv.CodeFilePathKey), caller)}
+}
+
+func convertLevel(lvl level.Value) log.Severity {
+	switch lvl {
+	case level.DebugValue():
+		return log.SeverityDebug
+	case level.InfoValue():
+		return log.SeverityInfo
+	case level.WarnValue():
+		return log.SeverityWarn
+	case level.ErrorValue():
+		return log.SeverityError
+	default:
+		return log.SeverityUndefined
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelgokit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	kitlog "github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+	"go.opentelemetry.io/otel/log/logtest"
+)
+
+type ctxKey struct{}
+
+func TestNewConfig(t *testing.T) {
+	assert.Equal(t, config{
+		provider:   global.GetLoggerProvider(),
+		messageKey: "msg",
+	}, newConfig(nil))
+
+	assert.Equal(t, config{
+		provider:   global.GetLoggerProvider(),
+		version:    "42.0",
+		messageKey: "message",
+	}, newConfig([]Option{WithVersion("42.0"), WithMessageKey("message")}))
+}
+
+func TestNewLogger(t *testing.T) {
+	const name = "name"
+	provider := global.GetLoggerProvider()
+
+	l := NewLogger(name,
+		WithVersion("42.1"),
+		WithSchemaURL("https://example.com"),
+		WithAttributes(attribute.String("testattr", "testval")),
+	)
+	want := provider.Logger(
+		name,
+		log.WithInstrumentationVersion("42.1"),
+		log.WithSchemaURL("https://example.com"),
+		log.WithInstrumentationAttributes(attribute.String("testattr", "testval")),
+	)
+	assert.Equal(t, want, l.logger)
+}
+
+func TestLoggerLog(t *testing.T) {
+	const name = "name"
+	now := time.Now()
+	errTest := errors.New("test error")
+	ctx := context.WithValue(t.Context(), ctxKey{}, "value")
+
+	for _, tt := range []struct {
+		name    string
+		options []Option
+		log     func(kitlog.Logger) error
+
+		want logtest.Recording
+	}{
+		{
+			name: "message without level",
+			log: func(l kitlog.Logger) error {
+				return l.Log("msg", "hello", "key", "value")
+			},
+			want: logtest.Recording{
+				logtest.Scope{Name: name}: {{
+					Context:    context.Background(),
+					Body:       attribute.StringValue("hello"),
+					Attributes: []attribute.KeyValue{attribute.String("key", "value")},
+				}},
+			},
+		},
+		{
+			name: "level value",
+			log: func(l kitlog.Logger) error {
+				return level.Warn(l).Log("msg", "hello")
+			},
+			want: logtest.Recording{
+				logtest.Scope{Name: name}: {{
+					Context:      context.Background(),
+					Severity:     log.SeverityWarn,
+					SeverityText: "warn",
+					Body:         attribute.StringValue("hello"),
+				}},
+			},
+		},
+		{
+			name: "level string",
+			log: func(l kitlog.Logger) error {
+				return l.Log("level", "debug", "msg", "hello")
+			},
+			want: logtest.Recording{
+				logtest.Scope{Name: name}: {{
+					Context:      context.Background(),
+					Severity:     log.SeverityDebug,
+					SeverityText: "debug",
+					Body:         attribute.StringValue("hello"),
+				}},
+			},
+		},
+		{
+			name: "unknown level string",
+			log: func(l kitlog.Logger) error {
+				return l.Log("level", "verbose")
+			},
+			want: logtest.Recording{
+				logtest.Scope{Name: name}: {{
+					Context:    context.Background(),
+					Attributes: []attribute.KeyValue{attribute.String("level", "verbose")},
+				}},
+			},
+		},
+		{
+			name: "timestamp, caller, error and context",
+			log: func(l kitlog.Logger) error {
+				return level.Error(l).Log(
+					"ts", now,
+					"caller", "main.go:42",
+					"err", errTest,
+					"ctx", ctx,
+					"msg", "failed",
+				)
+			},
+			want: logtest.Recording{
+				logtest.Scope{Name: name}: {{
+					Context:      ctx,
+					Timestamp:    now,
+					Severity:     log.SeverityError,
+					SeverityText: "error",
+					Body:         attribute.StringValue("failed"),
+					Error:        errTest,
+					Attributes: []attribute.KeyValue{
+						attribute.String("code.file.path", "main.go"),
+						attribute.Int("code.line.number", 42),
+					},
+				}},
+			},
+		},
+		{
+			name: "timestamp string",
+			log: func(l kitlog.Logger) error {
+				return l.Log("ts", "yesterday")
+			},
+			want: logtest.Recording{
+				logtest.Scope{Name: name}: {{
+					Context:    context.Background(),
+					Attributes: []attribute.KeyValue{attribute.String("ts", "yesterday")},
+				}},
+			},
+		},
+		{
+			name:    "custom message key",
+			options: []Option{WithMessageKey("message")},
+			log: func(l kitlog.Logger) error {
+				return l.Log("message", "hello", "msg", "other")
+			},
+			want: logtest.Recording{
+				logtest.Scope{Name: name}: {{
+					Context:    context.Background(),
+					Body:       attribute.StringValue("hello"),
+					Attributes: []attribute.KeyValue{attribute.String("msg", "other")},
+				}},
+			},
+		},
+		{
+			name: "odd number of keyvals and non-string key",
+			log: func(l kitlog.Logger) error {
+				return l.Log(1, 2, "missing")
+			},
+			want: logtest.Recording{
+				logtest.Scope{Name: name}: {{
+					Context: context.Background(),
+					Attributes: []attribute.KeyValue{
+						attribute.Int("1", 2),
+						attribute.String("missing", "(MISSING)"),
+					},
+				}},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := logtest.NewRecorder()
+			l := NewLogger(name, append(tt.options, WithLoggerProvider(rec))...)
+
+			require.NoError(t, tt.log(l))
+			logtest.AssertEqual(t, tt.want, rec.Result())
+		})
+	}
+}
+
+func TestLoggerEnabled(t *testing.T) {
+	rec := logtest.NewRecorder(logtest.WithEnabledFunc(func(_ context.Context, p log.EnabledParameters) bool {
+		return p.Severity >= log.SeverityWarn
+	}))
+	l := NewLogger("name", WithLoggerProvider(rec))
+
+	require.NoError(t, level.Info(l).Log("msg", "dropped"))
+	require.NoError(t, level.Error(l).Log("msg", "emitted"))
+
+	records := rec.Result()[logtest.Scope{Name: "name"}]
+	require.Len(t, records, 1)
+	assert.Equal(t, attribute.StringValue("emitted"), records[0].Body)
+}
+
+func TestLoggerDefaultTimestamp(t *testing.T) {
+	rec := logtest.NewRecorder()
+	l := kitlog.With(NewLogger("name", WithLoggerProvider(rec)), "ts", kitlog.DefaultTimestampUTC)
+
+	before := time.Now()
+	require.NoError(t, l.Log("msg", "hello"))
+	after := time.Now()
+
+	records := rec.Result()[logtest.Scope{Name: "name"}]
+	require.Len(t, records, 1)
+	assert.WithinRange(t, records[0].Timestamp, before, after)
+	assert.Empty(t, records[0].Attributes, "the timestamp is not an attribute")
+}
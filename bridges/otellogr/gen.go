@@ -6,3 +6,11 @@ package otellogr
 // Generate convert:
 //go:generate gotmpl --body=../../internal/shared/logutil/convert_test.go.tmpl "--data={ \"pkg\": \"otellogr\" }" --out=convert_test.go
 //go:generate gotmpl --body=../../internal/shared/logutil/convert.go.tmpl "--data={ \"pkg\": \"otellogr\" }" --out=convert.go
+
+// Generate span events:
+//go:generate gotmpl --body=../../internal/shared/logutil/spanevent_test.go.tmpl "--data={ \"pkg\": \"otellogr\" }" --out=spanevent_test.go
+//go:generate gotmpl --body=../../internal/shared/logutil/spanevent.go.tmpl "--data={ \"pkg\": \"otellogr\" }" --out=spanevent.go
+
+// Generate record policy:
+//go:generate gotmpl --body=../../internal/shared/logutil/policy_test.go.tmpl "--data={ \"pkg\": \"otellogr\" }" --out=policy_test.go
+//go:generate gotmpl --body=../../internal/shared/logutil/policy.go.tmpl "--data={ \"pkg\": \"otellogr\" }" --out=policy.go
@@ -9,6 +9,7 @@
 // The logr records are converted to OpenTelemetry [log.Record] in the following
 // way:
 //
+//   - The time of the call is set as the Timestamp.
 //   - Message is set as the Body using an [attribute.StringValue].
 //   - Level is transformed and set as the Severity. The SeverityText is not
 //     set.
@@ -19,6 +20,10 @@
 //     log record. All non-nested [context.Context] values are ignored and not
 //     added as attributes. If there are multiple [context.Context] the last one
 //     is used.
+//   - If the [WithSource] option is provided, the location of the logging call
+//     is set as the code.file.path, code.line.number, and code.function.name
+//     attributes. The call depth provided with [logr.RuntimeInfo] and
+//     [logr.Logger.WithCallDepth] is taken into account.
 //
 // The V-level is transformed by using the [WithLevelSeverity] option. If option is
 // not provided then V-level is transformed in the following way:
@@ -50,17 +55,24 @@
 //   - map are transformed to [attribute.MapValue] with the key-value pairs.
 //   - pointer, interface are transformed to the dereferenced value.
 //
+// The attribute keys are then renamed as configured with [WithKeyRenames],
+// and the record is passed to the function configured with [WithEnricher]
+// before it is emitted.
+//
 // [OpenTelemetry]: https://opentelemetry.io/docs/concepts/signals/logs/
 package otellogr
 
 import (
 	"context"
 	"fmt"
+	"runtime"
+	"time"
 
 	"github.com/go-logr/logr"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/log"
 	"go.opentelemetry.io/otel/log/global"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
 )
 
 type config struct {
@@ -70,6 +82,12 @@ type config struct {
 	attributes []attribute.KeyValue
 
 	levelSeverity func(int) log.Severity
+	source        bool
+	policy        recordPolicy
+
+	spanEvents           bool
+	spanEventMinSeverity log.Severity
+	spanEventMaxPerSpan  int
 }
 
 func newConfig(options []Option) config {
@@ -164,6 +182,59 @@ func WithLevelSeverity(f func(int) log.Severity) Option {
 	})
 }
 
+// WithEnricher returns an [Option] that configures a function called with
+// the context of every record emitted by a [LogSink] before it is emitted, e.g.
+// to add tenant or request attributes from the baggage of the context. The
+// function is called after the attribute keys are renamed with
+// [WithKeyRenames].
+//
+// By default if this Option is not provided, the records are not enriched.
+func WithEnricher(f func(ctx context.Context, record *log.Record)) Option {
+	return optFunc(func(c config) config {
+		c.policy.enrich = f
+		return c
+	})
+}
+
+// WithKeyRenames returns an [Option] that configures the [LogSink] to rename
+// the keys of the attributes of the records it emits. Each key of renames is
+// replaced with its value. Only the keys of the top-level attributes are
+// renamed.
+//
+// By default if this Option is not provided, the keys are not renamed.
+func WithKeyRenames(renames map[string]string) Option {
+	return optFunc(func(c config) config {
+		c.policy.renames = renames
+		return c
+	})
+}
+
+// WithSource returns an [Option] that configures the [LogSink] to include
+// the source location of the logging call in log attributes.
+func WithSource(source bool) Option {
+	return optFunc(func(c config) config {
+		c.source = source
+		return c
+	})
+}
+
+// WithSpanEvents returns an [Option] that configures the [LogSink] to also add
+// the log records with a severity of at least minSeverity as events to the
+// recording span of the context they are logged with. The name of an event is
+// the body of the record, and its attributes are the severity, the error, and
+// the attributes of the record. At most maxPerSpan events are added to a
+// span, or an unlimited number if maxPerSpan is not positive.
+//
+// By default if this Option is not provided, no span events are added.
+func WithSpanEvents(minSeverity log.Severity, maxPerSpan int) Option {
+	return optFunc(func(c config) config {
+		c.spanEvents = true
+		c.spanEventMinSeverity = minSeverity
+		c.spanEventMaxPerSpan = maxPerSpan
+		return c
+	})
+}
+
 // NewLogSink returns a new [LogSink] to be used as a [logr.LogSink].
 //
 // If [WithLoggerProvider] is not provided, the returned [LogSink] will use the
@@ -182,14 +253,20 @@ func NewLogSink(name string, options ...Option) *LogSink {
 		opts = append(opts, log.WithInstrumentationAttributes(c.attributes...))
 	}
 
-	return &LogSink{
+	l := &LogSink{
 		name:          name,
 		provider:      c.provider,
 		logger:        c.provider.Logger(name, opts...),
 		levelSeverity: c.levelSeverity,
+		source:        c.source,
+		policy:        c.policy,
 		opts:          opts,
 		ctx:           context.Background(),
 	}
+	if c.spanEvents {
+		l.spanEvents = newSpanEvents(c.spanEventMinSeverity, c.spanEventMaxPerSpan)
+	}
+	return l
 }
 
 // LogSink is a [logr.LogSink] that sends all logging records it receives to
@@ -202,13 +279,21 @@ type LogSink struct {
 	provider      log.LoggerProvider
 	logger        log.Logger
 	levelSeverity func(int) log.Severity
+	source        bool
+	callDepth     int
+	policy        recordPolicy
 	opts          []log.LoggerOption
 	attr          []attribute.KeyValue
 	ctx           context.Context
+	spanEvents    *spanEvents
 }
 
-// Compile-time check *Handler implements logr.LogSink.
-var _ logr.LogSink = (*LogSink)(nil)
+// Compile-time check *LogSink implements logr.LogSink and
+// logr.CallDepthLogSink.
+var (
+	_ logr.LogSink          = (*LogSink)(nil)
+	_ logr.CallDepthLogSink = (*LogSink)(nil)
+)
 
 // Enabled tests whether this LogSink is enabled at the specified V-level.
 // For example, commandline flags might be used to set the logging
@@ -222,38 +307,74 @@ func (l *LogSink) Enabled(level int) bool {
 // Error logs an error, with the given message and key/value pairs.
 func (l *LogSink) Error(err error, msg string, keysAndValues ...any) {
 	var record log.Record
+	record.SetTimestamp(time.Now())
 	record.SetBody(attribute.StringValue(msg))
 	record.SetSeverity(log.SeverityError)
 	record.SetErr(err)
+	if l.source {
+		l.addSource(&record)
+	}
 
 	record.AddAttributes(l.attr...)
 
 	ctx, attr := convertKVs(l.ctx, keysAndValues...)
 	record.AddAttributes(attr...)
+	record = l.policy.apply(ctx, record)
 
 	l.logger.Emit(ctx, record)
+	l.spanEvents.add(ctx, record)
 }
 
 // Info logs a non-error message with the given key/value pairs.
 func (l *LogSink) Info(level int, msg string, keysAndValues ...any) {
 	var record log.Record
+	record.SetTimestamp(time.Now())
 	record.SetBody(attribute.StringValue(msg))
 	record.SetSeverity(l.levelSeverity(level))
+	if l.source {
+		l.addSource(&record)
+	}
 
 	record.AddAttributes(l.attr...)
 
 	ctx, attr := convertKVs(l.ctx, keysAndValues...)
 	record.AddAttributes(attr...)
+	record = l.policy.apply(ctx, record)
 
 	l.logger.Emit(ctx, record)
+	l.spanEvents.add(ctx, record)
+}
+
+// addSource adds the source location of the logging call to record. It needs
+// to be called directly by Info or Error.
+func (l *LogSink) addSource(record *log.Record) {
+	var pcs [1]uintptr
+	// Skip runtime.Callers, addSource, the LogSink method, and the logr
+	// frames.
+	if runtime.Callers(3+l.callDepth, pcs[:]) == 0 {
+		return
+	}
+	fs := runtime.CallersFrames(pcs[:])
+	f, _ := fs.Next()
+	record.AddAttributes(
+		attribute.String(string(semconv.CodeFilePathKey), f.File),
+		attribute.String(string(semconv.CodeFunctionNameKey), f.Function),
+		attribute.Int(string(semconv.CodeLineNumberKey), f.Line),
+	)
+}
+
+// Init receives optional information about the logr library. The CallDepth
+// is used to find the location of the logging call when [WithSource] is
+// provided.
+func (l *LogSink) Init(info logr.RuntimeInfo) {
+	l.callDepth = info.CallDepth
 }
 
-// Init receives optional information about the logr library this
-// implementation does not use it.
-func (*LogSink) Init(logr.RuntimeInfo) {
-	// We don't need to do anything here.
-	// CallDepth is used to calculate the caller's PC.
-	// PC is dropped as part of the conversion to the OpenTelemetry log.Record.
+// WithCallDepth returns a new LogSink that offsets the call stack by the
+// specified number of frames when finding the location of the logging call.
+func (l LogSink) WithCallDepth(depth int) logr.LogSink {
+	l.callDepth += depth
+	return &l
 }
 
 // WithName returns a new LogSink with the specified name appended.
@@ -6,16 +6,21 @@ package otellogr
 import (
 	"context"
 	"errors"
+	"runtime"
 	"testing"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
 	"go.opentelemetry.io/otel/log"
 	"go.opentelemetry.io/otel/log/embedded"
 	"go.opentelemetry.io/otel/log/global"
 	"go.opentelemetry.io/otel/log/logtest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type mockLoggerProvider struct {
@@ -367,7 +372,8 @@ func TestLogSink(t *testing.T) {
 			logtest.AssertEqual(
 				t, tt.want, rec.Result(),
 				logtest.Transform(func(r logtest.Record) logtest.Record {
-					r.Context = nil // Ignore context for comparison.
+					r.Context = nil           // Ignore context for comparison.
+					r.Timestamp = time.Time{} // Ignore timestamp for comparison.
 					return r
 				}),
 			)
@@ -429,6 +435,60 @@ func TestLogSinkContext(t *testing.T) {
 	}
 }
 
+func TestLogSinkTimestamp(t *testing.T) {
+	rec := logtest.NewRecorder()
+	l := logr.New(NewLogSink("name", WithLoggerProvider(rec)))
+
+	before := time.Now()
+	l.Info("info")
+	l.Error(errors.New("test"), "error")
+	after := time.Now()
+
+	records := rec.Result()[logtest.Scope{Name: "name"}]
+	require.Len(t, records, 2)
+	for _, r := range records {
+		assert.False(t, r.Timestamp.Before(before), "timestamp before the call")
+		assert.False(t, r.Timestamp.After(after), "timestamp after the call")
+	}
+}
+
+func logHelper(l logr.Logger, msg string) {
+	l.WithCallDepth(1).Info(msg)
+}
+
+func TestLogSinkSource(t *testing.T) {
+	rec := logtest.NewRecorder()
+	l := logr.New(NewLogSink("name", WithLoggerProvider(rec), WithSource(true)))
+
+	_, file, line, _ := runtime.Caller(0)
+	l.Info("info")
+	l.Error(errors.New("test"), "error")
+	logHelper(l, "helper")
+	l.WithName("child").WithValues("key", "value").Info("child")
+
+	records := rec.Result()[logtest.Scope{Name: "name"}]
+	require.Len(t, records, 3)
+	records = append(records, rec.Result()[logtest.Scope{Name: "name/child"}]...)
+	require.Len(t, records, 4)
+
+	const funcName = "go.opentelemetry.io/contrib/bridges/otellogr.TestLogSinkSource"
+	for i, r := range records {
+		assert.Contains(t, r.Attributes, attribute.String(string(semconv.CodeFilePathKey), file), "record %d", i)
+		assert.Contains(t, r.Attributes, attribute.String(string(semconv.CodeFunctionNameKey), funcName), "record %d", i)
+		assert.Contains(t, r.Attributes, attribute.Int(string(semconv.CodeLineNumberKey), line+1+i), "record %d", i)
+	}
+}
+
+func TestLogSinkNoSource(t *testing.T) {
+	rec := logtest.NewRecorder()
+	l := logr.New(NewLogSink("name", WithLoggerProvider(rec)))
+	l.Info("info")
+
+	records := rec.Result()[logtest.Scope{Name: "name"}]
+	require.Len(t, records, 1)
+	assert.Empty(t, records[0].Attributes)
+}
+
 func TestLogSinkEnabled(t *testing.T) {
 	enabledFunc := func(_ context.Context, param log.EnabledParameters) bool {
 		return param.Severity == log.SeverityInfo
@@ -515,3 +575,54 @@ func TestConvertKVs(t *testing.T) {
 		})
 	}
 }
+
+func TestLogSinkSpanEvents(t *testing.T) {
+	span := &eventSpan{id: trace.SpanID{0x01}}
+	ctx := trace.ContextWithSpan(t.Context(), span)
+
+	rec := logtest.NewRecorder()
+	ls := NewLogSink("name", WithLoggerProvider(rec), WithSpanEvents(log.SeverityInfo, 0))
+	l := logr.New(ls).WithValues("ctx", ctx)
+	l.V(1).Info("debug")
+	l.Info("info", "key", "value")
+	l.Error(errors.New("test error"), "error")
+
+	require.Len(t, span.events, 2)
+	assert.Equal(t, "info", span.events[0].name)
+	assert.Contains(t, span.events[0].config.Attributes(), attribute.String("key", "value"))
+	assert.Equal(t, "error", span.events[1].name)
+	assert.Contains(t, span.events[1].config.Attributes(), attribute.String("exception.message", "test error"))
+}
+
+func TestLogSinkPolicy(t *testing.T) {
+	member, err := baggage.NewMember("tenant", "acme")
+	require.NoError(t, err)
+	bag, err := baggage.New(member)
+	require.NoError(t, err)
+	ctx := baggage.ContextWithBaggage(t.Context(), bag)
+
+	rec := logtest.NewRecorder()
+	ls := NewLogSink("name",
+		WithLoggerProvider(rec),
+		WithEnricher(func(ctx context.Context, r *log.Record) {
+			b := baggage.FromContext(ctx)
+			r.AddAttributes(attribute.String("tenant.id", b.Member("tenant").Value()))
+		}),
+		WithKeyRenames(map[string]string{"user": "user.name"}),
+	)
+	l := logr.New(ls).WithValues("ctx", ctx, "user", "alice")
+	l.Info("info", "key", "value")
+	l.Error(errors.New("test"), "error")
+
+	records := rec.Result()[logtest.Scope{Name: "name"}]
+	require.Len(t, records, 2)
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("user.name", "alice"),
+		attribute.String("key", "value"),
+		attribute.String("tenant.id", "acme"),
+	}, records[0].Attributes)
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("user.name", "alice"),
+		attribute.String("tenant.id", "acme"),
+	}, records[1].Attributes)
+}
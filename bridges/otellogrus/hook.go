@@ -16,8 +16,14 @@
 //   - Fields are transformed and set as the attributes.
 //   - A field with key [logrus.ErrorKey] and an [error] value is set using
 //     [log.Record.SetErr].
+//   - If the [WithSource] option is provided, the location of the logging call
+//     is set as the code.file.path, code.line.number, and code.function.name
+//     attributes. The Caller of the entry is used if the [logrus.Logger] has
+//     ReportCaller set, otherwise the location is found by skipping the logrus
+//     frames of the call stack.
 //
-// The Level is transformed to the OpenTelemetry
+// The Level is transformed by using the [WithLevelSeverity] option. If the
+// option is not provided then the Level is transformed to the OpenTelemetry
 // Severity types. For example:
 //
 //   - [logrus.DebugLevel] is transformed to [log.SeverityDebug]
@@ -30,14 +36,23 @@
 // Field values are transformed based on their type into log attributes, or
 // into a string value encoded using [fmt.Sprintf] if there is no matching type.
 //
+// The attribute keys are then renamed as configured with [WithKeyRenames],
+// and the record is passed to the function configured with [WithEnricher]
+// before it is emitted.
+//
 // [OpenTelemetry]: https://opentelemetry.io/docs/concepts/signals/logs/
 package otellogrus
 
 import (
+	"context"
+	"runtime"
+	"strings"
+
 	"github.com/sirupsen/logrus"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/log"
 	"go.opentelemetry.io/otel/log/global"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
 )
 
 type config struct {
@@ -47,6 +62,14 @@ type config struct {
 	attributes []attribute.KeyValue
 
 	levels []logrus.Level
+	source bool
+
+	levelSeverity func(logrus.Level) log.Severity
+	policy        recordPolicy
+
+	spanEvents           bool
+	spanEventMinSeverity log.Severity
+	spanEventMaxPerSpan  int
 }
 
 func newConfig(options []Option) config {
@@ -63,6 +86,10 @@ func newConfig(options []Option) config {
 		c.levels = logrus.AllLevels
 	}
 
+	if c.levelSeverity == nil {
+		c.levelSeverity = convertSeverity
+	}
+
 	return c
 }
 
@@ -142,23 +169,101 @@ func WithLevels(l []logrus.Level) Option {
 	})
 }
 
+// WithLevelSeverity returns an [Option] that configures the function used to
+// convert logrus levels to OpenTelemetry log severities.
+//
+// By default if this Option is not provided, the Hook will use a default
+// conversion function. See the package documentation for the conversion.
+func WithLevelSeverity(f func(logrus.Level) log.Severity) Option {
+	return optFunc(func(c config) config {
+		c.levelSeverity = f
+		return c
+	})
+}
+
+// WithEnricher returns an [Option] that configures a function called with
+// the context of every record emitted by a [Hook] before it is emitted, e.g.
+// to add tenant or request attributes from the baggage of the context. The
+// function is called after the attribute keys are renamed with
+// [WithKeyRenames].
+//
+// By default if this Option is not provided, the records are not enriched.
+func WithEnricher(f func(ctx context.Context, record *log.Record)) Option {
+	return optFunc(func(c config) config {
+		c.policy.enrich = f
+		return c
+	})
+}
+
+// WithKeyRenames returns an [Option] that configures the [Hook] to rename
+// the keys of the attributes of the records it emits. Each key of renames is
+// replaced with its value. Only the keys of the top-level attributes are
+// renamed.
+//
+// By default if this Option is not provided, the keys are not renamed.
+func WithKeyRenames(renames map[string]string) Option {
+	return optFunc(func(c config) config {
+		c.policy.renames = renames
+		return c
+	})
+}
+
+// WithSource returns an [Option] that configures the [Hook] to include the
+// source location of the logging call in log attributes.
+func WithSource(source bool) Option {
+	return optFunc(func(c config) config {
+		c.source = source
+		return c
+	})
+}
+
+// WithSpanEvents returns an [Option] that configures the [Hook] to also add
+// the log records with a severity of at least minSeverity as events to the
+// recording span of the context they are logged with. The name of an event is
+// the body of the record, and its attributes are the severity, the error, and
+// the attributes of the record. At most maxPerSpan events are added to a
+// span, or an unlimited number if maxPerSpan is not positive.
+//
+// By default if this Option is not provided, no span events are added.
+func WithSpanEvents(minSeverity log.Severity, maxPerSpan int) Option {
+	return optFunc(func(c config) config {
+		c.spanEvents = true
+		c.spanEventMinSeverity = minSeverity
+		c.spanEventMaxPerSpan = maxPerSpan
+		return c
+	})
+}
+
 // NewHook returns a new [Hook] to be used as a [logrus.Hook].
 //
 // If [WithLoggerProvider] is not provided, the returned Hook will use the
 // global LoggerProvider.
 func NewHook(name string, options ...Option) *Hook {
 	cfg := newConfig(options)
-	return &Hook{
+	h := &Hook{
 		logger: cfg.logger(name),
 		levels: cfg.levels,
+		source: cfg.source,
+
+		levelSeverity: cfg.levelSeverity,
+		policy:        cfg.policy,
 	}
+	if cfg.spanEvents {
+		h.spanEvents = newSpanEvents(cfg.spanEventMinSeverity, cfg.spanEventMaxPerSpan)
+	}
+	return h
 }
 
 // Hook is a [logrus.Hook] that sends all logging records it receives to
 // OpenTelemetry. See package documentation for how conversions are made.
 type Hook struct {
-	logger log.Logger
-	levels []logrus.Level
+	logger     log.Logger
+	levels     []logrus.Level
+	source     bool
+	spanEvents *spanEvents
+
+	levelSeverity func(logrus.Level) log.Severity
+	policy        recordPolicy
 }
 
 // Levels returns the list of log levels we want to be sent to OpenTelemetry.
@@ -169,17 +274,33 @@ func (h *Hook) Levels() []logrus.Level {
 // Fire handles the passed record, and sends it to OpenTelemetry.
 func (h *Hook) Fire(entry *logrus.Entry) error {
 	ctx := entry.Context
-	h.logger.Emit(ctx, h.convertEntry(entry))
+	record := h.policy.apply(ctx, h.convertEntry(entry))
+	h.logger.Emit(ctx, record)
+	h.spanEvents.add(ctx, record)
 	return nil
 }
 
-func (*Hook) convertEntry(e *logrus.Entry) log.Record {
+func (h *Hook) convertEntry(e *logrus.Entry) log.Record {
 	var record log.Record
 	record.SetTimestamp(e.Time)
 	record.SetBody(attribute.StringValue(e.Message))
-	record.SetSeverity(convertSeverity(e.Level))
+	record.SetSeverity(h.levelSeverity(e.Level))
 	record.SetSeverityText(e.Level.String())
 
+	if h.source {
+		f := e.Caller
+		if f == nil {
+			f = caller()
+		}
+		if f != nil {
+			record.AddAttributes(
+				attribute.String(string(semconv.CodeFilePathKey), f.File),
+				attribute.String(string(semconv.CodeFunctionNameKey), f.Function),
+				attribute.Int(string(semconv.CodeLineNumberKey), f.Line),
+			)
+		}
+	}
+
 	attrs, err := convertFields(e.Data)
 	if err != nil {
 		record.SetErr(err)
@@ -189,6 +310,43 @@ func (*Hook) convertEntry(e *logrus.Entry) log.Record {
 	return record
 }
 
+const (
+	logrusPackage = "github.com/sirupsen/logrus"
+	hookPrefix    = "go.opentelemetry.io/contrib/bridges/otellogrus.(*Hook)."
+)
+
+// maxCallerDepth is the maximum number of frames inspected to find the
+// location of the logging call.
+const maxCallerDepth = 32
+
+// caller returns the first frame of the call stack that is not part of the
+// logrus package or of a Hook method, or nil if none is found.
+func caller() *runtime.Frame {
+	var pcs [maxCallerDepth]uintptr
+	// Skip runtime.Callers and caller.
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+		if packageName(f.Function) != logrusPackage && !strings.HasPrefix(f.Function, hookPrefix) {
+			return &f
+		}
+		if !more {
+			return nil
+		}
+	}
+}
+
+// packageName returns the package path of the fully qualified function name
+// f.
+func packageName(f string) string {
+	i := strings.LastIndexByte(f, '/')
+	if j := strings.IndexByte(f[i+1:], '.'); j >= 0 {
+		return f[:i+1+j]
+	}
+	return f
+}
+
 func convertFields(fields logrus.Fields) ([]attribute.KeyValue, error) {
 	var errVal error
 	kvs := make([]attribute.KeyValue, 0, len(fields))
@@ -4,16 +4,23 @@
 package otellogrus
 
 import (
+	"context"
+	"io"
+	"runtime"
 	"testing"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
 	"go.opentelemetry.io/otel/log"
 	"go.opentelemetry.io/otel/log/embedded"
 	"go.opentelemetry.io/otel/log/global"
 	"go.opentelemetry.io/otel/log/logtest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type mockLoggerProvider struct {
@@ -77,7 +84,11 @@ func TestNewConfig(t *testing.T) {
 		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
-			assert.Equal(t, tt.wantConfig, newConfig(tt.options))
+			got := newConfig(tt.options)
+			// Functions are not comparable, only check the default is set.
+			assert.NotNil(t, got.levelSeverity)
+			got.levelSeverity = nil
+			assert.Equal(t, tt.wantConfig, got)
 		})
 	}
 }
@@ -547,3 +558,97 @@ func TestConvertFields(t *testing.T) {
 		})
 	}
 }
+
+func TestHookSpanEvents(t *testing.T) {
+	span := &eventSpan{id: trace.SpanID{0x01}}
+	ctx := trace.ContextWithSpan(t.Context(), span)
+
+	rec := logtest.NewRecorder()
+	hook := NewHook("name", WithLoggerProvider(rec), WithSpanEvents(log.SeverityInfo, 0))
+	require.NoError(t, hook.Fire(&logrus.Entry{Context: ctx, Level: logrus.DebugLevel, Message: "debug"}))
+	require.NoError(t, hook.Fire(&logrus.Entry{Context: ctx, Level: logrus.InfoLevel, Message: "info"}))
+
+	require.Len(t, span.events, 1)
+	assert.Equal(t, "info", span.events[0].name)
+	assert.Contains(t, span.events[0].config.Attributes(), attribute.String("log.severity_text", "info"))
+}
+
+func TestHookSource(t *testing.T) {
+	const funcName = "go.opentelemetry.io/contrib/bridges/otellogrus.TestHookSource"
+
+	for _, reportCaller := range []bool{false, true} {
+		rec := logtest.NewRecorder()
+		logger := logrus.New()
+		logger.SetOutput(io.Discard)
+		logger.SetReportCaller(reportCaller)
+		logger.AddHook(NewHook("name", WithLoggerProvider(rec), WithSource(true)))
+
+		_, file, line, _ := runtime.Caller(0)
+		logger.Info("info")
+		logger.WithField("key", "value").Warn("warn")
+
+		records := rec.Result()[logtest.Scope{Name: "name"}]
+		require.Len(t, records, 2)
+		for i, r := range records {
+			assert.Contains(t, r.Attributes, attribute.String(string(semconv.CodeFilePathKey), file), "ReportCaller %t, record %d", reportCaller, i)
+			assert.Contains(t, r.Attributes, attribute.String(string(semconv.CodeFunctionNameKey), funcName), "ReportCaller %t, record %d", reportCaller, i)
+			assert.Contains(t, r.Attributes, attribute.Int(string(semconv.CodeLineNumberKey), line+1+i), "ReportCaller %t, record %d", reportCaller, i)
+		}
+	}
+}
+
+func TestHookNoSource(t *testing.T) {
+	rec := logtest.NewRecorder()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.SetReportCaller(true)
+	logger.AddHook(NewHook("name", WithLoggerProvider(rec)))
+	logger.Info("info")
+
+	records := rec.Result()[logtest.Scope{Name: "name"}]
+	require.Len(t, records, 1)
+	assert.Empty(t, records[0].Attributes)
+}
+
+func TestPackageName(t *testing.T) {
+	assert.Equal(t, "github.com/sirupsen/logrus", packageName("github.com/sirupsen/logrus.(*Entry).Log"))
+	assert.Equal(t, "main", packageName("main.main"))
+	assert.Equal(t, "pkg", packageName("pkg"))
+}
+
+func TestHookPolicy(t *testing.T) {
+	member, err := baggage.NewMember("tenant", "acme")
+	require.NoError(t, err)
+	bag, err := baggage.New(member)
+	require.NoError(t, err)
+	ctx := baggage.ContextWithBaggage(t.Context(), bag)
+
+	rec := logtest.NewRecorder()
+	hook := NewHook("name",
+		WithLoggerProvider(rec),
+		WithLevelSeverity(func(l logrus.Level) log.Severity {
+			if l <= logrus.WarnLevel {
+				return log.SeverityError
+			}
+			return log.SeverityDebug
+		}),
+		WithEnricher(func(ctx context.Context, r *log.Record) {
+			b := baggage.FromContext(ctx)
+			r.AddAttributes(attribute.String("tenant.id", b.Member("tenant").Value()))
+		}),
+		WithKeyRenames(map[string]string{"user": "user.name"}),
+	)
+	require.NoError(t, hook.Fire(&logrus.Entry{
+		Context: ctx,
+		Level:   logrus.WarnLevel,
+		Data:    logrus.Fields{"user": "alice"},
+	}))
+
+	records := rec.Result()[logtest.Scope{Name: "name"}]
+	require.Len(t, records, 1)
+	assert.Equal(t, log.SeverityError, records[0].Severity)
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("user.name", "alice"),
+		attribute.String("tenant.id", "acme"),
+	}, records[0].Attributes)
+}
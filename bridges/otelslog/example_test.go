@@ -4,6 +4,9 @@
 package otelslog_test
 
 import (
+	"log/slog"
+	"os"
+
 	"go.opentelemetry.io/otel/log/noop"
 
 	"go.opentelemetry.io/contrib/bridges/otelslog"
@@ -16,3 +19,18 @@ func Example() {
 	// Create an *slog.Logger and use it in your application.
 	otelslog.NewLogger("my/pkg/name", otelslog.WithLoggerProvider(provider))
 }
+
+func ExampleNewTeeHandler() {
+	// Use a working LoggerProvider implementation instead e.g. using go.opentelemetry.io/otel/sdk/log.
+	provider := noop.NewLoggerProvider()
+
+	// Send the records to OpenTelemetry and write them, along with the trace
+	// context they are logged with, to the standard output.
+	h := otelslog.NewTeeHandler(
+		otelslog.NewHandler("my/pkg/name", otelslog.WithLoggerProvider(provider)),
+		slog.NewJSONHandler(os.Stdout, nil),
+	)
+
+	// Create an *slog.Logger and use it in your application.
+	slog.New(h)
+}
@@ -4,6 +4,9 @@
 // Package otelslog provides [Handler], an [slog.Handler] implementation, that
 // can be used to bridge between the [log/slog] API and [OpenTelemetry].
 //
+// [TeeHandler] can be used to send the records both to a [Handler] and to
+// local handlers, adding the trace context to the local output.
+//
 // # Record Conversion
 //
 // The [slog.Record] are converted to OpenTelemetry [log.Record] in the following
@@ -16,8 +19,9 @@
 //   - PC is dropped.
 //   - Attr are transformed and set as the Attributes.
 //
-// The Level is transformed by using the static offset to the OpenTelemetry
-// Severity types. For example:
+// The Level is transformed by using the [WithLevelSeverity] option. If the
+// option is not provided then the Level is transformed by using the static
+// offset to the OpenTelemetry Severity types. For example:
 //
 //   - [slog.LevelDebug] is transformed to [log.SeverityDebug]
 //   - [slog.LevelInfo] is transformed to [log.SeverityInfo]
@@ -41,6 +45,10 @@
 //     transforms for each group value.
 //   - [slog.KindLogValuer] the value is resolved and then transformed.
 //
+// The attribute keys are then renamed as configured with [WithKeyRenames],
+// and the record is passed to the function configured with [WithEnricher]
+// before it is emitted.
+//
 // [OpenTelemetry]: https://opentelemetry.io/docs/concepts/signals/logs/
 package otelslog
 
@@ -69,6 +77,13 @@ type config struct {
 	schemaURL  string
 	attributes []attribute.KeyValue
 	source     bool
+
+	levelSeverity func(slog.Level) log.Severity
+	policy        recordPolicy
+
+	spanEvents           bool
+	spanEventMinSeverity log.Severity
+	spanEventMaxPerSpan  int
 }
 
 func newConfig(options []Option) config {
@@ -81,6 +96,10 @@ func newConfig(options []Option) config {
 		c.provider = global.GetLoggerProvider()
 	}
 
+	if c.levelSeverity == nil {
+		c.levelSeverity = convertLevel
+	}
+
 	return c
 }
 
@@ -157,6 +176,62 @@ func WithSource(source bool) Option {
 	})
 }
 
+// WithLevelSeverity returns an [Option] that configures the function used to
+// convert slog levels to OpenTelemetry log severities.
+//
+// By default if this Option is not provided, the Handler will use a static
+// offset, transforming [slog.LevelInfo] to [log.SeverityInfo], etc.
+func WithLevelSeverity(f func(slog.Level) log.Severity) Option {
+	return optFunc(func(c config) config {
+		c.levelSeverity = f
+		return c
+	})
+}
+
+// WithEnricher returns an [Option] that configures a function called with
+// the context of every record emitted by a [Handler] before it is emitted, e.g.
+// to add tenant or request attributes from the baggage of the context. The
+// function is called after the attribute keys are renamed with
+// [WithKeyRenames].
+//
+// By default if this Option is not provided, the records are not enriched.
+func WithEnricher(f func(ctx context.Context, record *log.Record)) Option {
+	return optFunc(func(c config) config {
+		c.policy.enrich = f
+		return c
+	})
+}
+
+// WithKeyRenames returns an [Option] that configures the [Handler] to rename
+// the keys of the attributes of the records it emits. Each key of renames is
+// replaced with its value. Only the keys of the top-level attributes are
+// renamed.
+//
+// By default if this Option is not provided, the keys are not renamed.
+func WithKeyRenames(renames map[string]string) Option {
+	return optFunc(func(c config) config {
+		c.policy.renames = renames
+		return c
+	})
+}
+
+// WithSpanEvents returns an [Option] that configures the [Handler] to also add
+// the log records with a severity of at least minSeverity as events to the
+// recording span of the context they are logged with. The name of an event is
+// the body of the record, and its attributes are the severity, the error, and
+// the attributes of the record. At most maxPerSpan events are added to a
+// span, or an unlimited number if maxPerSpan is not positive.
+//
+// By default if this Option is not provided, no span events are added.
+func WithSpanEvents(minSeverity log.Severity, maxPerSpan int) Option {
+	return optFunc(func(c config) config {
+		c.spanEvents = true
+		c.spanEventMinSeverity = minSeverity
+		c.spanEventMaxPerSpan = maxPerSpan
+		return c
+	})
+}
+
 // Handler is an [slog.Handler] that sends all logging records it receives to
 // OpenTelemetry. See package documentation for how conversions are made.
 type Handler struct {
@@ -167,7 +242,10 @@ type Handler struct {
 	group  *group
 	logger log.Logger
 
-	source bool
+	source        bool
+	levelSeverity func(slog.Level) log.Severity
+	policy        recordPolicy
+	spanEvents    *spanEvents
 }
 
 // Compile-time check *Handler implements slog.Handler.
@@ -183,15 +261,23 @@ var _ slog.Handler = (*Handler)(nil)
 // [log.Logger] implementation may override this value with a default.
 func NewHandler(name string, options ...Option) *Handler {
 	cfg := newConfig(options)
-	return &Handler{
-		logger: cfg.logger(name),
-		source: cfg.source,
+	h := &Handler{
+		logger:        cfg.logger(name),
+		source:        cfg.source,
+		levelSeverity: cfg.levelSeverity,
+		policy:        cfg.policy,
 	}
+	if cfg.spanEvents {
+		h.spanEvents = newSpanEvents(cfg.spanEventMinSeverity, cfg.spanEventMaxPerSpan)
+	}
+	return h
 }
 
 // Handle handles the passed record.
 func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
-	h.logger.Emit(ctx, h.convertRecord(record))
+	r := h.policy.apply(ctx, h.convertRecord(record))
+	h.logger.Emit(ctx, r)
+	h.spanEvents.add(ctx, r)
 	return nil
 }
 
@@ -200,8 +286,7 @@ func (h *Handler) convertRecord(r slog.Record) log.Record {
 	record.SetTimestamp(r.Time)
 	record.SetBody(attribute.StringValue(r.Message))
 
-	const sevOffset = slog.Level(log.SeverityDebug) - slog.LevelDebug
-	record.SetSeverity(log.Severity(r.Level + sevOffset))
+	record.SetSeverity(h.levelSeverity(r.Level))
 	record.SetSeverityText(r.Level.String())
 
 	if h.source {
@@ -264,11 +349,17 @@ func (h *Handler) convertRecord(r slog.Record) log.Record {
 // Enabled returns true if the Handler is enabled to log for the provided
 // context and Level. Otherwise, false is returned if it is not enabled.
 func (h *Handler) Enabled(ctx context.Context, l slog.Level) bool {
-	const sevOffset = slog.Level(log.SeverityDebug) - slog.LevelDebug
-	param := log.EnabledParameters{Severity: log.Severity(l + sevOffset)}
+	param := log.EnabledParameters{Severity: h.levelSeverity(l)}
 	return h.logger.Enabled(ctx, param)
 }
 
+// convertLevel converts l to an OpenTelemetry log severity using the static
+// offset between the slog levels and the OpenTelemetry severities.
+func convertLevel(l slog.Level) log.Severity {
+	const sevOffset = slog.Level(log.SeverityDebug) - slog.LevelDebug
+	return log.Severity(l + sevOffset)
+}
+
 // WithAttrs returns a new [slog.Handler] based on h that will log using the
 // passed attrs.
 func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
@@ -22,10 +22,12 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
 	"go.opentelemetry.io/otel/log"
 	"go.opentelemetry.io/otel/log/embedded"
 	"go.opentelemetry.io/otel/log/global"
 	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var now = time.Now()
@@ -688,3 +690,64 @@ func TestKVBufferKeyValuesConcurrent(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestHandlerSpanEvents(t *testing.T) {
+	span := &eventSpan{id: trace.SpanID{0x01}}
+	ctx := trace.ContextWithSpan(t.Context(), span)
+
+	r := new(recorder)
+	l := slog.New(NewHandler("", WithLoggerProvider(r), WithSpanEvents(log.SeverityInfo, 1)))
+	l.DebugContext(ctx, "debug")
+	l.InfoContext(ctx, "first", "key", "value")
+	l.WarnContext(ctx, "second")
+
+	assert.Len(t, r.Records, 3)
+	require.Len(t, span.events, 1)
+	assert.Equal(t, "first", span.events[0].name)
+	assert.Contains(t, span.events[0].config.Attributes(), attribute.String("key", "value"))
+}
+
+func TestHandlerPolicy(t *testing.T) {
+	member, err := baggage.NewMember("tenant", "acme")
+	require.NoError(t, err)
+	bag, err := baggage.New(member)
+	require.NoError(t, err)
+	ctx := baggage.ContextWithBaggage(t.Context(), bag)
+
+	r := new(recorder)
+	r.MinSeverity = log.SeverityWarn
+	h := NewHandler("",
+		WithLoggerProvider(r),
+		WithLevelSeverity(func(l slog.Level) log.Severity {
+			if l >= slog.LevelInfo {
+				return log.SeverityError
+			}
+			return log.SeverityDebug
+		}),
+		WithEnricher(func(ctx context.Context, r *log.Record) {
+			b := baggage.FromContext(ctx)
+			r.AddAttributes(attribute.String("tenant.id", b.Member("tenant").Value()))
+		}),
+		WithKeyRenames(map[string]string{"user": "user.name"}),
+	)
+
+	assert.False(t, h.Enabled(ctx, slog.LevelDebug))
+	assert.True(t, h.Enabled(ctx, slog.LevelInfo))
+
+	l := slog.New(h)
+	l.InfoContext(ctx, "msg", "user", "alice", "key", "value")
+
+	require.Len(t, r.Records, 1)
+	got := r.Records[0]
+	assert.Equal(t, log.SeverityError, got.Severity())
+	var attrs []attribute.KeyValue
+	got.WalkAttributes(func(kv attribute.KeyValue) bool {
+		attrs = append(attrs, kv)
+		return true
+	})
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("user.name", "alice"),
+		attribute.String("key", "value"),
+		attribute.String("tenant.id", "acme"),
+	}, attrs)
+}
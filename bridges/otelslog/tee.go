@@ -0,0 +1,197 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelslog
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Keys of the attributes added by a [TeeHandler] to the records it forwards
+// to local handlers.
+const (
+	TraceIDKey    = "trace_id"
+	SpanIDKey     = "span_id"
+	TraceFlagsKey = "trace_flags"
+)
+
+// TeeHandler is an [slog.Handler] that forwards the records it receives to a
+// [Handler] and to local handlers, e.g. an [slog.JSONHandler] writing to the
+// standard output.
+//
+// The records forwarded to the local handlers have the [TraceIDKey],
+// [SpanIDKey], and [TraceFlagsKey] attributes added for the span context of
+// the context they are logged with, if it is valid. This allows correlating
+// the local output with the records sent to OpenTelemetry. The attributes are
+// added at the top level, outside of any group.
+//
+// A record is only forwarded to the handlers that are enabled for its level.
+type TeeHandler struct {
+	// Ensure forward compatibility by explicitly making this not comparable.
+	noCmp [0]func() //nolint:unused  // This is indeed used.
+
+	otel slog.Handler
+
+	// local are the local handlers with the attributes and groups of the
+	// TeeHandler applied.
+	local []slog.Handler
+	// root are the local handlers with the attributes of the TeeHandler
+	// added before its first group applied. The records with a trace
+	// context are handled by them when a group is open, with the groups
+	// added to the records, so the trace context is added at the top level.
+	root []slog.Handler
+	// groups are the open groups, outermost first.
+	groups []teeGroup
+}
+
+// teeGroup is a group opened with WithGroup and the attributes added to it.
+type teeGroup struct {
+	name  string
+	attrs []slog.Attr
+}
+
+// Compile-time check *TeeHandler implements slog.Handler.
+var _ slog.Handler = (*TeeHandler)(nil)
+
+// NewTeeHandler returns a new [TeeHandler] forwarding records to h and to
+// the local handlers.
+func NewTeeHandler(h *Handler, local ...slog.Handler) *TeeHandler {
+	local = slices.Clone(local)
+	return &TeeHandler{
+		otel:  h,
+		local: local,
+		root:  local,
+	}
+}
+
+// Enabled returns true if any of the handlers of t is enabled to log for the
+// provided context and Level.
+func (t *TeeHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	if t.otel.Enabled(ctx, l) {
+		return true
+	}
+	for _, h := range t.local {
+		if h.Enabled(ctx, l) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle forwards the passed record to all the handlers of t that are
+// enabled for its level.
+func (t *TeeHandler) Handle(ctx context.Context, record slog.Record) error {
+	var errs []error
+	if t.otel.Enabled(ctx, record.Level) {
+		if err := t.otel.Handle(ctx, record); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	sc := trace.SpanContextFromContext(ctx)
+	var (
+		traced   slog.Record
+		isTraced bool
+	)
+	for i, h := range t.local {
+		if !h.Enabled(ctx, record.Level) {
+			continue
+		}
+
+		r := record
+		if sc.IsValid() {
+			if !isTraced {
+				traced, isTraced = t.traced(record, sc), true
+			}
+			r = traced
+			if len(t.groups) > 0 {
+				h = t.root[i]
+			}
+		}
+		if err := h.Handle(ctx, r); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// traced returns a copy of record with the trace context of sc added at the
+// top level. The attributes of record are nested in the open groups, as the
+// record is handled by the root handlers when a group is open.
+func (t *TeeHandler) traced(record slog.Record, sc trace.SpanContext) slog.Record {
+	traceAttrs := []slog.Attr{
+		slog.String(TraceIDKey, sc.TraceID().String()),
+		slog.String(SpanIDKey, sc.SpanID().String()),
+		slog.String(TraceFlagsKey, sc.TraceFlags().String()),
+	}
+	if len(t.groups) == 0 {
+		r := record.Clone()
+		r.AddAttrs(traceAttrs...)
+		return r
+	}
+
+	attrs := make([]slog.Attr, 0, record.NumAttrs())
+	record.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	for i := len(t.groups) - 1; i >= 0; i-- {
+		g := t.groups[i]
+		attrs = []slog.Attr{{
+			Key:   g.name,
+			Value: slog.GroupValue(append(slices.Clip(g.attrs), attrs...)...),
+		}}
+	}
+
+	r := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	r.AddAttrs(traceAttrs...)
+	r.AddAttrs(attrs...)
+	return r
+}
+
+// WithAttrs returns a new [slog.Handler] based on t whose handlers will log
+// using the passed attrs.
+func (t *TeeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return t
+	}
+	h := &TeeHandler{
+		otel:   t.otel.WithAttrs(attrs),
+		local:  make([]slog.Handler, len(t.local)),
+		root:   t.root,
+		groups: t.groups,
+	}
+	for i, l := range t.local {
+		h.local[i] = l.WithAttrs(attrs)
+	}
+	if n := len(t.groups); n == 0 {
+		h.root = h.local
+	} else {
+		h.groups = slices.Clone(t.groups)
+		h.groups[n-1].attrs = append(slices.Clip(t.groups[n-1].attrs), attrs...)
+	}
+	return h
+}
+
+// WithGroup returns a new [slog.Handler] based on t whose handlers will log
+// all messages and attributes within a group of the provided name.
+func (t *TeeHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return t
+	}
+	h := &TeeHandler{
+		otel:   t.otel.WithGroup(name),
+		local:  make([]slog.Handler, len(t.local)),
+		root:   t.root,
+		groups: append(slices.Clip(t.groups), teeGroup{name: name}),
+	}
+	for i, l := range t.local {
+		h.local[i] = l.WithGroup(name)
+	}
+	return h
+}
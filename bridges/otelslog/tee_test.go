@@ -0,0 +1,171 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelslog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/slogtest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func parseJSONLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var out []map[string]any
+	for line := range strings.Lines(buf.String()) {
+		var m map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &m))
+		out = append(out, m)
+	}
+	return out
+}
+
+func TestTeeHandlerSlogtest(t *testing.T) {
+	var buf bytes.Buffer
+	slogtest.Run(t, func(*testing.T) slog.Handler {
+		buf.Reset()
+		return NewTeeHandler(NewHandler("", WithLoggerProvider(new(recorder))), slog.NewJSONHandler(&buf, nil))
+	}, func(t *testing.T) map[string]any {
+		return parseJSONLines(t, &buf)[0]
+	})
+}
+
+func TestTeeHandlerTraceContext(t *testing.T) {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x0a, 0xf7, 0x65, 0x19, 0x16, 0xcd, 0x43, 0xdd, 0x84, 0x48, 0xeb, 0x21, 0x1c, 0x80, 0x31, 0x9c},
+		SpanID:     trace.SpanID{0xb7, 0xad, 0x6b, 0x71, 0x69, 0x20, 0x33, 0x31},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(t.Context(), sc)
+
+	r := new(recorder)
+	var buf bytes.Buffer
+	logger := slog.New(NewTeeHandler(NewHandler("", WithLoggerProvider(r)), slog.NewJSONHandler(&buf, nil)))
+
+	logger.InfoContext(ctx, "top", "key", "value")
+	logger.With("a", 1).WithGroup("g").InfoContext(ctx, "grouped", "key", "value")
+	logger.InfoContext(t.Context(), "no span")
+
+	got := parseJSONLines(t, &buf)
+	require.Len(t, got, 3)
+
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", got[0][TraceIDKey])
+	assert.Equal(t, "b7ad6b7169203331", got[0][SpanIDKey])
+	assert.Equal(t, "01", got[0][TraceFlagsKey])
+	assert.Equal(t, "value", got[0]["key"])
+
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", got[1][TraceIDKey])
+	assert.Equal(t, float64(1), got[1]["a"])
+	assert.Equal(t, map[string]any{"key": "value"}, got[1]["g"])
+
+	assert.NotContains(t, got[2], TraceIDKey)
+
+	// The trace context is not added to the OpenTelemetry records.
+	require.Len(t, r.Records, 3)
+	for _, rec := range r.Records {
+		rec.WalkAttributes(func(kv attribute.KeyValue) bool {
+			assert.NotEqual(t, TraceIDKey, string(kv.Key))
+			return true
+		})
+	}
+}
+
+func TestTeeHandlerTraceContextNestedGroups(t *testing.T) {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x01},
+		SpanID:  trace.SpanID{0x01},
+	})
+	ctx := trace.ContextWithSpanContext(t.Context(), sc)
+
+	var buf bytes.Buffer
+	h := NewTeeHandler(NewHandler("", WithLoggerProvider(new(recorder))), slog.NewJSONHandler(&buf, nil))
+	logger := slog.New(h).With("a", 1).WithGroup("g1").With("b", 2).WithGroup("g2").With("c", 3).WithGroup("empty")
+
+	logger.InfoContext(ctx, "traced", "key", "value")
+	logger.InfoContext(t.Context(), "untraced", "key", "value")
+	logger.InfoContext(ctx, "no attributes")
+
+	got := parseJSONLines(t, &buf)
+	require.Len(t, got, 3)
+
+	assert.Equal(t, "01000000000000000000000000000000", got[0][TraceIDKey])
+	assert.Equal(t, "0100000000000000", got[0][SpanIDKey])
+	assert.Equal(t, "00", got[0][TraceFlagsKey])
+
+	// Apart from the trace context, the records are the same as the ones
+	// logged without a span.
+	for _, key := range []string{slog.TimeKey, slog.MessageKey, TraceIDKey, SpanIDKey, TraceFlagsKey} {
+		delete(got[0], key)
+		delete(got[1], key)
+	}
+	assert.Equal(t, got[1], got[0])
+	assert.Equal(t, map[string]any{
+		"b":  float64(2),
+		"g2": map[string]any{"c": float64(3), "empty": map[string]any{"key": "value"}},
+	}, got[0]["g1"])
+
+	// The empty group is omitted.
+	assert.Equal(t, map[string]any{"b": float64(2), "g2": map[string]any{"c": float64(3)}}, got[2]["g1"])
+}
+
+func TestTeeHandlerEnabled(t *testing.T) {
+	r := new(recorder)
+	r.MinSeverity = log.SeverityWarn
+	var buf bytes.Buffer
+	h := NewTeeHandler(
+		NewHandler("", WithLoggerProvider(r)),
+		slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}),
+	)
+
+	ctx := t.Context()
+	assert.False(t, h.Enabled(ctx, slog.LevelDebug))
+	assert.True(t, h.Enabled(ctx, slog.LevelInfo))
+
+	logger := slog.New(h)
+	logger.Debug("none")
+	logger.Info("local")
+	logger.Warn("both")
+
+	got := parseJSONLines(t, &buf)
+	require.Len(t, got, 2)
+	assert.Equal(t, "local", got[0][slog.MessageKey])
+	assert.Equal(t, "both", got[1][slog.MessageKey])
+
+	require.Len(t, r.Records, 1)
+	assert.Equal(t, attribute.StringValue("both"), r.Records[0].Body())
+}
+
+type errHandler struct {
+	slog.Handler
+	err error
+}
+
+func (h errHandler) Handle(context.Context, slog.Record) error { return h.err }
+
+func TestTeeHandlerErrors(t *testing.T) {
+	errA, errB := errors.New("a"), errors.New("b")
+	var buf bytes.Buffer
+	h := NewTeeHandler(
+		NewHandler("", WithLoggerProvider(new(recorder))),
+		errHandler{Handler: slog.NewJSONHandler(&buf, nil), err: errA},
+		slog.NewJSONHandler(&buf, nil),
+		errHandler{Handler: slog.NewJSONHandler(&buf, nil), err: errB},
+	)
+
+	err := h.Handle(t.Context(), slog.NewRecord(now, slog.LevelInfo, "msg", 0))
+	assert.ErrorIs(t, err, errA)
+	assert.ErrorIs(t, err, errB)
+	assert.Len(t, parseJSONLines(t, &buf), 1)
+}
@@ -0,0 +1,47 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelstdlog_test
+
+import (
+	"log"
+
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/noop"
+
+	"go.opentelemetry.io/contrib/bridges/otelstdlog"
+)
+
+func Example() {
+	// Use a working LoggerProvider implementation instead e.g. using go.opentelemetry.io/otel/sdk/log.
+	provider := noop.NewLoggerProvider()
+
+	// Create an *otelstdlog.Writer and use it as the output of the standard logger.
+	w := otelstdlog.NewWriter(
+		"my/pkg/name",
+		otelstdlog.WithLoggerProvider(provider),
+		otelstdlog.WithFlags(log.Flags()),
+		otelstdlog.WithSeverityPrefixes(map[string]otellog.Severity{
+			"[WARN] ":  otellog.SeverityWarn,
+			"[ERROR] ": otellog.SeverityError,
+		}),
+	)
+	log.SetOutput(w)
+
+	log.Print("[WARN] disk almost full")
+}
+
+func ExampleNewLogger() {
+	// Use a working LoggerProvider implementation instead e.g. using go.opentelemetry.io/otel/sdk/log.
+	provider := noop.NewLoggerProvider()
+
+	// Create a *log.Logger sending its messages to OpenTelemetry.
+	logger := otelstdlog.NewLogger(
+		"my/pkg/name",
+		otelstdlog.WithLoggerProvider(provider),
+		otelstdlog.WithPrefix("server: "),
+		otelstdlog.WithFlags(log.LstdFlags|log.Lshortfile),
+	)
+
+	logger.Println("Hello, World!")
+}
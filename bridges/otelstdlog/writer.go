@@ -0,0 +1,327 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package otelstdlog provides a [Writer], an [io.Writer] implementation that
+// can be used to bridge between the standard library [log] package and
+// [OpenTelemetry].
+//
+// A [Writer] can be set as the output of the standard logger with
+// [log.SetOutput], or of any [log.Logger]. [NewLogger] returns a [log.Logger]
+// writing to a new Writer.
+//
+// # Record Conversion
+//
+// Every write, which a [log.Logger] performs once per logged message, is
+// converted to an OpenTelemetry [otellog.Record] in the following way:
+//
+//   - The header written by the [log.Logger] is parsed according to the flags
+//     and prefix configured with [WithFlags] and [WithPrefix]. The date and
+//     time are set as the Timestamp, and the file and line as the
+//     code.file.path and code.line.number attributes.
+//   - If the message starts with one of the prefixes configured with
+//     [WithSeverityPrefixes], the prefix is removed from the message and
+//     its severity is set as the Severity. Otherwise, the severity configured
+//     with [WithDefaultSeverity] is used.
+//   - The message, without its trailing newline, is set as the Body using an
+//     [attribute.StringValue].
+//
+// [OpenTelemetry]: https://opentelemetry.io/docs/concepts/signals/logs/
+package otelstdlog
+
+import (
+	"context"
+	"io"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+)
+
+type config struct {
+	provider   otellog.LoggerProvider
+	version    string
+	schemaURL  string
+	attributes []attribute.KeyValue
+
+	severity         otellog.Severity
+	flags            int
+	prefix           string
+	severityPrefixes map[string]otellog.Severity
+}
+
+func newConfig(options []Option) config {
+	c := config{
+		severity: otellog.SeverityInfo,
+		flags:    log.LstdFlags,
+	}
+	for _, opt := range options {
+		c = opt.apply(c)
+	}
+
+	if c.provider == nil {
+		c.provider = global.GetLoggerProvider()
+	}
+
+	return c
+}
+
+// Option configures a [Writer].
+type Option interface {
+	apply(config) config
+}
+
+type optFunc func(config) config
+
+func (f optFunc) apply(c config) config { return f(c) }
+
+// WithVersion returns an [Option] that configures the version of the
+// [otellog.Logger] used by a [Writer]. The version should be the version of
+// the package that is being logged.
+func WithVersion(version string) Option {
+	return optFunc(func(c config) config {
+		c.version = version
+		return c
+	})
+}
+
+// WithSchemaURL returns an [Option] that configures the semantic convention
+// schema URL of the [otellog.Logger] used by a [Writer]. The schemaURL should
+// be the schema URL for the semantic conventions used in log records.
+func WithSchemaURL(schemaURL string) Option {
+	return optFunc(func(c config) config {
+		c.schemaURL = schemaURL
+		return c
+	})
+}
+
+// WithAttributes returns an [Option] that configures the instrumentation scope
+// attributes of the [otellog.Logger] used by a [Writer].
+func WithAttributes(attributes ...attribute.KeyValue) Option {
+	return optFunc(func(c config) config {
+		c.attributes = attributes
+		return c
+	})
+}
+
+// WithLoggerProvider returns an [Option] that configures
+// [otellog.LoggerProvider] used by a [Writer] to create its [otellog.Logger].
+//
+// By default if this Option is not provided, the Writer will use the global
+// LoggerProvider.
+func WithLoggerProvider(provider otellog.LoggerProvider) Option {
+	return optFunc(func(c config) config {
+		c.provider = provider
+		return c
+	})
+}
+
+// WithDefaultSeverity returns an [Option] that configures the severity of
+// the records emitted by a [Writer] for messages that do not start with one
+// of the prefixes configured with [WithSeverityPrefixes].
+//
+// By default if this Option is not provided, [otellog.SeverityInfo] is used.
+func WithDefaultSeverity(severity otellog.Severity) Option {
+	return optFunc(func(c config) config {
+		c.severity = severity
+		return c
+	})
+}
+
+// WithFlags returns an [Option] that configures the [log] flags of the
+// messages written to a [Writer], used to parse the header of the messages.
+// The flags are also used by the [log.Logger] returned by [NewLogger].
+//
+// By default if this Option is not provided, [log.LstdFlags] is used, which
+// are the default flags of the standard logger.
+func WithFlags(flags int) Option {
+	return optFunc(func(c config) config {
+		c.flags = flags
+		return c
+	})
+}
+
+// WithPrefix returns an [Option] that configures the [log] prefix of the
+// messages written to a [Writer]. The prefix is removed from the messages.
+// The prefix is also used by the [log.Logger] returned by [NewLogger].
+//
+// By default if this Option is not provided, the messages are expected to
+// have no prefix.
+func WithPrefix(prefix string) Option {
+	return optFunc(func(c config) config {
+		c.prefix = prefix
+		return c
+	})
+}
+
+// WithSeverityPrefixes returns an [Option] that configures message prefixes,
+// e.g. "[ERROR] " or "WARN: ", that determine the severity of the records
+// emitted by a [Writer]. The longest prefix a message starts with is removed
+// from the message and its severity is used.
+//
+// By default if this Option is not provided, messages are not parsed for a
+// severity.
+func WithSeverityPrefixes(prefixes map[string]otellog.Severity) Option {
+	return optFunc(func(c config) config {
+		c.severityPrefixes = prefixes
+		return c
+	})
+}
+
+// NewLogger returns a new [log.Logger] writing to a new [Writer]. The
+// returned Logger uses the flags and prefix configured with [WithFlags] and
+// [WithPrefix].
+func NewLogger(name string, options ...Option) *log.Logger {
+	c := newConfig(options)
+	return log.New(newWriter(name, c), c.prefix, c.flags)
+}
+
+// NewWriter returns a new [Writer] to be used as the output of a
+// [log.Logger].
+//
+// If [WithLoggerProvider] is not provided, the returned [Writer] will use the
+// global LoggerProvider.
+func NewWriter(name string, options ...Option) *Writer {
+	return newWriter(name, newConfig(options))
+}
+
+func newWriter(name string, c config) *Writer {
+	var opts []otellog.LoggerOption
+	if c.version != "" {
+		opts = append(opts, otellog.WithInstrumentationVersion(c.version))
+	}
+	if c.schemaURL != "" {
+		opts = append(opts, otellog.WithSchemaURL(c.schemaURL))
+	}
+	if c.attributes != nil {
+		opts = append(opts, otellog.WithInstrumentationAttributes(c.attributes...))
+	}
+
+	prefixes := make([]string, 0, len(c.severityPrefixes))
+	for p := range c.severityPrefixes {
+		prefixes = append(prefixes, p)
+	}
+	// Match the longest prefixes first.
+	slices.SortFunc(prefixes, func(a, b string) int { return len(b) - len(a) })
+
+	return &Writer{
+		logger:           c.provider.Logger(name, opts...),
+		severity:         c.severity,
+		flags:            c.flags,
+		prefix:           c.prefix,
+		prefixes:         prefixes,
+		severityPrefixes: c.severityPrefixes,
+	}
+}
+
+// Writer is an [io.Writer] that sends all the messages written to it to
+// OpenTelemetry. See package documentation for how conversions are made.
+type Writer struct {
+	// Ensure forward compatibility by explicitly making this not comparable.
+	noCmp [0]func() //nolint:unused  // This is indeed used.
+
+	logger           otellog.Logger
+	severity         otellog.Severity
+	flags            int
+	prefix           string
+	prefixes         []string
+	severityPrefixes map[string]otellog.Severity
+}
+
+// Compile-time check *Writer implements io.Writer.
+var _ io.Writer = (*Writer)(nil)
+
+// Write emits p as a single log record. It always returns len(p) and nil.
+func (w *Writer) Write(p []byte) (int, error) {
+	ctx := context.Background()
+	msg := strings.TrimSuffix(string(p), "\n")
+
+	var record otellog.Record
+	msg = w.parseHeader(&record, msg)
+
+	severity := w.severity
+	for _, prefix := range w.prefixes {
+		if rest, ok := strings.CutPrefix(msg, prefix); ok {
+			severity = w.severityPrefixes[prefix]
+			msg = rest
+			break
+		}
+	}
+	record.SetSeverity(severity)
+	record.SetBody(attribute.StringValue(msg))
+
+	if w.logger.Enabled(ctx, otellog.EnabledParameters{Severity: severity}) {
+		w.logger.Emit(ctx, record)
+	}
+	return len(p), nil
+}
+
+// parseHeader parses the header written by a log.Logger with the flags and
+// prefix of w into record, and returns the message following it. The
+// remaining part of msg is returned as is when it does not match the
+// expected header.
+func (w *Writer) parseHeader(record *otellog.Record, msg string) string {
+	if w.flags&log.Lmsgprefix == 0 {
+		msg = strings.TrimPrefix(msg, w.prefix)
+	}
+
+	if w.flags&(log.Ldate|log.Ltime|log.Lmicroseconds) != 0 {
+		var layout string
+		if w.flags&log.Ldate != 0 {
+			layout = "2006/01/02 "
+		}
+		switch {
+		case w.flags&log.Lmicroseconds != 0:
+			layout += "15:04:05.000000 "
+		case w.flags&log.Ltime != 0:
+			layout += "15:04:05 "
+		}
+		if len(msg) < len(layout) {
+			return msg
+		}
+
+		loc := time.Local
+		if w.flags&log.LUTC != 0 {
+			loc = time.UTC
+		}
+		t, err := time.ParseInLocation(layout, msg[:len(layout)], loc)
+		if err != nil {
+			return msg
+		}
+		// A timestamp without a date is not meaningful.
+		if w.flags&log.Ldate != 0 && w.flags&(log.Ltime|log.Lmicroseconds) != 0 {
+			record.SetTimestamp(t)
+		}
+		msg = msg[len(layout):]
+	}
+
+	if w.flags&(log.Lshortfile|log.Llongfile) != 0 {
+		i := strings.Index(msg, ": ")
+		if i < 0 {
+			return msg
+		}
+		j := strings.LastIndexByte(msg[:i], ':')
+		if j < 0 {
+			return msg
+		}
+		line, err := strconv.Atoi(msg[j+1 : i])
+		if err != nil {
+			return msg
+		}
+		record.AddAttributes(
+			attribute.String(string(semconv.CodeFilePathKey), msg[:j]),
+			attribute.Int(string(semconv.CodeLineNumberKey), line),
+		)
+		msg = msg[i+2:]
+	}
+
+	if w.flags&log.Lmsgprefix != 0 {
+		msg = strings.TrimPrefix(msg, w.prefix)
+	}
+	return msg
+}
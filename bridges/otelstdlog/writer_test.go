@@ -0,0 +1,224 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelstdlog
+
+import (
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+	"go.opentelemetry.io/otel/log/logtest"
+)
+
+func TestNewConfig(t *testing.T) {
+	assert.Equal(t, config{
+		provider: global.GetLoggerProvider(),
+		severity: otellog.SeverityInfo,
+		flags:    log.LstdFlags,
+	}, newConfig(nil))
+
+	assert.Equal(t, config{
+		provider: global.GetLoggerProvider(),
+		severity: otellog.SeverityWarn,
+		flags:    log.Lshortfile,
+		prefix:   "app: ",
+	}, newConfig([]Option{
+		WithDefaultSeverity(otellog.SeverityWarn),
+		WithFlags(log.Lshortfile),
+		WithPrefix("app: "),
+	}))
+}
+
+func TestNewWriter(t *testing.T) {
+	const name = "name"
+	provider := global.GetLoggerProvider()
+
+	w := NewWriter(name,
+		WithVersion("42.1"),
+		WithSchemaURL("https://example.com"),
+		WithAttributes(attribute.String("testattr", "testval")),
+	)
+	want := provider.Logger(
+		name,
+		otellog.WithInstrumentationVersion("42.1"),
+		otellog.WithSchemaURL("https://example.com"),
+		otellog.WithInstrumentationAttributes(attribute.String("testattr", "testval")),
+	)
+	assert.Equal(t, want, w.logger)
+}
+
+func TestWriter(t *testing.T) {
+	const name = "name"
+	ts := time.Date(2024, 5, 6, 7, 8, 9, 123456000, time.UTC)
+	prefixes := map[string]otellog.Severity{
+		"[WARN] ":   otellog.SeverityWarn,
+		"[WARNING]": otellog.SeverityWarn2,
+		"ERROR: ":   otellog.SeverityError,
+	}
+
+	for _, tt := range []struct {
+		name    string
+		options []Option
+		msg     string
+
+		want otellog.Record
+	}{
+		{
+			name:    "default severity",
+			options: []Option{WithFlags(0)},
+			msg:     "hello\n",
+			want: func() otellog.Record {
+				var r otellog.Record
+				r.SetSeverity(otellog.SeverityInfo)
+				r.SetBody(attribute.StringValue("hello"))
+				return r
+			}(),
+		},
+		{
+			name:    "custom default severity",
+			options: []Option{WithFlags(0), WithDefaultSeverity(otellog.SeverityDebug)},
+			msg:     "hello",
+			want: func() otellog.Record {
+				var r otellog.Record
+				r.SetSeverity(otellog.SeverityDebug)
+				r.SetBody(attribute.StringValue("hello"))
+				return r
+			}(),
+		},
+		{
+			name:    "severity prefix",
+			options: []Option{WithFlags(0), WithSeverityPrefixes(prefixes)},
+			msg:     "[WARNING] low disk\n",
+			want: func() otellog.Record {
+				var r otellog.Record
+				r.SetSeverity(otellog.SeverityWarn2)
+				r.SetBody(attribute.StringValue(" low disk"))
+				return r
+			}(),
+		},
+		{
+			name: "full header",
+			options: []Option{
+				WithFlags(log.Ldate | log.Lmicroseconds | log.LUTC | log.Llongfile),
+				WithPrefix("app: "),
+				WithSeverityPrefixes(prefixes),
+			},
+			msg: "app: 2024/05/06 07:08:09.123456 /src/main.go:42: ERROR: failed\n",
+			want: func() otellog.Record {
+				var r otellog.Record
+				r.SetTimestamp(ts)
+				r.SetSeverity(otellog.SeverityError)
+				r.SetBody(attribute.StringValue("failed"))
+				r.AddAttributes(
+					attribute.String("code.file.path", "/src/main.go"),
+					attribute.Int("code.line.number", 42),
+				)
+				return r
+			}(),
+		},
+		{
+			name: "message prefix",
+			options: []Option{
+				WithFlags(log.Lshortfile | log.Lmsgprefix),
+				WithPrefix("app: "),
+			},
+			msg: "main.go:42: app: hello\n",
+			want: func() otellog.Record {
+				var r otellog.Record
+				r.SetSeverity(otellog.SeverityInfo)
+				r.SetBody(attribute.StringValue("hello"))
+				r.AddAttributes(
+					attribute.String("code.file.path", "main.go"),
+					attribute.Int("code.line.number", 42),
+				)
+				return r
+			}(),
+		},
+		{
+			name:    "header mismatch",
+			options: []Option{WithFlags(log.LstdFlags)},
+			msg:     "not a header\n",
+			want: func() otellog.Record {
+				var r otellog.Record
+				r.SetSeverity(otellog.SeverityInfo)
+				r.SetBody(attribute.StringValue("not a header"))
+				return r
+			}(),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := logtest.NewRecorder()
+			w := NewWriter(name, append(tt.options, WithLoggerProvider(rec))...)
+
+			n, err := w.Write([]byte(tt.msg))
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.msg), n)
+
+			want := logtest.Recording{
+				logtest.Scope{Name: name}: {{
+					Context:      context.Background(),
+					Timestamp:    tt.want.Timestamp(),
+					Severity:     tt.want.Severity(),
+					SeverityText: tt.want.SeverityText(),
+					Body:         tt.want.Body(),
+					Attributes:   attributes(tt.want),
+				}},
+			}
+			logtest.AssertEqual(t, want, rec.Result())
+		})
+	}
+}
+
+func TestNewLogger(t *testing.T) {
+	rec := logtest.NewRecorder()
+	logger := NewLogger("name",
+		WithLoggerProvider(rec),
+		WithPrefix("app: "),
+		WithFlags(log.LstdFlags|log.Lshortfile),
+	)
+
+	before := time.Now().Truncate(time.Second)
+	logger.Println("hello")
+
+	records := rec.Result()[logtest.Scope{Name: "name"}]
+	require.Len(t, records, 1)
+	r := records[0]
+	assert.Equal(t, attribute.StringValue("hello"), r.Body)
+	assert.Equal(t, otellog.SeverityInfo, r.Severity)
+	assert.False(t, r.Timestamp.Before(before))
+	assert.Contains(t, r.Attributes, attribute.String("code.file.path", "writer_test.go"))
+}
+
+func TestWriterEnabled(t *testing.T) {
+	rec := logtest.NewRecorder(logtest.WithEnabledFunc(func(_ context.Context, p otellog.EnabledParameters) bool {
+		return p.Severity >= otellog.SeverityWarn
+	}))
+	w := NewWriter("name",
+		WithLoggerProvider(rec),
+		WithFlags(0),
+		WithSeverityPrefixes(map[string]otellog.Severity{"WARN ": otellog.SeverityWarn}),
+	)
+
+	_, _ = w.Write([]byte("dropped\n"))
+	_, _ = w.Write([]byte("WARN emitted\n"))
+
+	records := rec.Result()[logtest.Scope{Name: "name"}]
+	require.Len(t, records, 1)
+	assert.Equal(t, attribute.StringValue("emitted"), records[0].Body)
+}
+
+func attributes(r otellog.Record) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	r.WalkAttributes(func(kv attribute.KeyValue) bool {
+		attrs = append(attrs, kv)
+		return true
+	})
+	return attrs
+}
@@ -19,7 +19,9 @@
 //   - Field value of type [context.Context] is used as context when emitting log records.
 //   - For named loggers, LoggerName is used to access [log.Logger] from [log.LoggerProvider]
 //
-// The Level is transformed to the OpenTelemetry Severity types in the following way.
+// The Level is transformed by using the [WithLevelSeverity] option. If the
+// option is not provided then the Level is transformed to the OpenTelemetry
+// Severity types in the following way.
 //
 //   - [zapcore.DebugLevel] is transformed to [log.SeverityDebug]
 //   - [zapcore.InfoLevel] is transformed to [log.SeverityInfo]
@@ -32,6 +34,10 @@
 // Fields are transformed based on their type into log attributes, or
 // into a string value encoded using [fmt.Sprintf] if there is no matching type.
 //
+// The attribute keys are then renamed as configured with [WithKeyRenames],
+// and the record is passed to the function configured with [WithEnricher]
+// before it is emitted.
+//
 // [OpenTelemetry]: https://opentelemetry.io/docs/concepts/signals/logs/
 package otelzap
 
@@ -56,6 +62,13 @@ type config struct {
 	version    string
 	schemaURL  string
 	attributes []attribute.KeyValue
+
+	levelSeverity func(zapcore.Level) log.Severity
+	policy        recordPolicy
+
+	spanEvents           bool
+	spanEventMinSeverity log.Severity
+	spanEventMaxPerSpan  int
 }
 
 func newConfig(options []Option) config {
@@ -68,6 +81,10 @@ func newConfig(options []Option) config {
 		c.provider = global.GetLoggerProvider()
 	}
 
+	if c.levelSeverity == nil {
+		c.levelSeverity = convertLevel
+	}
+
 	return c
 }
 
@@ -121,6 +138,62 @@ func WithLoggerProvider(provider log.LoggerProvider) Option {
 	})
 }
 
+// WithLevelSeverity returns an [Option] that configures the function used to
+// convert zap levels to OpenTelemetry log severities.
+//
+// By default if this Option is not provided, the Core will use a default
+// conversion function. See the package documentation for the conversion.
+func WithLevelSeverity(f func(zapcore.Level) log.Severity) Option {
+	return optFunc(func(c config) config {
+		c.levelSeverity = f
+		return c
+	})
+}
+
+// WithEnricher returns an [Option] that configures a function called with
+// the context of every record emitted by a [Core] before it is emitted, e.g.
+// to add tenant or request attributes from the baggage of the context. The
+// function is called after the attribute keys are renamed with
+// [WithKeyRenames].
+//
+// By default if this Option is not provided, the records are not enriched.
+func WithEnricher(f func(ctx context.Context, record *log.Record)) Option {
+	return optFunc(func(c config) config {
+		c.policy.enrich = f
+		return c
+	})
+}
+
+// WithKeyRenames returns an [Option] that configures the [Core] to rename
+// the keys of the attributes of the records it emits. Each key of renames is
+// replaced with its value. Only the keys of the top-level attributes are
+// renamed.
+//
+// By default if this Option is not provided, the keys are not renamed.
+func WithKeyRenames(renames map[string]string) Option {
+	return optFunc(func(c config) config {
+		c.policy.renames = renames
+		return c
+	})
+}
+
+// WithSpanEvents returns an [Option] that configures the [Core] to also add
+// the log records with a severity of at least minSeverity as events to the
+// recording span of the context they are logged with. The name of an event is
+// the body of the record, and its attributes are the severity, the error, and
+// the attributes of the record. At most maxPerSpan events are added to a
+// span, or an unlimited number if maxPerSpan is not positive.
+//
+// By default if this Option is not provided, no span events are added.
+func WithSpanEvents(minSeverity log.Severity, maxPerSpan int) Option {
+	return optFunc(func(c config) config {
+		c.spanEvents = true
+		c.spanEventMinSeverity = minSeverity
+		c.spanEventMaxPerSpan = maxPerSpan
+		return c
+	})
+}
+
 // Core is a [zapcore.Core] that sends logging records to OpenTelemetry.
 type Core struct {
 	provider log.LoggerProvider
@@ -129,6 +202,10 @@ type Core struct {
 	attr     []attribute.KeyValue
 	ctx      context.Context
 	err      error
+
+	levelSeverity func(zapcore.Level) log.Severity
+	policy        recordPolicy
+	spanEvents    *spanEvents
 }
 
 // Compile-time check *Core implements zapcore.Core.
@@ -153,17 +230,24 @@ func NewCore(name string, opts ...Option) *Core {
 
 	logger := cfg.provider.Logger(name, loggerOpts...)
 
-	return &Core{
+	core := &Core{
 		provider: cfg.provider,
 		logger:   logger,
 		opts:     loggerOpts,
 		ctx:      context.Background(),
+
+		levelSeverity: cfg.levelSeverity,
+		policy:        cfg.policy,
 	}
+	if cfg.spanEvents {
+		core.spanEvents = newSpanEvents(cfg.spanEventMinSeverity, cfg.spanEventMaxPerSpan)
+	}
+	return core
 }
 
 // Enabled decides whether a given logging level is enabled when logging a message.
 func (o *Core) Enabled(level zapcore.Level) bool {
-	param := log.EnabledParameters{Severity: convertLevel(level)}
+	param := log.EnabledParameters{Severity: o.levelSeverity(level)}
 	return o.logger.Enabled(context.Background(), param)
 }
 
@@ -191,6 +275,10 @@ func (o *Core) clone() *Core {
 		attr:     slices.Clone(o.attr),
 		ctx:      o.ctx,
 		err:      o.err,
+
+		levelSeverity: o.levelSeverity,
+		policy:        o.policy,
+		spanEvents:    o.spanEvents,
 	}
 }
 
@@ -202,7 +290,7 @@ func (*Core) Sync() error {
 // Check determines whether the supplied Entry should be logged.
 // If the entry should be logged, the Core adds itself to the CheckedEntry and returns the result.
 func (o *Core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
-	param := log.EnabledParameters{Severity: convertLevel(ent.Level)}
+	param := log.EnabledParameters{Severity: o.levelSeverity(ent.Level)}
 
 	logger := o.logger
 	if ent.LoggerName != "" {
@@ -220,7 +308,7 @@ func (o *Core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
 	r := log.Record{}
 	r.SetTimestamp(ent.Time)
 	r.SetBody(attribute.StringValue(ent.Message))
-	r.SetSeverity(convertLevel(ent.Level))
+	r.SetSeverity(o.levelSeverity(ent.Level))
 	r.SetSeverityText(ent.Level.String())
 
 	emitCtx := o.ctx
@@ -261,7 +349,9 @@ func (o *Core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
 	if ent.LoggerName != "" {
 		logger = o.provider.Logger(ent.LoggerName, o.opts...)
 	}
+	r = o.policy.apply(emitCtx, r)
 	logger.Emit(emitCtx, r)
+	o.spanEvents.add(emitCtx, r)
 	return nil
 }
 
@@ -13,11 +13,13 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
 	"go.opentelemetry.io/otel/log"
 	"go.opentelemetry.io/otel/log/embedded"
 	"go.opentelemetry.io/otel/log/global"
 	"go.opentelemetry.io/otel/log/logtest"
 	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -680,3 +682,58 @@ func TestCoreMalformedErrorFieldDoesNotPanic(t *testing.T) {
 		})
 	}
 }
+
+func TestCoreSpanEvents(t *testing.T) {
+	span := &eventSpan{id: trace.SpanID{0x01}}
+	ctx := trace.ContextWithSpan(t.Context(), span)
+
+	rec := logtest.NewRecorder()
+	logger := zap.New(NewCore(loggerName, WithLoggerProvider(rec), WithSpanEvents(log.SeverityWarn, 0)))
+	logger.Info(testMessage, zap.Any("ctx", ctx))
+	logger.Error(testMessage, zap.Any("ctx", ctx), zap.String(testKey, testValue))
+
+	require.Len(t, span.events, 1)
+	require.Equal(t, testMessage, span.events[0].name)
+	require.Contains(t, span.events[0].config.Attributes(), attribute.String(testKey, testValue))
+}
+
+func TestCorePolicy(t *testing.T) {
+	member, err := baggage.NewMember("tenant", "acme")
+	require.NoError(t, err)
+	bag, err := baggage.New(member)
+	require.NoError(t, err)
+	ctx := baggage.ContextWithBaggage(t.Context(), bag)
+
+	rec := logtest.NewRecorder(logtest.WithEnabledFunc(func(_ context.Context, param log.EnabledParameters) bool {
+		return param.Severity >= log.SeverityError
+	}))
+	core := NewCore(loggerName,
+		WithLoggerProvider(rec),
+		WithLevelSeverity(func(l zapcore.Level) log.Severity {
+			if l >= zapcore.WarnLevel {
+				return log.SeverityError
+			}
+			return log.SeverityDebug
+		}),
+		WithEnricher(func(ctx context.Context, r *log.Record) {
+			b := baggage.FromContext(ctx)
+			r.AddAttributes(attribute.String("tenant.id", b.Member("tenant").Value()))
+		}),
+		WithKeyRenames(map[string]string{"user": "user.name"}),
+	)
+	require.False(t, core.Enabled(zapcore.InfoLevel))
+	require.True(t, core.Enabled(zapcore.WarnLevel))
+
+	logger := zap.New(core)
+	logger.Info(testMessage)
+	logger.Warn(testMessage, zap.Any("ctx", ctx), zap.String("user", "alice"), zap.String(testKey, testValue))
+
+	records := rec.Result()[logtest.Scope{Name: loggerName}]
+	require.Len(t, records, 1)
+	require.Equal(t, log.SeverityError, records[0].Severity)
+	require.Equal(t, []attribute.KeyValue{
+		attribute.String("user.name", "alice"),
+		attribute.String(testKey, testValue),
+		attribute.String("tenant.id", "acme"),
+	}, records[0].Attributes)
+}
@@ -0,0 +1,321 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelzerolog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Fields added by the Writer hook to carry the trace context of an event.
+const (
+	traceIDKey    = "trace_id"
+	spanIDKey     = "span_id"
+	traceFlagsKey = "trace_flags"
+)
+
+var errNotObject = errors.New("otelzerolog: event is not a JSON object")
+
+// event holds the decoded fields of a zerolog event that have a dedicated
+// representation in a log record.
+type event struct {
+	level    *zerolog.Level
+	message  string
+	time     time.Time
+	caller   string
+	err      string
+	hasErr   bool
+	stack    string
+	hasStack bool
+	trace    map[string]string
+	attrs    []attribute.KeyValue
+}
+
+// convertEvent decodes the JSON encoded zerolog event p into a log record
+// and the context to emit it with. If level is nil, the level of the event is
+// read from its level field.
+func convertEvent(p []byte, level *zerolog.Level) (context.Context, log.Record, error) {
+	dec := json.NewDecoder(bytes.NewReader(p))
+	dec.UseNumber()
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, log.Record{}, err
+	}
+	if tok != json.Delim('{') {
+		return nil, log.Record{}, errNotObject
+	}
+
+	e := event{level: level}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, log.Record{}, err
+		}
+		key, _ := tok.(string)
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, log.Record{}, err
+		}
+		if err := e.decodeField(key, raw); err != nil {
+			return nil, log.Record{}, err
+		}
+	}
+	return e.record()
+}
+
+func (e *event) decodeField(key string, raw json.RawMessage) error {
+	switch key {
+	case zerolog.LevelFieldName:
+		if e.level == nil {
+			if lvl, err := zerolog.ParseLevel(rawString(raw)); err == nil {
+				e.level = &lvl
+			}
+		}
+		return nil
+	case zerolog.MessageFieldName:
+		e.message = rawString(raw)
+		return nil
+	case zerolog.TimestampFieldName:
+		if t, ok := parseTime(raw); ok {
+			e.time = t
+			return nil
+		}
+	case zerolog.CallerFieldName:
+		e.caller = rawString(raw)
+		return nil
+	case zerolog.ErrorFieldName:
+		e.err, e.hasErr = rawString(raw), true
+		return nil
+	case zerolog.ErrorStackFieldName:
+		e.stack, e.hasStack = rawString(raw), true
+		return nil
+	case traceIDKey, spanIDKey, traceFlagsKey:
+		if e.trace == nil {
+			e.trace = make(map[string]string, 3)
+		}
+		e.trace[key] = rawString(raw)
+		return nil
+	}
+
+	v, err := decodeValue(raw)
+	if err != nil {
+		return err
+	}
+	e.attrs = append(e.attrs, attribute.KeyValue{Key: attribute.Key(key), Value: v})
+	return nil
+}
+
+func (e *event) record() (context.Context, log.Record, error) {
+	var r log.Record
+	if !e.time.IsZero() {
+		r.SetTimestamp(e.time)
+	}
+	r.SetBody(attribute.StringValue(e.message))
+	if e.level != nil {
+		r.SetSeverity(convertLevel(*e.level))
+		r.SetSeverityText(e.level.String())
+	}
+
+	if e.caller != "" {
+		r.AddAttributes(callerAttributes(e.caller)...)
+	}
+	if e.hasStack {
+		stacktraceKey := semconv.CodeStacktraceKey
+		if e.hasErr {
+			stacktraceKey = semconv.ExceptionStacktraceKey
+		}
+		r.AddAttributes(attribute.String(string(stacktraceKey), e.stack))
+	}
+	if e.hasErr {
+		r.AddAttributes(attribute.String(string(semconv.ExceptionMessageKey), e.err))
+	}
+	r.AddAttributes(e.attrs...)
+
+	ctx := context.Background()
+	if sc, ok := e.spanContext(); ok {
+		ctx = trace.ContextWithSpanContext(ctx, sc)
+	} else {
+		// Not a trace context added by the hook, keep the fields as is.
+		for _, k := range []string{traceIDKey, spanIDKey, traceFlagsKey} {
+			if v, ok := e.trace[k]; ok {
+				r.AddAttributes(attribute.String(k, v))
+			}
+		}
+	}
+	return ctx, r, nil
+}
+
+func (e *event) spanContext() (trace.SpanContext, bool) {
+	if e.trace == nil {
+		return trace.SpanContext{}, false
+	}
+	traceID, err := trace.TraceIDFromHex(e.trace[traceIDKey])
+	if err != nil {
+		return trace.SpanContext{}, false
+	}
+	spanID, err := trace.SpanIDFromHex(e.trace[spanIDKey])
+	if err != nil {
+		return trace.SpanContext{}, false
+	}
+	var flags trace.TraceFlags
+	if s, ok := e.trace[traceFlagsKey]; ok {
+		f, err := strconv.ParseUint(s, 16, 8)
+		if err != nil {
+			return trace.SpanContext{}, false
+		}
+		flags = trace.TraceFlags(f)
+	}
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: flags,
+	}), true
+}
+
+// callerAttributes returns the code attributes for a caller formatted by the
+// default zerolog.CallerMarshalFunc as file:line.
+func callerAttributes(caller string) []attribute.KeyValue {
+	if i := strings.LastIndexByte(caller, ':'); i > 0 {
+		if line, err := strconv.Atoi(caller[i+1:]); err == nil {
+			return []attribute.KeyValue{
+				attribute.String(string(semconv.CodeFilePathKey), caller[:i]),
+				attribute.Int(string(semconv.CodeLineNumberKey), line),
+			}
+		}
+	}
+	return []attribute.KeyValue{attribute.String(string(semconv.CodeFilePathKey), caller)}
+}
+
+// parseTime parses a timestamp encoded according to zerolog.TimeFieldFormat.
+func parseTime(raw json.RawMessage) (time.Time, bool) {
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return time.Time{}, false
+	}
+
+	switch v := v.(type) {
+	case string:
+		format := zerolog.TimeFieldFormat
+		switch format {
+		case zerolog.TimeFormatUnix, zerolog.TimeFormatUnixMs, zerolog.TimeFormatUnixMicro, zerolog.TimeFormatUnixNano:
+			format = time.RFC3339
+		}
+		t, err := time.Parse(format, v)
+		return t, err == nil
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return time.Time{}, false
+		}
+		switch zerolog.TimeFieldFormat {
+		case zerolog.TimeFormatUnix:
+			return time.Unix(0, int64(f*float64(time.Second))), true
+		case zerolog.TimeFormatUnixMs:
+			return time.UnixMilli(int64(f)), true
+		case zerolog.TimeFormatUnixMicro:
+			return time.UnixMicro(int64(f)), true
+		case zerolog.TimeFormatUnixNano:
+			if n, err := v.Int64(); err == nil {
+				return time.Unix(0, n), true
+			}
+			return time.Unix(0, int64(f)), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// rawString returns the string encoded by raw, or raw itself if it does not
+// encode a string.
+func rawString(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
+
+func decodeValue(raw json.RawMessage) (attribute.Value, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	return decodeNext(dec)
+}
+
+func decodeNext(dec *json.Decoder) (attribute.Value, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return attribute.Value{}, err
+	}
+
+	switch tok := tok.(type) {
+	case json.Delim:
+		switch tok {
+		case '{':
+			var kvs []attribute.KeyValue
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return attribute.Value{}, err
+				}
+				key, _ := keyTok.(string)
+				v, err := decodeNext(dec)
+				if err != nil {
+					return attribute.Value{}, err
+				}
+				kvs = append(kvs, attribute.KeyValue{Key: attribute.Key(key), Value: v})
+			}
+			_, err = dec.Token() // Consume '}'.
+			return attribute.MapValue(kvs...), err
+		case '[':
+			var vals []attribute.Value
+			for dec.More() {
+				v, err := decodeNext(dec)
+				if err != nil {
+					return attribute.Value{}, err
+				}
+				vals = append(vals, v)
+			}
+			_, err = dec.Token() // Consume ']'.
+			return attribute.SliceValue(vals...), err
+		}
+	case string:
+		return attribute.StringValue(tok), nil
+	case bool:
+		return attribute.BoolValue(tok), nil
+	case json.Number:
+		return convertNumber(tok), nil
+	case nil:
+		return attribute.Value{}, nil
+	}
+	return attribute.StringValue(fmt.Sprint(tok)), nil
+}
+
+func convertNumber(n json.Number) attribute.Value {
+	if i, err := n.Int64(); err == nil {
+		return attribute.Int64Value(i)
+	}
+	if u, err := strconv.ParseUint(n.String(), 10, 64); err == nil && u > math.MaxInt64 {
+		// Too large for an Int64 value, keep the exact value.
+		return attribute.StringValue(n.String())
+	}
+	if f, err := n.Float64(); err == nil {
+		return attribute.Float64Value(f)
+	}
+	return attribute.StringValue(n.String())
+}
@@ -0,0 +1,27 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelzerolog_test
+
+import (
+	"os"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/log/noop"
+
+	"go.opentelemetry.io/contrib/bridges/otelzerolog"
+)
+
+func Example() {
+	// Use a working LoggerProvider implementation instead e.g. using go.opentelemetry.io/otel/sdk/log.
+	provider := noop.NewLoggerProvider()
+
+	// Create an *otelzerolog.Writer and use it in your application.
+	w := otelzerolog.NewWriter("my/pkg/name", otelzerolog.WithLoggerProvider(provider))
+
+	// Use the writer both as an output, here along with the standard error,
+	// and as a hook to record the trace context of events.
+	logger := zerolog.New(zerolog.MultiLevelWriter(os.Stderr, w)).Hook(w)
+
+	logger.Info().Str("key", "value").Msg("Hello, World!")
+}
@@ -0,0 +1,7 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelzerolog
+
+// Version is the current release version of the otelzerolog bridge.
+const Version = "0.20.0"
@@ -0,0 +1,239 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package otelzerolog provides a [Writer], a [zerolog.LevelWriter] and
+// [zerolog.Hook] implementation that can be used to bridge between the
+// [github.com/rs/zerolog] API and [OpenTelemetry].
+//
+// The Writer needs to be set both as the output and as a hook of the
+// [zerolog.Logger] to record the trace context of events:
+//
+//	w := otelzerolog.NewWriter("my/pkg/name")
+//	logger := zerolog.New(w).Hook(w)
+//
+// As a hook, the Writer adds the trace_id, span_id, and trace_flags fields
+// for the span context carried by the context of the event set with
+// [zerolog.Event.Ctx] or [zerolog.Context.Ctx]. As an output, it decodes the
+// JSON encoded events and emits them as OpenTelemetry log records, using
+// these fields as the trace context of the records. The Writer can be
+// combined with other outputs using [zerolog.MultiLevelWriter].
+//
+// # Record Conversion
+//
+// The zerolog events are converted to OpenTelemetry [log.Record] in the
+// following way:
+//
+//   - The [zerolog.TimestampFieldName] field is set as the Timestamp.
+//   - The [zerolog.MessageFieldName] field is set as the Body using an
+//     [attribute.StringValue].
+//   - Level is transformed and set as the Severity. The SeverityText is also
+//     set.
+//   - The [zerolog.CallerFieldName] field is set as the code.file.path and
+//     code.line.number attributes.
+//   - The [zerolog.ErrorFieldName] field is set as the exception.message
+//     attribute.
+//   - The [zerolog.ErrorStackFieldName] field is set as the
+//     exception.stacktrace attribute if the event has an error, or as the
+//     code.stacktrace attribute otherwise.
+//   - The trace_id, span_id, and trace_flags fields are used as the trace
+//     context of the record.
+//   - All other fields are transformed and set as the attributes. Nested
+//     dictionaries are transformed into [attribute.MAP] values and arrays
+//     into [attribute.SLICE] values. Numbers are transformed into
+//     [attribute.INT64] values if they are integers, or [attribute.FLOAT64]
+//     values otherwise.
+//
+// The Level is transformed to the OpenTelemetry Severity types in the
+// following way:
+//
+//   - [zerolog.TraceLevel] is transformed to [log.SeverityTrace]
+//   - [zerolog.DebugLevel] is transformed to [log.SeverityDebug]
+//   - [zerolog.InfoLevel] is transformed to [log.SeverityInfo]
+//   - [zerolog.WarnLevel] is transformed to [log.SeverityWarn]
+//   - [zerolog.ErrorLevel] is transformed to [log.SeverityError]
+//   - [zerolog.FatalLevel] is transformed to [log.SeverityFatal]
+//   - [zerolog.PanicLevel] is transformed to [log.SeverityFatal4]
+//
+// Events encoded with the binary_log build tag are not supported.
+//
+// [OpenTelemetry]: https://opentelemetry.io/docs/concepts/signals/logs/
+package otelzerolog
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type config struct {
+	provider   log.LoggerProvider
+	version    string
+	schemaURL  string
+	attributes []attribute.KeyValue
+}
+
+func newConfig(options []Option) config {
+	var c config
+	for _, opt := range options {
+		c = opt.apply(c)
+	}
+
+	if c.provider == nil {
+		c.provider = global.GetLoggerProvider()
+	}
+
+	return c
+}
+
+func (c config) logger(name string) log.Logger {
+	var opts []log.LoggerOption
+	if c.version != "" {
+		opts = append(opts, log.WithInstrumentationVersion(c.version))
+	}
+	if c.schemaURL != "" {
+		opts = append(opts, log.WithSchemaURL(c.schemaURL))
+	}
+	if c.attributes != nil {
+		opts = append(opts, log.WithInstrumentationAttributes(c.attributes...))
+	}
+	return c.provider.Logger(name, opts...)
+}
+
+// Option configures a [Writer].
+type Option interface {
+	apply(config) config
+}
+
+type optFunc func(config) config
+
+func (f optFunc) apply(c config) config { return f(c) }
+
+// WithVersion returns an [Option] that configures the version of the
+// [log.Logger] used by a [Writer]. The version should be the version of the
+// package that is being logged.
+func WithVersion(version string) Option {
+	return optFunc(func(c config) config {
+		c.version = version
+		return c
+	})
+}
+
+// WithSchemaURL returns an [Option] that configures the semantic convention
+// schema URL of the [log.Logger] used by a [Writer]. The schemaURL should be
+// the schema URL for the semantic conventions used in log records.
+func WithSchemaURL(schemaURL string) Option {
+	return optFunc(func(c config) config {
+		c.schemaURL = schemaURL
+		return c
+	})
+}
+
+// WithAttributes returns an [Option] that configures the instrumentation scope
+// attributes of the [log.Logger] used by a [Writer].
+func WithAttributes(attributes ...attribute.KeyValue) Option {
+	return optFunc(func(c config) config {
+		c.attributes = attributes
+		return c
+	})
+}
+
+// WithLoggerProvider returns an [Option] that configures [log.LoggerProvider]
+// used by a [Writer].
+//
+// By default if this Option is not provided, the Writer will use the global
+// LoggerProvider.
+func WithLoggerProvider(provider log.LoggerProvider) Option {
+	return optFunc(func(c config) config {
+		c.provider = provider
+		return c
+	})
+}
+
+// Writer is a [zerolog.LevelWriter] and a [zerolog.Hook] that sends all the
+// events it receives to OpenTelemetry. See package documentation for how
+// conversions are made.
+type Writer struct {
+	logger log.Logger
+}
+
+// Compile-time check *Writer implements zerolog.LevelWriter and zerolog.Hook.
+var (
+	_ zerolog.LevelWriter = (*Writer)(nil)
+	_ zerolog.Hook        = (*Writer)(nil)
+)
+
+// NewWriter returns a new [Writer] to be used as the output and a hook of a
+// [zerolog.Logger].
+//
+// If [WithLoggerProvider] is not provided, the returned Writer will use the
+// global LoggerProvider.
+func NewWriter(name string, options ...Option) *Writer {
+	cfg := newConfig(options)
+	return &Writer{logger: cfg.logger(name)}
+}
+
+// Run adds the trace context carried by the context of e to e.
+func (w *Writer) Run(e *zerolog.Event, level zerolog.Level, _ string) {
+	ctx := e.GetCtx()
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() || !w.enabled(ctx, level) {
+		return
+	}
+	e.Str(traceIDKey, sc.TraceID().String()).
+		Str(spanIDKey, sc.SpanID().String()).
+		Str(traceFlagsKey, sc.TraceFlags().String())
+}
+
+// Write decodes the JSON encoded event p and sends it to OpenTelemetry. The
+// level of the event is read from its [zerolog.LevelFieldName] field.
+func (w *Writer) Write(p []byte) (int, error) {
+	return w.write(p, nil)
+}
+
+// WriteLevel decodes the JSON encoded event p and sends it to OpenTelemetry
+// with level.
+func (w *Writer) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	return w.write(p, &level)
+}
+
+func (w *Writer) write(p []byte, level *zerolog.Level) (int, error) {
+	ctx, r, err := convertEvent(p, level)
+	if err != nil {
+		return 0, err
+	}
+	if w.logger.Enabled(ctx, log.EnabledParameters{Severity: r.Severity()}) {
+		w.logger.Emit(ctx, r)
+	}
+	return len(p), nil
+}
+
+func (w *Writer) enabled(ctx context.Context, level zerolog.Level) bool {
+	return w.logger.Enabled(ctx, log.EnabledParameters{Severity: convertLevel(level)})
+}
+
+func convertLevel(level zerolog.Level) log.Severity {
+	switch level {
+	case zerolog.PanicLevel:
+		// PanicLevel is not supported by OpenTelemetry, use Fatal4 as the highest severity.
+		return log.SeverityFatal4
+	case zerolog.FatalLevel:
+		return log.SeverityFatal
+	case zerolog.ErrorLevel:
+		return log.SeverityError
+	case zerolog.WarnLevel:
+		return log.SeverityWarn
+	case zerolog.InfoLevel:
+		return log.SeverityInfo
+	case zerolog.DebugLevel:
+		return log.SeverityDebug
+	case zerolog.TraceLevel:
+		return log.SeverityTrace
+	default:
+		// NoLevel and custom levels have no matching severity.
+		return log.SeverityUndefined
+	}
+}
@@ -0,0 +1,341 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelzerolog
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/embedded"
+	"go.opentelemetry.io/otel/log/global"
+	"go.opentelemetry.io/otel/log/logtest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type mockLoggerProvider struct {
+	embedded.LoggerProvider
+}
+
+func (mockLoggerProvider) Logger(string, ...log.LoggerOption) log.Logger {
+	return nil
+}
+
+func TestNewConfig(t *testing.T) {
+	customLoggerProvider := mockLoggerProvider{}
+
+	for _, tt := range []struct {
+		name    string
+		options []Option
+
+		wantConfig config
+	}{
+		{
+			name: "with no options",
+
+			wantConfig: config{
+				provider: global.GetLoggerProvider(),
+			},
+		},
+		{
+			name: "with a custom instrumentation scope",
+			options: []Option{
+				WithVersion("42.0"),
+			},
+
+			wantConfig: config{
+				version:  "42.0",
+				provider: global.GetLoggerProvider(),
+			},
+		},
+		{
+			name: "with a custom logger provider",
+			options: []Option{
+				WithLoggerProvider(customLoggerProvider),
+			},
+
+			wantConfig: config{
+				provider: customLoggerProvider,
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantConfig, newConfig(tt.options))
+		})
+	}
+}
+
+func TestNewWriter(t *testing.T) {
+	const name = "name"
+	provider := global.GetLoggerProvider()
+
+	w := NewWriter(name,
+		WithVersion("42.1"),
+		WithSchemaURL("https://example.com"),
+		WithAttributes(attribute.String("testattr", "testval")),
+	)
+	want := provider.Logger(
+		name,
+		log.WithInstrumentationVersion("42.1"),
+		log.WithSchemaURL("https://example.com"),
+		log.WithInstrumentationAttributes(attribute.String("testattr", "testval")),
+	)
+	assert.Equal(t, want, w.logger)
+}
+
+func TestWriter(t *testing.T) {
+	const name = "name"
+	now := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	errTest := errors.New("test error")
+
+	for _, tt := range []struct {
+		name string
+		log  func(zerolog.Logger)
+
+		want logtest.Recording
+	}{
+		{
+			name: "message and level",
+			log: func(l zerolog.Logger) {
+				l.Warn().Msg("hello")
+			},
+			want: logtest.Recording{
+				logtest.Scope{Name: name}: {{
+					Severity:     log.SeverityWarn,
+					SeverityText: "warn",
+					Body:         attribute.StringValue("hello"),
+				}},
+			},
+		},
+		{
+			name: "without level",
+			log: func(l zerolog.Logger) {
+				l.Log().Msg("hello")
+			},
+			want: logtest.Recording{
+				logtest.Scope{Name: name}: {{
+					Body: attribute.StringValue("hello"),
+				}},
+			},
+		},
+		{
+			name: "timestamp",
+			log: func(l zerolog.Logger) {
+				l.Info().Time(zerolog.TimestampFieldName, now).Send()
+			},
+			want: logtest.Recording{
+				logtest.Scope{Name: name}: {{
+					Timestamp:    now,
+					Severity:     log.SeverityInfo,
+					SeverityText: "info",
+					Body:         attribute.StringValue(""),
+				}},
+			},
+		},
+		{
+			name: "typed fields",
+			log: func(l zerolog.Logger) {
+				l.Debug().
+					Str("string", "str").
+					Int("int", 42).
+					Uint64("uint64", 1<<63).
+					Float64("float", 1.5).
+					Bool("bool", true).
+					Strs("strings", []string{"a", "b"}).
+					Interface("nil", nil).
+					Msg("fields")
+			},
+			want: logtest.Recording{
+				logtest.Scope{Name: name}: {{
+					Severity:     log.SeverityDebug,
+					SeverityText: "debug",
+					Body:         attribute.StringValue("fields"),
+					Attributes: []attribute.KeyValue{
+						attribute.String("string", "str"),
+						attribute.Int("int", 42),
+						attribute.String("uint64", "9223372036854775808"),
+						attribute.Float64("float", 1.5),
+						attribute.Bool("bool", true),
+						{Key: "strings", Value: attribute.SliceValue(attribute.StringValue("a"), attribute.StringValue("b"))},
+						{Key: "nil", Value: attribute.Value{}},
+					},
+				}},
+			},
+		},
+		{
+			name: "nested dictionary",
+			log: func(l zerolog.Logger) {
+				l = l.With().Str("component", "api").Logger()
+				l.Info().
+					Dict("request", zerolog.Dict().
+						Str("method", "GET").
+						Dict("header", zerolog.Dict().Int("size", 3)),
+					).
+					Msg("dict")
+			},
+			want: logtest.Recording{
+				logtest.Scope{Name: name}: {{
+					Severity:     log.SeverityInfo,
+					SeverityText: "info",
+					Body:         attribute.StringValue("dict"),
+					Attributes: []attribute.KeyValue{
+						attribute.String("component", "api"),
+						{Key: "request", Value: attribute.MapValue(
+							attribute.String("method", "GET"),
+							attribute.KeyValue{Key: "header", Value: attribute.MapValue(attribute.Int("size", 3))},
+						)},
+					},
+				}},
+			},
+		},
+		{
+			name: "error and caller",
+			log: func(l zerolog.Logger) {
+				l.Error().Err(errTest).Str(zerolog.ErrorStackFieldName, "stack").Str(zerolog.CallerFieldName, "/app/main.go:42").Msg("failed")
+			},
+			want: logtest.Recording{
+				logtest.Scope{Name: name}: {{
+					Severity:     log.SeverityError,
+					SeverityText: "error",
+					Body:         attribute.StringValue("failed"),
+					Attributes: []attribute.KeyValue{
+						attribute.String("code.file.path", "/app/main.go"),
+						attribute.Int("code.line.number", 42),
+						attribute.String("exception.stacktrace", "stack"),
+						attribute.String("exception.message", "test error"),
+					},
+				}},
+			},
+		},
+		{
+			name: "stack without error",
+			log: func(l zerolog.Logger) {
+				l.Panic().Str(zerolog.ErrorStackFieldName, "stack").Msg("stack")
+			},
+			want: logtest.Recording{
+				logtest.Scope{Name: name}: {{
+					Severity:     log.SeverityFatal4,
+					SeverityText: "panic",
+					Body:         attribute.StringValue("stack"),
+					Attributes: []attribute.KeyValue{
+						attribute.String("code.stacktrace", "stack"),
+					},
+				}},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := logtest.NewRecorder()
+			w := NewWriter(name, WithLoggerProvider(rec))
+			l := zerolog.New(w).Hook(w)
+
+			func() {
+				defer func() { _ = recover() }()
+				tt.log(l)
+			}()
+
+			got := rec.Result()
+			for _, records := range got {
+				for i := range records {
+					records[i].Context = nil
+				}
+			}
+			logtest.AssertEqual(t, tt.want, got)
+		})
+	}
+}
+
+func TestWriterTraceContext(t *testing.T) {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01},
+		SpanID:     trace.SpanID{0x02},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(t.Context(), sc)
+
+	rec := logtest.NewRecorder()
+	w := NewWriter("name", WithLoggerProvider(rec))
+	l := zerolog.New(w).Hook(w)
+
+	l.Info().Ctx(ctx).Msg("with span")
+	l.Info().Msg("without span")
+
+	records := rec.Result()[logtest.Scope{Name: "name"}]
+	require.Len(t, records, 2)
+	got := trace.SpanContextFromContext(records[0].Context)
+	assert.Equal(t, sc, got)
+	assert.Empty(t, records[0].Attributes)
+	assert.False(t, trace.SpanContextFromContext(records[1].Context).IsValid())
+}
+
+func TestWriterEnabled(t *testing.T) {
+	rec := logtest.NewRecorder(logtest.WithEnabledFunc(func(_ context.Context, p log.EnabledParameters) bool {
+		return p.Severity >= log.SeverityWarn
+	}))
+	w := NewWriter("name", WithLoggerProvider(rec))
+	l := zerolog.New(w).Hook(w)
+
+	l.Info().Msg("dropped")
+	l.Error().Msg("emitted")
+
+	records := rec.Result()[logtest.Scope{Name: "name"}]
+	require.Len(t, records, 1)
+	assert.Equal(t, attribute.StringValue("emitted"), records[0].Body)
+}
+
+func TestWriterInvalidEvent(t *testing.T) {
+	w := NewWriter("name", WithLoggerProvider(logtest.NewRecorder()))
+
+	_, err := w.Write([]byte("not json"))
+	assert.Error(t, err)
+
+	_, err = w.Write([]byte(`["array"]`))
+	assert.ErrorIs(t, err, errNotObject)
+}
+
+func TestConvertLevel(t *testing.T) {
+	for level, want := range map[zerolog.Level]log.Severity{
+		zerolog.TraceLevel: log.SeverityTrace,
+		zerolog.DebugLevel: log.SeverityDebug,
+		zerolog.InfoLevel:  log.SeverityInfo,
+		zerolog.WarnLevel:  log.SeverityWarn,
+		zerolog.ErrorLevel: log.SeverityError,
+		zerolog.FatalLevel: log.SeverityFatal,
+		zerolog.PanicLevel: log.SeverityFatal4,
+		zerolog.NoLevel:    log.SeverityUndefined,
+	} {
+		assert.Equal(t, want, convertLevel(level), level.String())
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2024, 5, 6, 7, 8, 9, 123456789, time.UTC)
+	for _, tt := range []struct {
+		format string
+		raw    string
+		want   time.Time
+	}{
+		{format: time.RFC3339Nano, raw: `"2024-05-06T07:08:09.123456789Z"`, want: now},
+		{format: zerolog.TimeFormatUnix, raw: `1714979289`, want: now.Truncate(time.Second)},
+		{format: zerolog.TimeFormatUnixMs, raw: `1714979289123`, want: now.Truncate(time.Millisecond)},
+		{format: zerolog.TimeFormatUnixMicro, raw: `1714979289123456`, want: now.Truncate(time.Microsecond)},
+		{format: zerolog.TimeFormatUnixNano, raw: `1714979289123456789`, want: now},
+	} {
+		t.Run(tt.format, func(t *testing.T) {
+			orig := zerolog.TimeFieldFormat
+			t.Cleanup(func() { zerolog.TimeFieldFormat = orig })
+			zerolog.TimeFieldFormat = tt.format
+
+			got, ok := parseTime([]byte(tt.raw))
+			require.True(t, ok)
+			assert.True(t, tt.want.Equal(got), "want %v, got %v", tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,463 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+const (
+	scopeNameLabel    = "otel_scope_name"
+	scopeVersionLabel = "otel_scope_version"
+	counterSuffix     = "_total"
+	ratioSuffix       = "_ratio"
+
+	// maxNativeHistogramScale is the highest schema of Prometheus native
+	// histograms.
+	maxNativeHistogramScale = 8
+	// minNativeHistogramScale is the lowest exponential schema of Prometheus
+	// native histograms.
+	minNativeHistogramScale = -4
+)
+
+// unitSuffixes are the suffixes added to the metric names for their
+// unit, as by the OpenTelemetry Prometheus exporter.
+var unitSuffixes = map[string]string{
+	// Time
+	"d":   "_days",
+	"h":   "_hours",
+	"min": "_minutes",
+	"s":   "_seconds",
+	"ms":  "_milliseconds",
+	"us":  "_microseconds",
+	"ns":  "_nanoseconds",
+
+	// Bytes
+	"By":   "_bytes",
+	"KiBy": "_kibibytes",
+	"MiBy": "_mebibytes",
+	"GiBy": "_gibibytes",
+	"TiBy": "_tibibytes",
+	"KBy":  "_kilobytes",
+	"MBy":  "_megabytes",
+	"GBy":  "_gigabytes",
+	"TBy":  "_terabytes",
+
+	// SI
+	"m": "_meters",
+	"V": "_volts",
+	"A": "_amperes",
+	"J": "_joules",
+	"W": "_watts",
+	"g": "_grams",
+
+	// Misc
+	"Cel": "_celsius",
+	"Hz":  "_hertz",
+	"%":   "_percent",
+}
+
+var (
+	errDeltaTemporality = errors.New("delta temporality is not supported")
+	errConflictingType  = errors.New("conflicting metric type")
+	errUnsupportedScale = errors.New("unsupported exponential histogram scale")
+)
+
+// Collector is a [prometheus.Collector] that collects the metrics of an
+// OpenTelemetry SDK [metric.Reader] or [metric.Producer]. It allows serving
+// the metrics of OpenTelemetry instruments from an existing
+// [prometheus.Registry], along with the metrics of Prometheus instruments.
+//
+// The metrics are converted in the following way:
+//
+//   - Gauges and non-monotonic sums are converted to gauges. The "_ratio"
+//     suffix is added to the name of the gauges with the unit "1".
+//   - Monotonic sums are converted to counters, with the "_total" suffix
+//     added to their name.
+//   - Histograms are converted to histograms.
+//   - Exponential histograms are converted to native histograms. Their scale
+//     is reduced to the highest schema of native histograms if needed.
+//   - Summaries are converted to summaries.
+//   - Attributes are converted to labels. The instrumentation scope name and
+//     version are added as the otel_scope_name and otel_scope_version labels,
+//     the attributes converted to the same labels are dropped.
+//
+// A suffix is added to the metric names for their unit, e.g. "_seconds" for
+// "s" and "_bytes" for "By", as by the OpenTelemetry Prometheus exporter.
+// Annotations and unknown units are not added.
+//
+// Invalid characters in metric names and label names are replaced with "_".
+// Metrics with a delta temporality are not supported and are dropped.
+// Metrics with the same name from different instrumentation scopes are
+// collected in the same metric family, using the description of the first
+// one. The ones with a different type than the first one are dropped.
+//
+// The Collector is an unchecked collector: it does not describe the metrics
+// it collects in advance.
+type Collector struct {
+	collect func(context.Context) ([]metricdata.ScopeMetrics, error)
+}
+
+// Compile-time check *Collector implements prometheus.Collector.
+var _ prometheus.Collector = (*Collector)(nil)
+
+// NewCollector returns a [Collector] that collects the metrics of reader. The
+// reader needs to be registered with a [metric.MeterProvider], e.g. a
+// [metric.ManualReader] configured with [metric.WithReader]. It should not
+// be used by another exporter, as collecting from it may reset the state of
+// delta aggregations.
+func NewCollector(reader metric.Reader) *Collector {
+	return &Collector{
+		collect: func(ctx context.Context) ([]metricdata.ScopeMetrics, error) {
+			var rm metricdata.ResourceMetrics
+			err := reader.Collect(ctx, &rm)
+			return rm.ScopeMetrics, err
+		},
+	}
+}
+
+// NewProducerCollector returns a [Collector] that collects the metrics
+// produced by producer.
+//
+// The producer must not be a producer returned by [NewMetricProducer]
+// gathering from a registry the Collector is registered with.
+func NewProducerCollector(producer metric.Producer) *Collector {
+	return &Collector{collect: producer.Produce}
+}
+
+// Describe implements [prometheus.Collector]. It does not send any
+// descriptor, making the Collector an unchecked collector.
+func (*Collector) Describe(chan<- *prometheus.Desc) {}
+
+// Collect implements [prometheus.Collector]. It collects the OpenTelemetry
+// metrics and sends them to ch as Prometheus metrics. Errors are handled with
+// the OpenTelemetry error handler.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	scopeMetrics, err := c.collect(context.Background())
+	var errs multierr
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	// families are the metric families already collected, as all the
+	// metrics of a Prometheus metric family need the same type and help.
+	families := make(map[string]family)
+	for _, sm := range scopeMetrics {
+		for _, m := range sm.Metrics {
+			if err := collectMetric(ch, sm.Scope, m, families); err != nil {
+				errs = append(errs, fmt.Errorf("%w for metric %v", err, m.Name))
+			}
+		}
+	}
+	if err := errs.errOrNil(); err != nil {
+		otel.Handle(err)
+	}
+}
+
+type family struct {
+	typ  string
+	help string
+}
+
+func collectMetric(ch chan<- prometheus.Metric, scope instrumentation.Scope, m metricdata.Metrics, families map[string]family) error {
+	name := unitName(sanitizeName(m.Name, true), m.Unit)
+	var typ string
+	switch data := m.Data.(type) {
+	case metricdata.Sum[int64]:
+		if data.IsMonotonic {
+			name, typ = counterName(name), "counter"
+		} else {
+			name, typ = gaugeName(name, m.Unit), "gauge"
+		}
+	case metricdata.Sum[float64]:
+		if data.IsMonotonic {
+			name, typ = counterName(name), "counter"
+		} else {
+			name, typ = gaugeName(name, m.Unit), "gauge"
+		}
+	case metricdata.Gauge[int64], metricdata.Gauge[float64]:
+		name, typ = gaugeName(name, m.Unit), "gauge"
+	case metricdata.Histogram[int64], metricdata.Histogram[float64],
+		metricdata.ExponentialHistogram[int64], metricdata.ExponentialHistogram[float64]:
+		typ = "histogram"
+	case metricdata.Summary:
+		typ = "summary"
+	default:
+		return fmt.Errorf("%w: %T", errUnsupportedType, m.Data)
+	}
+	f, ok := families[name]
+	if !ok {
+		f = family{typ: typ, help: m.Description}
+		families[name] = f
+	} else if f.typ != typ {
+		return fmt.Errorf("%w: %s already collected as %s", errConflictingType, typ, f.typ)
+	}
+	help := f.help
+
+	switch data := m.Data.(type) {
+	case metricdata.Sum[int64]:
+		return collectSum(ch, scope, name, help, data)
+	case metricdata.Sum[float64]:
+		return collectSum(ch, scope, name, help, data)
+	case metricdata.Gauge[int64]:
+		return collectGauge(ch, scope, name, help, data)
+	case metricdata.Gauge[float64]:
+		return collectGauge(ch, scope, name, help, data)
+	case metricdata.Histogram[int64]:
+		return collectHistogram(ch, scope, name, help, data)
+	case metricdata.Histogram[float64]:
+		return collectHistogram(ch, scope, name, help, data)
+	case metricdata.ExponentialHistogram[int64]:
+		return collectExponentialHistogram(ch, scope, name, help, data)
+	case metricdata.ExponentialHistogram[float64]:
+		return collectExponentialHistogram(ch, scope, name, help, data)
+	case metricdata.Summary:
+		return collectSummary(ch, scope, name, help, data)
+	}
+	return nil
+}
+
+func collectSum[N int64 | float64](ch chan<- prometheus.Metric, scope instrumentation.Scope, name, help string, data metricdata.Sum[N]) error {
+	if data.Temporality == metricdata.DeltaTemporality {
+		return errDeltaTemporality
+	}
+	valueType := prometheus.GaugeValue
+	if data.IsMonotonic {
+		valueType = prometheus.CounterValue
+	}
+
+	var errs multierr
+	for _, dp := range data.DataPoints {
+		desc, values := newDesc(scope, name, help, dp.Attributes)
+		var (
+			pm  prometheus.Metric
+			err error
+		)
+		if data.IsMonotonic {
+			pm, err = prometheus.NewConstMetricWithCreatedTimestamp(desc, valueType, float64(dp.Value), dp.StartTime, values...)
+		} else {
+			pm, err = prometheus.NewConstMetric(desc, valueType, float64(dp.Value), values...)
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ch <- pm
+	}
+	return errs.errOrNil()
+}
+
+func collectGauge[N int64 | float64](ch chan<- prometheus.Metric, scope instrumentation.Scope, name, help string, data metricdata.Gauge[N]) error {
+	var errs multierr
+	for _, dp := range data.DataPoints {
+		desc, values := newDesc(scope, name, help, dp.Attributes)
+		pm, err := prometheus.NewConstMetric(desc, prometheus.GaugeValue, float64(dp.Value), values...)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ch <- pm
+	}
+	return errs.errOrNil()
+}
+
+func collectHistogram[N int64 | float64](ch chan<- prometheus.Metric, scope instrumentation.Scope, name, help string, data metricdata.Histogram[N]) error {
+	if data.Temporality == metricdata.DeltaTemporality {
+		return errDeltaTemporality
+	}
+
+	var errs multierr
+	for _, dp := range data.DataPoints {
+		desc, values := newDesc(scope, name, help, dp.Attributes)
+		// Prometheus buckets are cumulative.
+		buckets := make(map[float64]uint64, len(dp.Bounds))
+		var count uint64
+		for i, bound := range dp.Bounds {
+			if i < len(dp.BucketCounts) {
+				count += dp.BucketCounts[i]
+			}
+			buckets[bound] = count
+		}
+		pm, err := prometheus.NewConstHistogramWithCreatedTimestamp(desc, dp.Count, float64(dp.Sum), buckets, dp.StartTime, values...)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ch <- pm
+	}
+	return errs.errOrNil()
+}
+
+func collectExponentialHistogram[N int64 | float64](ch chan<- prometheus.Metric, scope instrumentation.Scope, name, help string, data metricdata.ExponentialHistogram[N]) error {
+	if data.Temporality == metricdata.DeltaTemporality {
+		return errDeltaTemporality
+	}
+
+	var errs multierr
+	for _, dp := range data.DataPoints {
+		if dp.Scale < minNativeHistogramScale {
+			errs = append(errs, fmt.Errorf("%w: %d", errUnsupportedScale, dp.Scale))
+			continue
+		}
+		scale := min(dp.Scale, maxNativeHistogramScale)
+		desc, values := newDesc(scope, name, help, dp.Attributes)
+		pm, err := prometheus.NewConstNativeHistogram(
+			desc,
+			dp.Count,
+			float64(dp.Sum),
+			convertToNativeBuckets(dp.PositiveBucket, dp.Scale-scale),
+			convertToNativeBuckets(dp.NegativeBucket, dp.Scale-scale),
+			dp.ZeroCount,
+			scale,
+			dp.ZeroThreshold,
+			dp.StartTime,
+			values...,
+		)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ch <- pm
+	}
+	return errs.errOrNil()
+}
+
+// convertToNativeBuckets returns the counts of the buckets of an exponential
+// histogram indexed by their native histogram index, after reducing their
+// scale by downscale.
+func convertToNativeBuckets(bucket metricdata.ExponentialBucket, downscale int32) map[int]int64 {
+	buckets := make(map[int]int64, len(bucket.Counts))
+	for i, count := range bucket.Counts {
+		if count == 0 {
+			continue
+		}
+		// Exponential histogram buckets are indexed by lower boundary while
+		// native histogram buckets are indexed by upper boundary, the result
+		// being that the indexes are different-by-one.
+		index := (bucket.Offset + int32(i)) >> downscale
+		buckets[int(index)+1] += int64(count) //nolint:gosec // Bucket counts fit in int64.
+	}
+	return buckets
+}
+
+func collectSummary(ch chan<- prometheus.Metric, scope instrumentation.Scope, name, help string, data metricdata.Summary) error {
+	var errs multierr
+	for _, dp := range data.DataPoints {
+		desc, values := newDesc(scope, name, help, dp.Attributes)
+		quantiles := make(map[float64]float64, len(dp.QuantileValues))
+		for _, q := range dp.QuantileValues {
+			quantiles[q.Quantile] = q.Value
+		}
+		pm, err := prometheus.NewConstSummaryWithCreatedTimestamp(desc, dp.Count, dp.Sum, quantiles, dp.StartTime, values...)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ch <- pm
+	}
+	return errs.errOrNil()
+}
+
+// newDesc returns the descriptor of the Prometheus metric of a data point with
+// attrs and the values of its labels.
+func newDesc(scope instrumentation.Scope, name, help string, attrs attribute.Set) (*prometheus.Desc, []string) {
+	labels := make(map[string]string, attrs.Len())
+	iter := attrs.Iter()
+	for iter.Next() {
+		kv := iter.Attribute()
+		key := sanitizeName(string(kv.Key), false)
+		if key == scopeNameLabel || key == scopeVersionLabel {
+			// The labels of the instrumentation scope take precedence.
+			continue
+		}
+		if v, ok := labels[key]; ok {
+			// Attributes whose keys are sanitized to the same label have
+			// their values concatenated.
+			labels[key] = v + ";" + kv.Value.Emit()
+			continue
+		}
+		labels[key] = kv.Value.Emit()
+	}
+
+	names := make([]string, 0, len(labels)+2)
+	for k := range labels {
+		names = append(names, k)
+	}
+	slices.Sort(names)
+	values := make([]string, len(names), len(names)+2)
+	for i, k := range names {
+		values[i] = labels[k]
+	}
+	names = append(names, scopeNameLabel, scopeVersionLabel)
+	values = append(values, scope.Name, scope.Version)
+
+	return prometheus.NewDesc(name, help, names, nil), values
+}
+
+func counterName(name string) string {
+	return addSuffix(name, counterSuffix)
+}
+
+// gaugeName returns the name of a gauge with unit, with the "_ratio" suffix
+// for the unit "1".
+func gaugeName(name, unit string) string {
+	if unit == "1" {
+		return addSuffix(name, ratioSuffix)
+	}
+	return name
+}
+
+// unitName returns name with the suffix of unit, if it is known.
+func unitName(name, unit string) string {
+	if suffix, ok := unitSuffixes[unit]; ok {
+		return addSuffix(name, suffix)
+	}
+	return name
+}
+
+func addSuffix(name, suffix string) string {
+	if strings.HasSuffix(name, suffix) {
+		return name
+	}
+	return name + suffix
+}
+
+// sanitizeName returns name with the characters that are not valid in a
+// Prometheus metric name, or label name if metric is false, replaced with
+// "_".
+func sanitizeName(name string, metric bool) string {
+	if name == "" {
+		return "_"
+	}
+	var b strings.Builder
+	b.Grow(len(name))
+	for i, r := range name {
+		valid := r == '_' || (r < unicode.MaxASCII && (unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r))))
+		if metric && r == ':' {
+			valid = true
+		}
+		switch {
+		case valid:
+			b.WriteRune(r)
+		case i == 0 && unicode.IsDigit(r):
+			b.WriteRune('_')
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
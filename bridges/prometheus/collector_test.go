@@ -0,0 +1,245 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheus
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestCollector(t *testing.T) {
+	reader := metric.NewManualReader()
+	mp := metric.NewMeterProvider(metric.WithReader(reader))
+	meter := mp.Meter("test/meter", otelmetric.WithInstrumentationVersion("v1.0.0"))
+
+	ctx := t.Context()
+	counter, err := meter.Int64Counter("requests", otelmetric.WithDescription("The number of requests"))
+	require.NoError(t, err)
+	counter.Add(ctx, 3, otelmetric.WithAttributes(attribute.String("http.method", "GET")))
+
+	upDown, err := meter.Float64UpDownCounter("in.flight", otelmetric.WithUnit("{request}"))
+	require.NoError(t, err)
+	upDown.Add(ctx, 2.5)
+
+	gauge, err := meter.Int64Gauge("temperature", otelmetric.WithUnit("Cel"))
+	require.NoError(t, err)
+	gauge.Record(ctx, 21)
+
+	hist, err := meter.Float64Histogram("latency", otelmetric.WithUnit("s"), otelmetric.WithExplicitBucketBoundaries(1, 5))
+	require.NoError(t, err)
+	hist.Record(ctx, 0.5)
+	hist.Record(ctx, 2)
+	hist.Record(ctx, 10)
+
+	promCounter := prometheus.NewCounter(prometheus.CounterOpts{Name: "prom_counter_total", Help: "A prometheus counter"})
+	promCounter.Inc()
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(NewCollector(reader), promCounter)
+
+	const expected = `
+# HELP in_flight 
+# TYPE in_flight gauge
+in_flight{otel_scope_name="test/meter",otel_scope_version="v1.0.0"} 2.5
+# HELP latency_seconds 
+# TYPE latency_seconds histogram
+latency_seconds_bucket{otel_scope_name="test/meter",otel_scope_version="v1.0.0",le="1"} 1
+latency_seconds_bucket{otel_scope_name="test/meter",otel_scope_version="v1.0.0",le="5"} 2
+latency_seconds_bucket{otel_scope_name="test/meter",otel_scope_version="v1.0.0",le="+Inf"} 3
+latency_seconds_sum{otel_scope_name="test/meter",otel_scope_version="v1.0.0"} 12.5
+latency_seconds_count{otel_scope_name="test/meter",otel_scope_version="v1.0.0"} 3
+# HELP prom_counter_total A prometheus counter
+# TYPE prom_counter_total counter
+prom_counter_total 1
+# HELP requests_total The number of requests
+# TYPE requests_total counter
+requests_total{http_method="GET",otel_scope_name="test/meter",otel_scope_version="v1.0.0"} 3
+# HELP temperature_celsius 
+# TYPE temperature_celsius gauge
+temperature_celsius{otel_scope_name="test/meter",otel_scope_version="v1.0.0"} 21
+`
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected)))
+}
+
+func TestCollectorExponentialHistogram(t *testing.T) {
+	reader := metric.NewManualReader()
+	mp := metric.NewMeterProvider(
+		metric.WithReader(reader),
+		metric.WithView(metric.NewView(
+			metric.Instrument{Name: "latency"},
+			metric.Stream{Aggregation: metric.AggregationBase2ExponentialHistogram{MaxSize: 160, MaxScale: 20}},
+		)),
+	)
+	hist, err := mp.Meter("test").Float64Histogram("latency")
+	require.NoError(t, err)
+	// A single value keeps the highest scale, above the highest native
+	// histogram schema.
+	ctx := t.Context()
+	hist.Record(ctx, 1.5)
+	hist.Record(ctx, 0)
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(NewCollector(reader))
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	require.Len(t, families, 1)
+
+	h := families[0].GetMetric()[0].GetHistogram()
+	assert.Equal(t, int32(maxNativeHistogramScale), h.GetSchema())
+	assert.Equal(t, uint64(2), h.GetSampleCount())
+	assert.Equal(t, 1.5, h.GetSampleSum())
+	assert.Equal(t, uint64(1), h.GetZeroCount())
+
+	// 1.5 is in the bucket (2^(149/256), 2^(150/256)] at scale 8.
+	otelHist := convertExponentialHistogram(families[0].GetMetric(), processStartTime, metricdata.CumulativeTemporality)
+	dp := otelHist.DataPoints[0]
+	assert.Equal(t, metricdata.ExponentialBucket{Offset: 149, Counts: []uint64{1}}, dp.PositiveBucket)
+}
+
+func TestConvertToNativeBuckets(t *testing.T) {
+	bucket := metricdata.ExponentialBucket{Offset: -2, Counts: []uint64{1, 0, 2, 3}}
+	assert.Equal(t, map[int]int64{-1: 1, 1: 2, 2: 3}, convertToNativeBuckets(bucket, 0))
+	// Indexes -2, 0, and 1 are merged into -1, 0, and 0 at a lower scale.
+	assert.Equal(t, map[int]int64{0: 1, 1: 5}, convertToNativeBuckets(bucket, 1))
+}
+
+func TestCollectorProducer(t *testing.T) {
+	producer := metric.Producer(producerFunc(func(context.Context) ([]metricdata.ScopeMetrics, error) {
+		return []metricdata.ScopeMetrics{
+			{
+				Scope: instrumentation.Scope{Name: "a"},
+				Metrics: []metricdata.Metrics{
+					{
+						Name:        "1.metric",
+						Description: "First",
+						Data: metricdata.Gauge[float64]{DataPoints: []metricdata.DataPoint[float64]{{
+							Attributes: attribute.NewSet(
+								attribute.String("a.b", "1"),
+								attribute.String("a_b", "2"),
+								// Dropped, colliding with the scope labels.
+								attribute.String("otel.scope.name", "other"),
+								attribute.String("otel_scope_version", "v2"),
+							),
+							Value: 1,
+						}}},
+					},
+					{
+						Name: "delta",
+						Data: metricdata.Sum[int64]{
+							Temporality: metricdata.DeltaTemporality,
+							IsMonotonic: true,
+							DataPoints:  []metricdata.DataPoint[int64]{{Value: 1}},
+						},
+					},
+				},
+			},
+			{
+				Scope: instrumentation.Scope{Name: "b"},
+				Metrics: []metricdata.Metrics{
+					{
+						Name:        "1.metric",
+						Description: "Second",
+						Data:        metricdata.Gauge[int64]{DataPoints: []metricdata.DataPoint[int64]{{Value: 2}}},
+					},
+					{
+						Name: "1.metric",
+						Data: metricdata.Summary{DataPoints: []metricdata.SummaryDataPoint{{Count: 1}}},
+					},
+					{
+						Name: "usage",
+						Unit: "1",
+						Data: metricdata.Gauge[float64]{DataPoints: []metricdata.DataPoint[float64]{{Value: 0.5}}},
+					},
+					{
+						Name: "transferred_bytes",
+						Unit: "By",
+						Data: metricdata.Sum[int64]{
+							Temporality: metricdata.CumulativeTemporality,
+							IsMonotonic: true,
+							DataPoints:  []metricdata.DataPoint[int64]{{Value: 8}},
+						},
+					},
+					{
+						Name: "summary",
+						Data: metricdata.Summary{DataPoints: []metricdata.SummaryDataPoint{{
+							Count:          2,
+							Sum:            3,
+							QuantileValues: []metricdata.QuantileValue{{Quantile: 0.5, Value: 1}},
+						}}},
+					},
+				},
+			},
+		}, nil
+	}))
+
+	previousHandler := otel.GetErrorHandler()
+	t.Cleanup(func() { otel.SetErrorHandler(previousHandler) })
+	var handledErr error
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		handledErr = err
+	}))
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(NewProducerCollector(producer))
+
+	const expected = `
+# HELP _1_metric First
+# TYPE _1_metric gauge
+_1_metric{a_b="1;2",otel_scope_name="a",otel_scope_version=""} 1
+_1_metric{otel_scope_name="b",otel_scope_version=""} 2
+# HELP summary 
+# TYPE summary summary
+summary{otel_scope_name="b",otel_scope_version="",quantile="0.5"} 1
+summary_sum{otel_scope_name="b",otel_scope_version=""} 3
+summary_count{otel_scope_name="b",otel_scope_version=""} 2
+# HELP transferred_bytes_total 
+# TYPE transferred_bytes_total counter
+transferred_bytes_total{otel_scope_name="b",otel_scope_version=""} 8
+# HELP usage_ratio 
+# TYPE usage_ratio gauge
+usage_ratio{otel_scope_name="b",otel_scope_version=""} 0.5
+`
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected)))
+	require.Error(t, handledErr)
+	assert.ErrorContains(t, handledErr, errDeltaTemporality.Error())
+	assert.ErrorContains(t, handledErr, errConflictingType.Error())
+}
+
+func TestSanitizeName(t *testing.T) {
+	assert.Equal(t, "http_server_duration", sanitizeName("http.server.duration", true))
+	assert.Equal(t, "ns:metric", sanitizeName("ns:metric", true))
+	assert.Equal(t, "ns_label", sanitizeName("ns:label", false))
+	assert.Equal(t, "_1_metric", sanitizeName("1.metric", true))
+	assert.Equal(t, "a1", sanitizeName("a1", false))
+	assert.Equal(t, "_", sanitizeName("", false))
+	assert.Equal(t, "caf_", sanitizeName("café", false))
+}
+
+func TestUnitName(t *testing.T) {
+	assert.Equal(t, "latency_seconds", unitName("latency", "s"))
+	assert.Equal(t, "latency_seconds", unitName("latency_seconds", "s"))
+	assert.Equal(t, "size_bytes", unitName("size", "By"))
+	assert.Equal(t, "requests", unitName("requests", "{request}"))
+	assert.Equal(t, "requests", unitName("requests", ""))
+	assert.Equal(t, "usage", unitName("usage", "1"))
+	assert.Equal(t, "usage_ratio", gaugeName("usage", "1"))
+}
+
+type producerFunc func(context.Context) ([]metricdata.ScopeMetrics, error)
+
+func (f producerFunc) Produce(ctx context.Context) ([]metricdata.ScopeMetrics, error) {
+	return f(ctx)
+}
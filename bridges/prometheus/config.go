@@ -4,12 +4,31 @@
 package prometheus
 
 import (
+	"regexp"
+
 	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
 )
 
 // config contains options for the producer.
 type config struct {
-	gatherers []prometheus.Gatherer
+	gatherers []scopedGatherer
+	targets   []ScrapeTarget
+
+	includeMetrics []*regexp.Regexp
+	excludeMetrics []*regexp.Regexp
+	labelFilter    func(metricName string, labels attribute.Set) bool
+	labelRenames   map[string]string
+
+	preferClassicHistograms bool
+}
+
+// scopedGatherer is a prometheus Gatherer along with the instrumentation scope
+// of the metrics gathered from it.
+type scopedGatherer struct {
+	gatherer prometheus.Gatherer
+	scope    instrumentation.Scope
 }
 
 // newConfig creates a validated config configured with options.
@@ -19,8 +38,11 @@ func newConfig(opts ...Option) config {
 		cfg = opt.apply(cfg)
 	}
 
-	if len(cfg.gatherers) == 0 {
-		cfg.gatherers = []prometheus.Gatherer{prometheus.DefaultGatherer}
+	if len(cfg.gatherers) == 0 && len(cfg.targets) == 0 {
+		cfg.gatherers = []scopedGatherer{{
+			gatherer: prometheus.DefaultGatherer,
+			scope:    instrumentation.Scope{Name: scopeName},
+		}}
 	}
 
 	return cfg
@@ -38,10 +60,125 @@ func (fn optionFunc) apply(cfg config) config {
 }
 
 // WithGatherer configures which prometheus Gatherer the Bridge will gather
-// from. If no registerer is used the prometheus DefaultGatherer is used.
+// from. If neither a gatherer nor a scrape target is configured, the
+// prometheus DefaultGatherer is used.
+//
+// The metrics gathered are reported with the instrumentation scope of the
+// Bridge. Use [WithScopedGatherer] to use a different instrumentation scope.
 func WithGatherer(gatherer prometheus.Gatherer) Option {
+	return WithScopedGatherer(gatherer, instrumentation.Scope{Name: scopeName})
+}
+
+// WithScopedGatherer configures a prometheus Gatherer the Bridge will gather
+// from, like [WithGatherer], and the instrumentation scope the metrics gathered
+// from it are reported with. This allows telling apart the metrics of the
+// registry of a library from other metrics.
+//
+// The metrics of the gatherers and scrape targets with the same scope are
+// reported together, and the metrics with the same name are merged into a
+// single metric. A metric with the same name but a different type than a
+// metric already gathered is dropped and an error is handled with the global
+// error handler.
+func WithScopedGatherer(gatherer prometheus.Gatherer, scope instrumentation.Scope) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.gatherers = append(cfg.gatherers, scopedGatherer{
+			gatherer: gatherer,
+			scope:    scope,
+		})
+		return cfg
+	})
+}
+
+// WithScrapeTarget configures a remote endpoint exposing metrics in the text
+// or protobuf Prometheus exposition format the Bridge will scrape each time
+// metrics are produced. This allows reporting the metrics of a sidecar
+// exporter alongside the metrics of the process. Multiple targets are scraped
+// concurrently.
+//
+// Along with the scraped metrics, the up, scrape_duration_seconds and
+// scrape_samples_scraped gauges are reported for the target with the labels
+// of the target. If the scrape fails, up is 0 and the error is handled with
+// the global error handler.
+//
+// Scraped metrics are filtered and renamed like gathered metrics. The scrape
+// health metrics are not filtered by [WithIncludedMetrics] and
+// [WithExcludedMetrics]. The scrape fails if the response body is larger than
+// [ScrapeTarget.MaxBodySize].
+func WithScrapeTarget(target ScrapeTarget) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.targets = append(cfg.targets, target)
+		return cfg
+	})
+}
+
+// WithIncludedMetrics configures the Bridge to only produce the metrics with
+// a name matching one of the patterns. The patterns are matched against the
+// name of the prometheus metric family, they need to be anchored to match the
+// complete name.
+//
+// By default, all the metrics are produced.
+func WithIncludedMetrics(patterns ...*regexp.Regexp) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.includeMetrics = append(cfg.includeMetrics, patterns...)
+		return cfg
+	})
+}
+
+// WithExcludedMetrics configures the Bridge to not produce the metrics with a
+// name matching one of the patterns. The patterns are matched against the
+// name of the prometheus metric family, they need to be anchored to match the
+// complete name. Exclusions take precedence over [WithIncludedMetrics].
+func WithExcludedMetrics(patterns ...*regexp.Regexp) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.excludeMetrics = append(cfg.excludeMetrics, patterns...)
+		return cfg
+	})
+}
+
+// WithLabelFilter configures the Bridge to only produce the series of a
+// metric for which filter returns true. The filter is called with the name of
+// the prometheus metric family and the labels of the series, before they are
+// renamed with [WithLabelRenames].
+//
+// By default, all the series are produced.
+func WithLabelFilter(filter func(metricName string, labels attribute.Set) bool) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.labelFilter = filter
+		return cfg
+	})
+}
+
+// WithLabelRenames configures the Bridge to rename prometheus labels when
+// converting them to attributes. Each key of renames is the name of a label
+// and its value is the attribute key used instead. Exemplar labels are not
+// renamed.
+//
+// By default, the attribute keys are the label names.
+func WithLabelRenames(renames map[string]string) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.labelRenames = renames
+		return cfg
+	})
+}
+
+// WithClassicHistograms configures the Bridge to convert the prometheus
+// histograms with both native and classic buckets to OpenTelemetry explicit
+// bucket histograms, using their classic buckets.
+//
+// By default, they are converted to exponential histograms using their native
+// buckets. A metric is converted to a single histogram: if some of its series
+// only have classic buckets, it is converted to an explicit bucket histogram
+// and the native buckets of the series without classic buckets are converted
+// to explicit buckets.
+//
+// Native histograms with custom buckets (schema -53) are always converted to
+// explicit bucket histograms using their classic buckets. The ones without
+// classic buckets are dropped and an error is handled with the global error
+// handler, as the Prometheus client model does not expose their bucket
+// boundaries.
+func WithClassicHistograms() Option {
 	return optionFunc(func(cfg config) config {
-		cfg.gatherers = append(cfg.gatherers, gatherer)
+		cfg.preferClassicHistograms = true
 		return cfg
 	})
 }
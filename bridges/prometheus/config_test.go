@@ -4,14 +4,21 @@
 package prometheus
 
 import (
+	"regexp"
 	"testing"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
 )
 
 func TestNewConfig(t *testing.T) {
 	otherRegistry := prometheus.NewRegistry()
+	defaultScope := instrumentation.Scope{Name: scopeName}
+	otherScope := instrumentation.Scope{Name: "other", Version: "v1.0.0"}
+	includeRe := regexp.MustCompile("^include_")
+	excludeRe := regexp.MustCompile("_excluded$")
+	target := ScrapeTarget{URL: "http://localhost:9100/metrics"}
 
 	testCases := []struct {
 		name       string
@@ -22,21 +29,63 @@ func TestNewConfig(t *testing.T) {
 			name:    "Default",
 			options: nil,
 			wantConfig: config{
-				gatherers: []prometheus.Gatherer{prometheus.DefaultGatherer},
+				gatherers: []scopedGatherer{{prometheus.DefaultGatherer, defaultScope}},
 			},
 		},
 		{
 			name:    "With a different gatherer",
 			options: []Option{WithGatherer(otherRegistry)},
 			wantConfig: config{
-				gatherers: []prometheus.Gatherer{otherRegistry},
+				gatherers: []scopedGatherer{{otherRegistry, defaultScope}},
 			},
 		},
 		{
 			name:    "Multiple gatherers",
 			options: []Option{WithGatherer(otherRegistry), WithGatherer(prometheus.DefaultGatherer)},
 			wantConfig: config{
-				gatherers: []prometheus.Gatherer{otherRegistry, prometheus.DefaultGatherer},
+				gatherers: []scopedGatherer{
+					{otherRegistry, defaultScope},
+					{prometheus.DefaultGatherer, defaultScope},
+				},
+			},
+		},
+		{
+			name:    "With a scoped gatherer",
+			options: []Option{WithGatherer(prometheus.DefaultGatherer), WithScopedGatherer(otherRegistry, otherScope)},
+			wantConfig: config{
+				gatherers: []scopedGatherer{
+					{prometheus.DefaultGatherer, defaultScope},
+					{otherRegistry, otherScope},
+				},
+			},
+		},
+		{
+			name:    "With a scrape target",
+			options: []Option{WithScrapeTarget(target)},
+			wantConfig: config{
+				targets: []ScrapeTarget{target},
+			},
+		},
+		{
+			name:    "With a gatherer and a scrape target",
+			options: []Option{WithGatherer(otherRegistry), WithScrapeTarget(target)},
+			wantConfig: config{
+				gatherers: []scopedGatherer{{otherRegistry, defaultScope}},
+				targets:   []ScrapeTarget{target},
+			},
+		},
+		{
+			name: "With filters",
+			options: []Option{
+				WithIncludedMetrics(includeRe),
+				WithExcludedMetrics(excludeRe),
+				WithLabelRenames(map[string]string{"a": "b"}),
+			},
+			wantConfig: config{
+				gatherers:      []scopedGatherer{{prometheus.DefaultGatherer, defaultScope}},
+				includeMetrics: []*regexp.Regexp{includeRe},
+				excludeMetrics: []*regexp.Regexp{excludeRe},
+				labelRenames:   map[string]string{"a": "b"},
 			},
 		},
 	}
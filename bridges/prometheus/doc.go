@@ -1,7 +1,8 @@
 // Copyright The OpenTelemetry Authors
 // SPDX-License-Identifier: Apache-2.0
 
-// Package prometheus provides a bridge from Prometheus to OpenTelemetry.
+// Package prometheus provides a bridge from Prometheus to OpenTelemetry, and
+// from OpenTelemetry to Prometheus.
 //
 // The Prometheus Bridge allows using the [Prometheus Golang client library]
 // with the OpenTelemetry SDK. This enables prometheus instrumentation libraries
@@ -12,6 +13,31 @@
 // Prometheus native histograms, set the (currently experimental) NativeHistogram...
 // options of the prometheus [HistogramOpts] when creating prometheus histograms.
 //
+// Series with both native and classic buckets are translated to exponential
+// histograms, or to explicit bucket histograms with [WithClassicHistograms].
+// Native histograms with custom buckets (schema -53) are translated to explicit
+// bucket histograms, using the boundaries of their classic buckets. A metric
+// with series that only have native buckets and series that only have classic
+// buckets is translated to an explicit bucket histogram, the native buckets
+// being converted to explicit buckets. Prometheus gauge histograms are
+// translated to histograms with delta temporality, starting at the time of the
+// scrape.
+//
+// By default, the metrics of all the gatherers are reported with the
+// instrumentation scope of the Prometheus Bridge. Use [WithScopedGatherer] to
+// report the metrics of a gatherer, e.g. the registry of a library, with a
+// different instrumentation scope. The metrics and series produced can be
+// selected with [WithIncludedMetrics], [WithExcludedMetrics], and
+// [WithLabelFilter], and the labels renamed with [WithLabelRenames].
+//
+// Metrics can also be scraped from remote endpoints, e.g. sidecar exporters,
+// with [WithScrapeTarget]. The labels of a [ScrapeTarget] are added to the
+// series scraped from it, and the up, scrape_duration_seconds, and
+// scrape_samples_scraped metrics report the health of its scrapes.
+//
+// The [Collector] bridges the other way: it is a Prometheus collector serving
+// the metrics of OpenTelemetry instruments from a Prometheus registry.
+//
 // While the Prometheus Bridge has some overhead, it can significantly reduce the
 // combined overall CPU and Memory footprint when sending to an OpenTelemetry
 // Collector. See the [benchmarks] for more details.
@@ -4,6 +4,7 @@
 package prometheus_test
 
 import (
+	promclient "github.com/prometheus/client_golang/prometheus"
 	"go.opentelemetry.io/otel/sdk/metric"
 
 	"go.opentelemetry.io/contrib/bridges/prometheus"
@@ -24,3 +25,16 @@ func ExampleNewMetricProducer() {
 	// exported batches of metrics.
 	_ = metric.NewMeterProvider(metric.WithReader(reader))
 }
+
+func ExampleNewCollector() {
+	// Create a ManualReader to collect the metrics of OpenTelemetry
+	// instruments, including those of instrumentation libraries, on demand.
+	reader := metric.NewManualReader()
+	_ = metric.NewMeterProvider(metric.WithReader(reader))
+	// Register a Collector of the reader with an existing Prometheus registry.
+	// The metrics of OpenTelemetry instruments are served along with the
+	// metrics of Prometheus instruments by the handler of the registry, e.g.
+	// promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).
+	registry := promclient.NewRegistry()
+	registry.MustRegister(prometheus.NewCollector(reader))
+}
@@ -0,0 +1,128 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheus
+
+import (
+	"regexp"
+
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// familyFilter selects the prometheus metric families and series to convert,
+// and renames their labels.
+type familyFilter struct {
+	include     []*regexp.Regexp
+	exclude     []*regexp.Regexp
+	labelFilter func(string, attribute.Set) bool
+	renames     map[string]string
+}
+
+func newFamilyFilter(cfg config) familyFilter {
+	return familyFilter{
+		include:     cfg.includeMetrics,
+		exclude:     cfg.excludeMetrics,
+		labelFilter: cfg.labelFilter,
+		renames:     cfg.labelRenames,
+	}
+}
+
+func (f familyFilter) isNoop() bool {
+	return len(f.include) == 0 && len(f.exclude) == 0 && f.labelFilter == nil && len(f.renames) == 0
+}
+
+// withoutNames returns f without the selection of the families by name.
+func (f familyFilter) withoutNames() familyFilter {
+	f.include, f.exclude = nil, nil
+	return f
+}
+
+// apply returns the families selected by f, with their labels renamed. The
+// passed families are not modified.
+func (f familyFilter) apply(families []*dto.MetricFamily) []*dto.MetricFamily {
+	if f.isNoop() {
+		return families
+	}
+
+	out := make([]*dto.MetricFamily, 0, len(families))
+	for _, mf := range families {
+		if !f.matchName(mf.GetName()) {
+			continue
+		}
+		if f.labelFilter == nil && len(f.renames) == 0 {
+			out = append(out, mf)
+			continue
+		}
+
+		metrics := make([]*dto.Metric, 0, len(mf.GetMetric()))
+		for _, m := range mf.GetMetric() {
+			if f.labelFilter != nil && !f.labelFilter(mf.GetName(), convertLabels(m.GetLabel())) {
+				continue
+			}
+			metrics = append(metrics, f.rename(m))
+		}
+		if len(metrics) == 0 {
+			continue
+		}
+		out = append(out, &dto.MetricFamily{
+			Name:   mf.Name,
+			Help:   mf.Help,
+			Type:   mf.Type,
+			Unit:   mf.Unit,
+			Metric: metrics,
+		})
+	}
+	return out
+}
+
+// matchName reports whether the family with name is selected by f.
+func (f familyFilter) matchName(name string) bool {
+	for _, re := range f.exclude {
+		if re.MatchString(name) {
+			return false
+		}
+	}
+	if len(f.include) == 0 {
+		return true
+	}
+	for _, re := range f.include {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// rename returns m with its labels renamed. m is returned as is if none of
+// its labels is renamed.
+func (f familyFilter) rename(m *dto.Metric) *dto.Metric {
+	if len(f.renames) == 0 {
+		return m
+	}
+
+	var labels []*dto.LabelPair
+	for i, l := range m.GetLabel() {
+		name, ok := f.renames[l.GetName()]
+		if !ok {
+			continue
+		}
+		if labels == nil {
+			labels = make([]*dto.LabelPair, len(m.GetLabel()))
+			copy(labels, m.GetLabel())
+		}
+		labels[i] = &dto.LabelPair{Name: &name, Value: l.Value}
+	}
+	if labels == nil {
+		return m
+	}
+	return &dto.Metric{
+		Label:       labels,
+		Gauge:       m.Gauge,
+		Counter:     m.Counter,
+		Summary:     m.Summary,
+		Untyped:     m.Untyped,
+		Histogram:   m.Histogram,
+		TimestampMs: m.TimestampMs,
+	}
+}
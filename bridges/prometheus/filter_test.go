@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheus
+
+import (
+	"regexp"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestFamilyFilterNoop(t *testing.T) {
+	families := []*dto.MetricFamily{{Name: proto.String("metric")}}
+	got := familyFilter{}.apply(families)
+	assert.Same(t, families[0], got[0])
+}
+
+func TestFamilyFilterMatchName(t *testing.T) {
+	f := familyFilter{
+		include: []*regexp.Regexp{regexp.MustCompile("^a_"), regexp.MustCompile("^b_")},
+		exclude: []*regexp.Regexp{regexp.MustCompile("_excluded$")},
+	}
+	assert.True(t, f.matchName("a_metric"))
+	assert.True(t, f.matchName("b_metric"))
+	assert.False(t, f.matchName("c_metric"))
+	assert.False(t, f.matchName("a_excluded"))
+
+	f = familyFilter{exclude: []*regexp.Regexp{regexp.MustCompile("_excluded$")}}
+	assert.True(t, f.matchName("c_metric"))
+	assert.False(t, f.matchName("c_excluded"))
+}
+
+func TestFamilyFilterRename(t *testing.T) {
+	m := &dto.Metric{
+		Label: []*dto.LabelPair{
+			{Name: proto.String("a"), Value: proto.String("1")},
+			{Name: proto.String("b"), Value: proto.String("2")},
+		},
+		Gauge:       &dto.Gauge{Value: proto.Float64(1)},
+		TimestampMs: proto.Int64(1000),
+	}
+	families := []*dto.MetricFamily{{
+		Name:   proto.String("metric"),
+		Type:   dto.MetricType_GAUGE.Enum(),
+		Metric: []*dto.Metric{m},
+	}}
+
+	got := familyFilter{renames: map[string]string{"b": "renamed"}}.apply(families)
+	require.Len(t, got, 1)
+	require.Len(t, got[0].GetMetric(), 1)
+	renamed := got[0].GetMetric()[0]
+	assert.Equal(t, "a", renamed.GetLabel()[0].GetName())
+	assert.Equal(t, "renamed", renamed.GetLabel()[1].GetName())
+	assert.Equal(t, "2", renamed.GetLabel()[1].GetValue())
+	assert.Same(t, m.GetGauge(), renamed.GetGauge())
+	assert.Equal(t, int64(1000), renamed.GetTimestampMs())
+
+	// The original families are not modified.
+	assert.Equal(t, "b", m.GetLabel()[1].GetName())
+
+	// Metrics without renamed labels are not copied.
+	got = familyFilter{renames: map[string]string{"c": "renamed"}}.apply(families)
+	assert.Same(t, m, got[0].GetMetric()[0])
+}
@@ -4,14 +4,16 @@
 package prometheus
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
 	"math"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -26,13 +28,22 @@ const (
 	spanIDLabel  = "span_id"
 )
 
+// customBucketsSchema is the schema of native histograms with custom bucket
+// boundaries.
+const customBucketsSchema = -53
+
 var (
-	errUnsupportedType = errors.New("unsupported metric type")
-	processStartTime   = time.Now()
+	errUnsupportedType    = errors.New("unsupported metric type")
+	errNoBucketBoundaries = errors.New("no bucket boundaries for native histogram with custom buckets")
+	errConflictingTypes   = errors.New("conflicting metric types")
+	processStartTime      = time.Now()
 )
 
 type producer struct {
-	gatherers prometheus.Gatherers
+	gatherers     []scopedGatherer
+	scrapers      []scraper
+	filter        familyFilter
+	preferClassic bool
 }
 
 // NewMetricProducer returns a metric.Producer that fetches metrics from
@@ -40,42 +51,143 @@ type producer struct {
 // added to an OpenTelemetry export pipeline.
 func NewMetricProducer(opts ...Option) metric.Producer {
 	cfg := newConfig(opts...)
+	scrapers := make([]scraper, len(cfg.targets))
+	for i, target := range cfg.targets {
+		scrapers[i] = newScraper(target)
+	}
 	return &producer{
-		gatherers: cfg.gatherers,
+		gatherers:     cfg.gatherers,
+		scrapers:      scrapers,
+		filter:        newFamilyFilter(cfg),
+		preferClassic: cfg.preferClassicHistograms,
 	}
 }
 
-func (p *producer) Produce(context.Context) ([]metricdata.ScopeMetrics, error) {
+func (p *producer) Produce(ctx context.Context) ([]metricdata.ScopeMetrics, error) {
 	now := time.Now()
 	var errs multierr
-	otelMetrics := make([]metricdata.Metrics, 0)
-	for _, gatherer := range p.gatherers {
-		promMetrics, err := gatherer.Gather()
+	// The families of all the gatherers and targets with the same scope are
+	// merged, so a metric reported by several of them is converted to a
+	// single metric.
+	var scopes []*scopeFamilies
+	index := make(map[instrumentation.Scope]*scopeFamilies)
+	add := func(scope instrumentation.Scope, families []*dto.MetricFamily) {
+		sf, ok := index[scope]
+		if !ok {
+			sf = newScopeFamilies(scope)
+			index[scope] = sf
+			scopes = append(scopes, sf)
+		}
+		for _, mf := range families {
+			if err := sf.add(mf); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	for _, g := range p.gatherers {
+		promMetrics, err := g.gatherer.Gather()
 		if err != nil {
 			errs = append(errs, err)
 			continue
 		}
-		m, err := convertPrometheusMetricsInto(promMetrics, now)
-		otelMetrics = append(otelMetrics, m...)
+		add(g.scope, p.filter.apply(promMetrics))
+	}
+
+	// The scrape health metrics are not filtered by name, so the health of
+	// the targets is always reported.
+	healthFilter := p.filter.withoutNames()
+	for i, r := range p.scrape(ctx) {
+		if r.err != nil {
+			errs = append(errs, r.err)
+		}
+		// The scrape health metrics are reported even if the scrape failed.
+		scope := p.scrapers[i].target.Scope
+		add(scope, p.filter.apply(r.families))
+		add(scope, healthFilter.apply(r.health))
+	}
+
+	var scopeMetrics []metricdata.ScopeMetrics
+	for _, sf := range scopes {
+		m, err := convertPrometheusMetricsInto(sf.families, now, p.preferClassic)
 		if err != nil {
 			errs = append(errs, err)
 		}
+		if len(m) == 0 {
+			continue
+		}
+		scopeMetrics = append(scopeMetrics, metricdata.ScopeMetrics{Scope: sf.scope, Metrics: m})
 	}
+
 	if errs.errOrNil() != nil {
 		otel.Handle(errs.errOrNil())
 	}
-	if len(otelMetrics) == 0 {
-		return nil, nil
+	return scopeMetrics, nil
+}
+
+// scopeFamilies are the metric families of an instrumentation scope, merged
+// by name.
+type scopeFamilies struct {
+	scope    instrumentation.Scope
+	families []*dto.MetricFamily
+	// index is the index of each family name in families.
+	index map[string]int
+}
+
+func newScopeFamilies(scope instrumentation.Scope) *scopeFamilies {
+	return &scopeFamilies{scope: scope, index: make(map[string]int)}
+}
+
+// add adds mf to the families. The series of mf are appended to the ones of
+// the family with the same name, if any. The passed families are not
+// modified. An error is returned, and mf is dropped, if the family with the
+// same name has a different type.
+func (sf *scopeFamilies) add(mf *dto.MetricFamily) error {
+	i, ok := sf.index[mf.GetName()]
+	if !ok {
+		sf.index[mf.GetName()] = len(sf.families)
+		sf.families = append(sf.families, mf)
+		return nil
 	}
-	return []metricdata.ScopeMetrics{{
-		Scope: instrumentation.Scope{
-			Name: scopeName,
-		},
-		Metrics: otelMetrics,
-	}}, nil
+
+	prev := sf.families[i]
+	if prev.GetType() != mf.GetType() {
+		return fmt.Errorf("%w: %v and %v for metric %v", errConflictingTypes, prev.GetType(), mf.GetType(), mf.GetName())
+	}
+	sf.families[i] = &dto.MetricFamily{
+		Name:   prev.Name,
+		Help:   prev.Help,
+		Type:   prev.Type,
+		Unit:   prev.Unit,
+		Metric: append(slices.Clip(prev.GetMetric()), mf.GetMetric()...),
+	}
+	return nil
 }
 
-func convertPrometheusMetricsInto(promMetrics []*dto.MetricFamily, now time.Time) ([]metricdata.Metrics, error) {
+type scrapeResult struct {
+	families []*dto.MetricFamily
+	health   []*dto.MetricFamily
+	err      error
+}
+
+// scrape scrapes all the targets concurrently, and returns the results in the
+// order of the targets.
+func (p *producer) scrape(ctx context.Context) []scrapeResult {
+	results := make([]scrapeResult, len(p.scrapers))
+	var wg sync.WaitGroup
+	for i, s := range p.scrapers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			families, health, err := s.scrape(ctx)
+			results[i] = scrapeResult{families: families, health: health, err: err}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+func convertPrometheusMetricsInto(promMetrics []*dto.MetricFamily, now time.Time, preferClassic bool) ([]metricdata.Metrics, error) {
 	var errs multierr
 	otelMetrics := make([]metricdata.Metrics, 0)
 	for _, pm := range promMetrics {
@@ -96,14 +208,27 @@ func convertPrometheusMetricsInto(promMetrics []*dto.MetricFamily, now time.Time
 			newMetric.Data = convertCounter(pm.GetMetric(), now)
 		case dto.MetricType_SUMMARY:
 			newMetric.Data = convertSummary(pm.GetMetric(), now)
-		case dto.MetricType_HISTOGRAM:
-			if isExponentialHistogram(pm.GetMetric()[0].GetHistogram()) {
-				newMetric.Data = convertExponentialHistogram(pm.GetMetric(), now)
-			} else {
-				newMetric.Data = convertHistogram(pm.GetMetric(), now)
+		case dto.MetricType_HISTOGRAM, dto.MetricType_GAUGE_HISTOGRAM:
+			temporality := metricdata.CumulativeTemporality
+			if pm.GetType() == dto.MetricType_GAUGE_HISTOGRAM {
+				// The buckets of a gauge histogram describe the current
+				// distribution of the values, they are not accumulated over
+				// time.
+				temporality = metricdata.DeltaTemporality
+			}
+			series, exponential, err := selectHistograms(pm.GetMetric(), preferClassic)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%w for metric %v", err, pm.GetName()))
+			}
+			switch {
+			case len(series) == 0:
+				continue
+			case exponential:
+				newMetric.Data = convertExponentialHistogram(series, now, temporality)
+			default:
+				newMetric.Data = convertHistogram(series, now, temporality)
 			}
 		default:
-			// MetricType_GAUGE_HISTOGRAM
 			errs = append(errs, fmt.Errorf("%w: %v for metric %v", errUnsupportedType, pm.GetType(), pm.GetName()))
 			continue
 		}
@@ -112,6 +237,49 @@ func convertPrometheusMetricsInto(promMetrics []*dto.MetricFamily, now time.Time
 	return otelMetrics, errs.errOrNil()
 }
 
+// selectHistograms returns the series of metrics converted to a single
+// histogram, and whether it is an exponential histogram.
+//
+// The family is converted to an exponential histogram if all its series have
+// native buckets, unless preferClassic is true. Otherwise, it is converted to
+// an explicit bucket histogram: the series with classic buckets are converted
+// with them, and the native buckets of the other series are converted to
+// explicit buckets.
+//
+// Native histograms with custom buckets (schema -53) are converted with their
+// classic buckets, as their bucket boundaries are the ones of the classic
+// buckets. An error is returned for the ones without classic buckets, which
+// are dropped.
+func selectHistograms(metrics []*dto.Metric, preferClassic bool) (series []*dto.Metric, exponential bool, err error) {
+	exponential = !preferClassic
+	series = make([]*dto.Metric, 0, len(metrics))
+	for _, m := range metrics {
+		hasNative, hasClassic := histogramBuckets(m.GetHistogram())
+		if !hasNative && !hasClassic {
+			err = errNoBucketBoundaries
+			continue
+		}
+		if !hasNative {
+			exponential = false
+		}
+		series = append(series, m)
+	}
+	return series, exponential, err
+}
+
+// histogramBuckets reports whether hist has native exponential buckets, and
+// whether it has classic buckets. A histogram without any bucket is reported
+// as having classic buckets, its count being the count of the implicit +Inf
+// bucket.
+func histogramBuckets(hist *dto.Histogram) (hasNative, hasClassic bool) {
+	if hist.GetSchema() == customBucketsSchema {
+		return false, len(hist.GetBucket()) > 0
+	}
+	hasNative = isExponentialHistogram(hist)
+	hasClassic = len(hist.GetBucket()) > 0 || !hasNative
+	return hasNative, hasClassic
+}
+
 func isExponentialHistogram(hist *dto.Histogram) bool {
 	// The prometheus go client ensures at least one of these is non-zero
 	// so it can be distinguished from a fixed-bucket histogram.
@@ -186,10 +354,10 @@ func convertCounter(metrics []*dto.Metric, now time.Time) metricdata.Sum[float64
 	return otelCounter
 }
 
-func convertExponentialHistogram(metrics []*dto.Metric, now time.Time) metricdata.ExponentialHistogram[float64] {
+func convertExponentialHistogram(metrics []*dto.Metric, now time.Time, temporality metricdata.Temporality) metricdata.ExponentialHistogram[float64] {
 	otelExpHistogram := metricdata.ExponentialHistogram[float64]{
 		DataPoints:  make([]metricdata.ExponentialHistogramDataPoint[float64], len(metrics)),
-		Temporality: metricdata.CumulativeTemporality,
+		Temporality: temporality,
 	}
 	for i, m := range metrics {
 		dp := metricdata.ExponentialHistogramDataPoint[float64]{
@@ -218,6 +386,9 @@ func convertExponentialHistogram(metrics []*dto.Metric, now time.Time) metricdat
 		if t := m.GetTimestampMs(); t != 0 {
 			dp.Time = time.UnixMilli(t)
 		}
+		if temporality == metricdata.DeltaTemporality {
+			dp.StartTime = dp.Time
+		}
 		otelExpHistogram.DataPoints[i] = dp
 	}
 	return otelExpHistogram
@@ -267,13 +438,23 @@ func convertExponentialBuckets(bucketSpans []*dto.BucketSpan, deltas []int64) me
 	}
 }
 
-func convertHistogram(metrics []*dto.Metric, now time.Time) metricdata.Histogram[float64] {
+func convertHistogram(metrics []*dto.Metric, now time.Time, temporality metricdata.Temporality) metricdata.Histogram[float64] {
 	otelHistogram := metricdata.Histogram[float64]{
 		DataPoints:  make([]metricdata.HistogramDataPoint[float64], len(metrics)),
-		Temporality: metricdata.CumulativeTemporality,
+		Temporality: temporality,
 	}
 	for i, m := range metrics {
-		bounds, bucketCounts, exemplars := convertBuckets(m.GetHistogram().GetBucket(), m.GetHistogram().GetSampleCount())
+		var (
+			bounds       []float64
+			bucketCounts []uint64
+			exemplars    []metricdata.Exemplar[float64]
+		)
+		if h := m.GetHistogram(); len(h.GetBucket()) == 0 && h.GetSchema() != customBucketsSchema && isExponentialHistogram(h) {
+			// The series only has native buckets.
+			bounds, bucketCounts = convertNativeBuckets(h)
+		} else {
+			bounds, bucketCounts, exemplars = convertBuckets(h.GetBucket(), h.GetSampleCount())
+		}
 		dp := metricdata.HistogramDataPoint[float64]{
 			Attributes:   convertLabels(m.GetLabel()),
 			StartTime:    processStartTime,
@@ -291,11 +472,65 @@ func convertHistogram(metrics []*dto.Metric, now time.Time) metricdata.Histogram
 		if m.GetTimestampMs() != 0 {
 			dp.Time = time.UnixMilli(m.GetTimestampMs())
 		}
+		if temporality == metricdata.DeltaTemporality {
+			dp.StartTime = dp.Time
+		}
 		otelHistogram.DataPoints[i] = dp
 	}
 	return otelHistogram
 }
 
+// convertNativeBuckets returns the explicit bucket boundaries and counts of
+// the native exponential buckets of hist with a standard schema. Each
+// populated exponential bucket, and the zero bucket, is converted to an
+// explicit bucket with the same upper boundary. The empty buckets between
+// them are merged into the following bucket.
+func convertNativeBuckets(hist *dto.Histogram) ([]float64, []uint64) {
+	type bucket struct {
+		bound float64
+		count uint64
+	}
+	var buckets []bucket
+	// The exponential bucket of index k covers (base^k, base^(k+1)] for
+	// positive values, and [-base^(k+1), -base^k) for negative values.
+	bound := func(k int32) float64 {
+		return math.Exp2(float64(k) * math.Exp2(-float64(hist.GetSchema())))
+	}
+	negative := convertExponentialBuckets(hist.GetNegativeSpan(), hist.GetNegativeDelta())
+	for j, c := range negative.Counts {
+		if c > 0 {
+			buckets = append(buckets, bucket{-bound(negative.Offset + int32(j)), c})
+		}
+	}
+	if zero := hist.GetZeroCount(); zero > 0 {
+		buckets = append(buckets, bucket{hist.GetZeroThreshold(), zero})
+	}
+	positive := convertExponentialBuckets(hist.GetPositiveSpan(), hist.GetPositiveDelta())
+	for j, c := range positive.Counts {
+		if c > 0 {
+			buckets = append(buckets, bucket{bound(positive.Offset + int32(j) + 1), c})
+		}
+	}
+	slices.SortFunc(buckets, func(a, b bucket) int {
+		return cmp.Compare(a.bound, b.bound)
+	})
+
+	bounds := make([]float64, len(buckets))
+	// The last bucket is the +Inf bucket, holding the observations not
+	// counted in the exponential buckets, if any.
+	bucketCounts := make([]uint64, len(buckets)+1)
+	var total uint64
+	for i, b := range buckets {
+		bounds[i] = b.bound
+		bucketCounts[i] = b.count
+		total += b.count
+	}
+	if count := hist.GetSampleCount(); count > total {
+		bucketCounts[len(buckets)] = count - total
+	}
+	return bounds, bucketCounts
+}
+
 func convertBuckets(buckets []*dto.Bucket, sampleCount uint64) ([]float64, []uint64, []metricdata.Exemplar[float64]) {
 	if len(buckets) == 0 {
 		// This should never happen
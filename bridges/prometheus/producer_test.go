@@ -4,6 +4,7 @@
 package prometheus
 
 import (
+	"regexp"
 	"testing"
 	"time"
 
@@ -474,7 +475,7 @@ func TestProduce(t *testing.T) {
 }
 
 // This is separate from TestProduce because the Prometheus Go SDK does not
-// provide a function that generates a gauge histogram metric.
+// provide a function that generates a metric of an unsupported type.
 func TestProducePartialSuccess(t *testing.T) {
 	previousHandler := otel.GetErrorHandler()
 	t.Cleanup(func() { otel.SetErrorHandler(previousHandler) })
@@ -505,9 +506,9 @@ func TestProducePartialSuccess(t *testing.T) {
 				},
 			},
 			{
-				Name: proto.String("test_gauge_histogram_metric"),
-				Help: proto.String("A gauge histogram metric for testing"),
-				Type: dto.MetricType_GAUGE_HISTOGRAM.Enum(),
+				Name: proto.String("test_unknown_metric"),
+				Help: proto.String("A metric of an unknown type for testing"),
+				Type: dto.MetricType(-1).Enum(),
 				Metric: []*dto.Metric{
 					{},
 				},
@@ -518,7 +519,7 @@ func TestProducePartialSuccess(t *testing.T) {
 	output, err := p.Produce(t.Context())
 	assert.NoError(t, err)
 	require.ErrorIs(t, handledErr, errUnsupportedType)
-	assert.Contains(t, handledErr.Error(), "test_gauge_histogram_metric")
+	assert.Contains(t, handledErr.Error(), "test_unknown_metric")
 	require.Len(t, output, 1)
 
 	expected := metricdata.ScopeMetrics{
@@ -679,3 +680,495 @@ type gathererFunc func() ([]*dto.MetricFamily, error)
 func (f gathererFunc) Gather() ([]*dto.MetricFamily, error) {
 	return f()
 }
+
+func newTestGauge(t *testing.T, reg *prometheus.Registry, name string, labels prometheus.Labels, value float64) {
+	t.Helper()
+	g := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name:        name,
+		Help:        "A gauge metric for testing",
+		ConstLabels: labels,
+	})
+	reg.MustRegister(g)
+	g.Set(value)
+}
+
+func testGaugeMetrics(name string, attrs attribute.Set, value float64) metricdata.Metrics {
+	return metricdata.Metrics{
+		Name:        name,
+		Description: "A gauge metric for testing",
+		Data: metricdata.Gauge[float64]{
+			DataPoints: []metricdata.DataPoint[float64]{
+				{Attributes: attrs, Value: value},
+			},
+		},
+	}
+}
+
+func TestProduceScopes(t *testing.T) {
+	regA, regB, regC := prometheus.NewRegistry(), prometheus.NewRegistry(), prometheus.NewRegistry()
+	newTestGauge(t, regA, "a_metric", nil, 1)
+	newTestGauge(t, regB, "b_metric", nil, 2)
+	newTestGauge(t, regC, "c_metric", nil, 3)
+
+	libScope := instrumentation.Scope{Name: "github.com/example/lib", Version: "v1.2.3"}
+	p := NewMetricProducer(
+		WithGatherer(regA),
+		WithScopedGatherer(regB, libScope),
+		WithGatherer(regC),
+	)
+	output, err := p.Produce(t.Context())
+	require.NoError(t, err)
+
+	expected := []metricdata.ScopeMetrics{
+		{
+			Scope: instrumentation.Scope{Name: scopeName},
+			Metrics: []metricdata.Metrics{
+				testGaugeMetrics("a_metric", *attribute.EmptySet(), 1),
+				testGaugeMetrics("c_metric", *attribute.EmptySet(), 3),
+			},
+		},
+		{
+			Scope:   libScope,
+			Metrics: []metricdata.Metrics{testGaugeMetrics("b_metric", *attribute.EmptySet(), 2)},
+		},
+	}
+	require.Len(t, output, len(expected))
+	for i := range output {
+		metricdatatest.AssertEqual(t, expected[i], output[i], metricdatatest.IgnoreTimestamp())
+	}
+}
+
+func TestProduceFilters(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	newTestGauge(t, reg, "lib_requests", prometheus.Labels{"env": "prod", "svc": "a"}, 1)
+	newTestGauge(t, reg, "lib_internal_requests", prometheus.Labels{"env": "prod"}, 2)
+	newTestGauge(t, reg, "other_requests", prometheus.Labels{"env": "prod"}, 3)
+	vec := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "lib_errors",
+		Help: "A gauge metric for testing",
+	}, []string{"env"})
+	reg.MustRegister(vec)
+	vec.WithLabelValues("prod").Set(4)
+	vec.WithLabelValues("dev").Set(5)
+
+	p := NewMetricProducer(
+		WithGatherer(reg),
+		WithIncludedMetrics(regexp.MustCompile("^lib_")),
+		WithExcludedMetrics(regexp.MustCompile("^lib_internal_")),
+		WithLabelFilter(func(_ string, labels attribute.Set) bool {
+			env, _ := labels.Value("env")
+			return env.AsString() != "dev"
+		}),
+		WithLabelRenames(map[string]string{"env": "deployment.environment.name"}),
+	)
+	output, err := p.Produce(t.Context())
+	require.NoError(t, err)
+	require.Len(t, output, 1)
+
+	expected := metricdata.ScopeMetrics{
+		Scope: instrumentation.Scope{Name: scopeName},
+		Metrics: []metricdata.Metrics{
+			testGaugeMetrics("lib_errors", attribute.NewSet(
+				attribute.String("deployment.environment.name", "prod"),
+			), 4),
+			testGaugeMetrics("lib_requests", attribute.NewSet(
+				attribute.String("deployment.environment.name", "prod"),
+				attribute.String("svc", "a"),
+			), 1),
+		},
+	}
+	metricdatatest.AssertEqual(t, expected, output[0], metricdatatest.IgnoreTimestamp())
+}
+
+func TestProduceFiltersAllSeries(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	newTestGauge(t, reg, "test_gauge_metric", prometheus.Labels{"env": "dev"}, 1)
+
+	p := NewMetricProducer(
+		WithGatherer(reg),
+		WithLabelFilter(func(string, attribute.Set) bool { return false }),
+	)
+	output, err := p.Produce(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, output)
+}
+
+func TestProduceHistograms(t *testing.T) {
+	classicBuckets := []*dto.Bucket{
+		{UpperBound: proto.Float64(1), CumulativeCount: proto.Uint64(1)},
+		{UpperBound: proto.Float64(5), CumulativeCount: proto.Uint64(3)},
+	}
+	nativeSpans := []*dto.BucketSpan{{Offset: proto.Int32(1), Length: proto.Uint32(2)}}
+	nativeDeltas := []int64{1, 1}
+	ts := time.Unix(1000, 0)
+
+	previousHandler := otel.GetErrorHandler()
+	t.Cleanup(func() { otel.SetErrorHandler(previousHandler) })
+	var handledErr error
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		handledErr = err
+	}))
+
+	p := NewMetricProducer(WithGatherer(gathererFunc(func() ([]*dto.MetricFamily, error) {
+		return []*dto.MetricFamily{
+			{
+				Name: proto.String("gauge_histogram"),
+				Type: dto.MetricType_GAUGE_HISTOGRAM.Enum(),
+				Metric: []*dto.Metric{{
+					TimestampMs: proto.Int64(ts.UnixMilli()),
+					Histogram: &dto.Histogram{
+						SampleCount: proto.Uint64(4),
+						SampleSum:   proto.Float64(10),
+						Bucket:      classicBuckets,
+					},
+				}},
+			},
+			{
+				Name: proto.String("native_gauge_histogram"),
+				Type: dto.MetricType_GAUGE_HISTOGRAM.Enum(),
+				Metric: []*dto.Metric{{
+					TimestampMs: proto.Int64(ts.UnixMilli()),
+					Histogram: &dto.Histogram{
+						SampleCount:   proto.Uint64(3),
+						SampleSum:     proto.Float64(6),
+						Schema:        proto.Int32(1),
+						PositiveSpan:  nativeSpans,
+						PositiveDelta: nativeDeltas,
+					},
+				}},
+			},
+			{
+				Name: proto.String("both_histogram"),
+				Type: dto.MetricType_HISTOGRAM.Enum(),
+				Metric: []*dto.Metric{{
+					Label:       []*dto.LabelPair{{Name: proto.String("series"), Value: proto.String("both")}},
+					TimestampMs: proto.Int64(ts.UnixMilli()),
+					Histogram: &dto.Histogram{
+						SampleCount:   proto.Uint64(4),
+						SampleSum:     proto.Float64(10),
+						Bucket:        classicBuckets,
+						Schema:        proto.Int32(1),
+						PositiveSpan:  nativeSpans,
+						PositiveDelta: nativeDeltas,
+					},
+				}},
+			},
+			{
+				Name: proto.String("mixed_histogram"),
+				Type: dto.MetricType_HISTOGRAM.Enum(),
+				Metric: []*dto.Metric{
+					{
+						Label:       []*dto.LabelPair{{Name: proto.String("series"), Value: proto.String("both")}},
+						TimestampMs: proto.Int64(ts.UnixMilli()),
+						Histogram: &dto.Histogram{
+							SampleCount:   proto.Uint64(4),
+							SampleSum:     proto.Float64(10),
+							Bucket:        classicBuckets,
+							Schema:        proto.Int32(1),
+							PositiveSpan:  nativeSpans,
+							PositiveDelta: nativeDeltas,
+						},
+					},
+					{
+						Label:       []*dto.LabelPair{{Name: proto.String("series"), Value: proto.String("classic")}},
+						TimestampMs: proto.Int64(ts.UnixMilli()),
+						Histogram: &dto.Histogram{
+							SampleCount: proto.Uint64(4),
+							SampleSum:   proto.Float64(10),
+							Bucket:      classicBuckets,
+						},
+					},
+				},
+			},
+			{
+				Name: proto.String("custom_buckets_histogram"),
+				Type: dto.MetricType_HISTOGRAM.Enum(),
+				Metric: []*dto.Metric{
+					{
+						Label:       []*dto.LabelPair{{Name: proto.String("series"), Value: proto.String("bounds")}},
+						TimestampMs: proto.Int64(ts.UnixMilli()),
+						Histogram: &dto.Histogram{
+							SampleCount:   proto.Uint64(4),
+							SampleSum:     proto.Float64(10),
+							Bucket:        classicBuckets,
+							Schema:        proto.Int32(customBucketsSchema),
+							PositiveSpan:  []*dto.BucketSpan{{Offset: proto.Int32(0), Length: proto.Uint32(3)}},
+							PositiveDelta: []int64{1, 1, -1},
+						},
+					},
+					{
+						Label:       []*dto.LabelPair{{Name: proto.String("series"), Value: proto.String("no_bounds")}},
+						TimestampMs: proto.Int64(ts.UnixMilli()),
+						Histogram: &dto.Histogram{
+							SampleCount:   proto.Uint64(1),
+							Schema:        proto.Int32(customBucketsSchema),
+							PositiveSpan:  []*dto.BucketSpan{{Offset: proto.Int32(0), Length: proto.Uint32(1)}},
+							PositiveDelta: []int64{1},
+						},
+					},
+				},
+			},
+		}, nil
+	})))
+
+	output, err := p.Produce(t.Context())
+	require.NoError(t, err)
+	require.ErrorIs(t, handledErr, errNoBucketBoundaries)
+	assert.Contains(t, handledErr.Error(), "custom_buckets_histogram")
+	require.Len(t, output, 1)
+
+	classicDataPoint := func(attrs attribute.Set) metricdata.HistogramDataPoint[float64] {
+		return metricdata.HistogramDataPoint[float64]{
+			Attributes:   attrs,
+			StartTime:    processStartTime,
+			Time:         ts,
+			Count:        4,
+			Sum:          10,
+			Bounds:       []float64{1, 5},
+			BucketCounts: []uint64{1, 2, 1},
+		}
+	}
+	nativeDataPoint := func(attrs attribute.Set, count uint64, sum float64) metricdata.ExponentialHistogramDataPoint[float64] {
+		return metricdata.ExponentialHistogramDataPoint[float64]{
+			Attributes:     attrs,
+			StartTime:      processStartTime,
+			Time:           ts,
+			Count:          count,
+			Sum:            sum,
+			Scale:          1,
+			PositiveBucket: metricdata.ExponentialBucket{Offset: 0, Counts: []uint64{1, 2}},
+		}
+	}
+	// The start time of the gauge histograms is their time.
+	gaugeDataPoint := classicDataPoint(*attribute.EmptySet())
+	gaugeDataPoint.StartTime = ts
+	nativeGaugeDataPoint := nativeDataPoint(*attribute.EmptySet(), 3, 6)
+	nativeGaugeDataPoint.StartTime = ts
+	both := attribute.NewSet(attribute.String("series", "both"))
+
+	expected := metricdata.ScopeMetrics{
+		Scope: instrumentation.Scope{Name: scopeName},
+		Metrics: []metricdata.Metrics{
+			{
+				Name: "gauge_histogram",
+				Data: metricdata.Histogram[float64]{
+					Temporality: metricdata.DeltaTemporality,
+					DataPoints:  []metricdata.HistogramDataPoint[float64]{gaugeDataPoint},
+				},
+			},
+			{
+				Name: "native_gauge_histogram",
+				Data: metricdata.ExponentialHistogram[float64]{
+					Temporality: metricdata.DeltaTemporality,
+					DataPoints:  []metricdata.ExponentialHistogramDataPoint[float64]{nativeGaugeDataPoint},
+				},
+			},
+			{
+				Name: "both_histogram",
+				Data: metricdata.ExponentialHistogram[float64]{
+					Temporality: metricdata.CumulativeTemporality,
+					DataPoints:  []metricdata.ExponentialHistogramDataPoint[float64]{nativeDataPoint(both, 4, 10)},
+				},
+			},
+			{
+				Name: "mixed_histogram",
+				Data: metricdata.Histogram[float64]{
+					Temporality: metricdata.CumulativeTemporality,
+					DataPoints: []metricdata.HistogramDataPoint[float64]{
+						classicDataPoint(both),
+						classicDataPoint(attribute.NewSet(attribute.String("series", "classic"))),
+					},
+				},
+			},
+			{
+				Name: "custom_buckets_histogram",
+				Data: metricdata.Histogram[float64]{
+					Temporality: metricdata.CumulativeTemporality,
+					DataPoints: []metricdata.HistogramDataPoint[float64]{
+						classicDataPoint(attribute.NewSet(attribute.String("series", "bounds"))),
+					},
+				},
+			},
+		},
+	}
+	metricdatatest.AssertEqual(t, expected, output[0])
+}
+
+func TestProduceMixedHistograms(t *testing.T) {
+	classic := &dto.Metric{
+		Label: []*dto.LabelPair{{Name: proto.String("series"), Value: proto.String("classic")}},
+		Histogram: &dto.Histogram{
+			SampleCount: proto.Uint64(1),
+			SampleSum:   proto.Float64(1),
+			Bucket:      []*dto.Bucket{{UpperBound: proto.Float64(1), CumulativeCount: proto.Uint64(1)}},
+		},
+	}
+	native := &dto.Metric{
+		Label: []*dto.LabelPair{{Name: proto.String("series"), Value: proto.String("native")}},
+		Histogram: &dto.Histogram{
+			SampleCount:   proto.Uint64(1),
+			SampleSum:     proto.Float64(1),
+			Schema:        proto.Int32(1),
+			PositiveSpan:  []*dto.BucketSpan{{Offset: proto.Int32(1), Length: proto.Uint32(1)}},
+			PositiveDelta: []int64{1},
+		},
+	}
+	both := &dto.Metric{
+		Label: []*dto.LabelPair{{Name: proto.String("series"), Value: proto.String("both")}},
+		Histogram: &dto.Histogram{
+			SampleCount:   proto.Uint64(1),
+			SampleSum:     proto.Float64(1),
+			Bucket:        classic.Histogram.Bucket,
+			Schema:        proto.Int32(1),
+			PositiveSpan:  native.Histogram.PositiveSpan,
+			PositiveDelta: native.Histogram.PositiveDelta,
+		},
+	}
+	gatherer := func(metrics ...*dto.Metric) prometheus.Gatherer {
+		return gathererFunc(func() ([]*dto.MetricFamily, error) {
+			return []*dto.MetricFamily{{
+				Name:   proto.String("test_histogram"),
+				Type:   dto.MetricType_HISTOGRAM.Enum(),
+				Metric: metrics,
+			}}, nil
+		})
+	}
+
+	tests := []struct {
+		name    string
+		metrics []*dto.Metric
+		opts    []Option
+		// wantSeries are the series converted.
+		wantSeries  []string
+		wantClassic bool
+	}{
+		{
+			name:       "Both",
+			metrics:    []*dto.Metric{both},
+			wantSeries: []string{"both"},
+		},
+		{
+			name:        "BothClassic",
+			metrics:     []*dto.Metric{both},
+			opts:        []Option{WithClassicHistograms()},
+			wantSeries:  []string{"both"},
+			wantClassic: true,
+		},
+		{
+			name:       "BothAndNative",
+			metrics:    []*dto.Metric{both, native},
+			wantSeries: []string{"both", "native"},
+		},
+		{
+			name:        "BothAndNativeClassic",
+			metrics:     []*dto.Metric{both, native},
+			opts:        []Option{WithClassicHistograms()},
+			wantSeries:  []string{"both", "native"},
+			wantClassic: true,
+		},
+		{
+			name:        "BothAndClassic",
+			metrics:     []*dto.Metric{both, classic},
+			wantSeries:  []string{"both", "classic"},
+			wantClassic: true,
+		},
+		{
+			// The native buckets of the native series are converted to
+			// explicit buckets.
+			name:        "NativeAndClassic",
+			metrics:     []*dto.Metric{both, native, classic},
+			wantSeries:  []string{"both", "native", "classic"},
+			wantClassic: true,
+		},
+		{
+			name:        "NativeAndClassicClassic",
+			metrics:     []*dto.Metric{both, native, classic},
+			opts:        []Option{WithClassicHistograms()},
+			wantSeries:  []string{"both", "native", "classic"},
+			wantClassic: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previousHandler := otel.GetErrorHandler()
+			t.Cleanup(func() { otel.SetErrorHandler(previousHandler) })
+			var handledErr error
+			otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+				handledErr = err
+			}))
+
+			p := NewMetricProducer(append(tt.opts, WithGatherer(gatherer(tt.metrics...)))...)
+			output, err := p.Produce(t.Context())
+			require.NoError(t, err)
+			require.NoError(t, handledErr)
+			require.Len(t, output, 1)
+			require.Len(t, output[0].Metrics, 1, "a single histogram per family")
+
+			var series []string
+			switch data := output[0].Metrics[0].Data.(type) {
+			case metricdata.Histogram[float64]:
+				assert.True(t, tt.wantClassic, "explicit bucket histogram")
+				for _, dp := range data.DataPoints {
+					v, _ := dp.Attributes.Value("series")
+					series = append(series, v.AsString())
+				}
+			case metricdata.ExponentialHistogram[float64]:
+				assert.False(t, tt.wantClassic, "exponential histogram")
+				for _, dp := range data.DataPoints {
+					v, _ := dp.Attributes.Value("series")
+					series = append(series, v.AsString())
+				}
+			default:
+				t.Fatalf("unexpected data type %T", data)
+			}
+			assert.Equal(t, tt.wantSeries, series)
+		})
+	}
+}
+
+func TestProduceCustomBucketsWithoutBoundaries(t *testing.T) {
+	previousHandler := otel.GetErrorHandler()
+	t.Cleanup(func() { otel.SetErrorHandler(previousHandler) })
+	var handledErr error
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		handledErr = err
+	}))
+
+	p := NewMetricProducer(WithGatherer(gathererFunc(func() ([]*dto.MetricFamily, error) {
+		return []*dto.MetricFamily{{
+			Name: proto.String("custom_buckets_histogram"),
+			Type: dto.MetricType_HISTOGRAM.Enum(),
+			Metric: []*dto.Metric{{
+				Histogram: &dto.Histogram{
+					SampleCount:   proto.Uint64(1),
+					Schema:        proto.Int32(customBucketsSchema),
+					PositiveSpan:  []*dto.BucketSpan{{Offset: proto.Int32(0), Length: proto.Uint32(1)}},
+					PositiveDelta: []int64{1},
+				},
+			}},
+		}}, nil
+	})))
+	output, err := p.Produce(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, output, "series without bucket boundaries dropped")
+	require.ErrorIs(t, handledErr, errNoBucketBoundaries)
+	assert.Contains(t, handledErr.Error(), "custom_buckets_histogram")
+}
+
+func TestConvertNativeBuckets(t *testing.T) {
+	hist := &dto.Histogram{
+		SampleCount:   proto.Uint64(10),
+		Schema:        proto.Int32(0),
+		ZeroThreshold: proto.Float64(0.001),
+		ZeroCount:     proto.Uint64(3),
+		// The buckets (-4, -2] and (-2, -1] with 2 and 1 observations.
+		NegativeSpan:  []*dto.BucketSpan{{Offset: proto.Int32(1), Length: proto.Uint32(2)}},
+		NegativeDelta: []int64{1, 1},
+		// The bucket (0.5, 1] with 4 observations.
+		PositiveSpan:  []*dto.BucketSpan{{Offset: proto.Int32(0), Length: proto.Uint32(1)}},
+		PositiveDelta: []int64{4},
+	}
+	bounds, counts := convertNativeBuckets(hist)
+	assert.Equal(t, []float64{-2, -1, 0.001, 1}, bounds)
+	assert.Equal(t, []uint64{2, 1, 3, 4, 0}, counts)
+}
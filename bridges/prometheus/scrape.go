@@ -0,0 +1,210 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// defaultScrapeTimeout is the timeout of a scrape if none is configured.
+	defaultScrapeTimeout = 10 * time.Second
+
+	// defaultMaxBodySize is the maximum size of a scrape response body if
+	// none is configured.
+	defaultMaxBodySize = 32 << 20 // 32 MiB
+
+	// scrapeAccept is the Accept header of the scrape requests, preferring
+	// the protobuf exposition format.
+	scrapeAccept = `application/vnd.google.protobuf;proto=io.prometheus.client.MetricFamily;encoding=delimited;q=0.7,text/plain;version=0.0.4;q=0.3`
+
+	upMetricName             = "up"
+	scrapeDurationMetricName = "scrape_duration_seconds"
+	scrapeSamplesMetricName  = "scrape_samples_scraped"
+)
+
+var (
+	errScrapeStatus = errors.New("unexpected scrape response status")
+	errBodyTooLarge = errors.New("scrape response body exceeds the maximum size")
+)
+
+// ScrapeTarget is a remote endpoint exposing Prometheus metrics, e.g. the
+// /metrics endpoint of a sidecar exporter, scraped by the Bridge.
+type ScrapeTarget struct {
+	// URL is the URL of the endpoint. It is required.
+	URL string
+
+	// Timeout is the maximum duration of a scrape of the endpoint. If zero,
+	// a timeout of 10 seconds is used.
+	Timeout time.Duration
+
+	// Labels are added to all the series scraped from the endpoint, and to
+	// the scrape health metrics of the target. They are converted to
+	// attributes along with the labels of the series, and overwrite labels
+	// with the same name. It is recommended to identify the target with the
+	// job and instance labels.
+	Labels map[string]string
+
+	// Scope is the instrumentation scope the metrics scraped from the
+	// endpoint are reported with. If the name of the scope is empty, the
+	// scope of the Bridge is used.
+	Scope instrumentation.Scope
+
+	// Client is the HTTP client used to scrape the endpoint. If nil,
+	// [http.DefaultClient] is used.
+	Client *http.Client
+
+	// MaxBodySize is the maximum size, in bytes, of the uncompressed
+	// response body of the endpoint. The scrape fails if the body is larger.
+	// If zero, a maximum size of 32 MiB is used.
+	MaxBodySize int64
+}
+
+// scraper scrapes the metric families of a ScrapeTarget.
+type scraper struct {
+	target ScrapeTarget
+	labels []*dto.LabelPair
+}
+
+func newScraper(target ScrapeTarget) scraper {
+	if target.Timeout <= 0 {
+		target.Timeout = defaultScrapeTimeout
+	}
+	if target.Client == nil {
+		target.Client = http.DefaultClient
+	}
+	if target.MaxBodySize <= 0 {
+		target.MaxBodySize = defaultMaxBodySize
+	}
+	if target.Scope.Name == "" {
+		target.Scope = instrumentation.Scope{Name: scopeName}
+	}
+
+	labels := make([]*dto.LabelPair, 0, len(target.Labels))
+	for name, value := range target.Labels {
+		labels = append(labels, &dto.LabelPair{Name: proto.String(name), Value: proto.String(value)})
+	}
+	slices.SortFunc(labels, func(a, b *dto.LabelPair) int {
+		return strings.Compare(a.GetName(), b.GetName())
+	})
+	return scraper{target: target, labels: labels}
+}
+
+// scrape returns the metric families scraped from the target with the
+// labels of the target added, and the scrape health metrics of the target.
+// The health metrics are returned even if the scrape fails.
+func (s scraper) scrape(ctx context.Context) (families, health []*dto.MetricFamily, err error) {
+	start := time.Now()
+	families, err = s.fetch(ctx)
+	duration := time.Since(start)
+
+	var samples int
+	for _, mf := range families {
+		samples += len(mf.GetMetric())
+		for _, m := range mf.GetMetric() {
+			m.Label = mergeLabels(m.GetLabel(), s.labels)
+		}
+	}
+
+	up := 1.0
+	if err != nil {
+		up = 0
+		err = fmt.Errorf("scrape %s: %w", s.target.URL, err)
+	}
+	health = []*dto.MetricFamily{
+		s.gauge(upMetricName, "1 if the target was scraped successfully, 0 otherwise.", up),
+		s.gauge(scrapeDurationMetricName, "Duration of the scrape of the target in seconds.", duration.Seconds()),
+		s.gauge(scrapeSamplesMetricName, "The number of series scraped from the target.", float64(samples)),
+	}
+	return families, health, err
+}
+
+func (s scraper) fetch(ctx context.Context) ([]*dto.MetricFamily, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.target.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.target.URL, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", scrapeAccept)
+	resp, err := s.target.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s", errScrapeStatus, resp.Status)
+	}
+
+	format := expfmt.ResponseFormat(resp.Header)
+	if format.FormatType() == expfmt.TypeUnknown {
+		// Fall back to the text format, as Prometheus does.
+		format = expfmt.NewFormat(expfmt.TypeTextPlain)
+	}
+	// Read one more byte than the maximum size to detect larger bodies.
+	body := &io.LimitedReader{R: resp.Body, N: s.target.MaxBodySize + 1}
+	dec := expfmt.NewDecoder(body, format)
+	var families []*dto.MetricFamily
+	for {
+		mf := new(dto.MetricFamily)
+		err := dec.Decode(mf)
+		if body.N <= 0 {
+			// The body is truncated, the families decoded may be incomplete.
+			return nil, fmt.Errorf("%w: %d bytes", errBodyTooLarge, s.target.MaxBodySize)
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		families = append(families, mf)
+	}
+	// The text decoder does not preserve the order of the families.
+	slices.SortFunc(families, func(a, b *dto.MetricFamily) int {
+		return strings.Compare(a.GetName(), b.GetName())
+	})
+	return families, nil
+}
+
+func (s scraper) gauge(name, help string, value float64) *dto.MetricFamily {
+	return &dto.MetricFamily{
+		Name: proto.String(name),
+		Help: proto.String(help),
+		Type: dto.MetricType_GAUGE.Enum(),
+		Metric: []*dto.Metric{{
+			Label: s.labels,
+			Gauge: &dto.Gauge{Value: proto.Float64(value)},
+		}},
+	}
+}
+
+// mergeLabels returns labels with the target labels added, replacing the
+// labels with the same name.
+func mergeLabels(labels, target []*dto.LabelPair) []*dto.LabelPair {
+	if len(target) == 0 {
+		return labels
+	}
+	merged := make([]*dto.LabelPair, 0, len(labels)+len(target))
+	for _, l := range labels {
+		if !slices.ContainsFunc(target, func(t *dto.LabelPair) bool { return t.GetName() == l.GetName() }) {
+			merged = append(merged, l)
+		}
+	}
+	return append(merged, target...)
+}
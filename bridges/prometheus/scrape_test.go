@@ -0,0 +1,339 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheus
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata/metricdatatest"
+	"google.golang.org/protobuf/proto"
+)
+
+const textExposition = `# HELP node_load1 1m load average.
+# TYPE node_load1 gauge
+node_load1 0.5
+# HELP node_cpu_seconds_total Seconds the CPUs spent in each mode.
+# TYPE node_cpu_seconds_total counter
+node_cpu_seconds_total{cpu="0",mode="idle"} 12.5
+node_cpu_seconds_total{cpu="0",mode="user"} 3
+`
+
+// findMetrics returns the metrics with the given name of sm.
+func findMetrics(t *testing.T, sm metricdata.ScopeMetrics, name string) metricdata.Metrics {
+	t.Helper()
+	for _, m := range sm.Metrics {
+		if m.Name == name {
+			return m
+		}
+	}
+	require.Failf(t, "metric not found", "no metric named %q", name)
+	return metricdata.Metrics{}
+}
+
+func gaugeValue(t *testing.T, m metricdata.Metrics) (attribute.Set, float64) {
+	t.Helper()
+	g, ok := m.Data.(metricdata.Gauge[float64])
+	require.True(t, ok, "metric %q is not a gauge", m.Name)
+	require.Len(t, g.DataPoints, 1)
+	return g.DataPoints[0].Attributes, g.DataPoints[0].Value
+}
+
+func TestProduceScrapeTargets(t *testing.T) {
+	previousHandler := otel.GetErrorHandler()
+	t.Cleanup(func() { otel.SetErrorHandler(previousHandler) })
+	var handledErr error
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		handledErr = err
+	}))
+
+	reg := prometheus.NewRegistry()
+	newTestGauge(t, reg, "app_queue_size", prometheus.Labels{"queue": "jobs"}, 7)
+	protoSrv := httptest.NewServer(promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	t.Cleanup(protoSrv.Close)
+
+	textSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		_, _ = fmt.Fprint(w, textExposition)
+	}))
+	t.Cleanup(textSrv.Close)
+
+	failingSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(failingSrv.Close)
+
+	appScope := instrumentation.Scope{Name: "legacy-app"}
+	p := NewMetricProducer(
+		WithScrapeTarget(ScrapeTarget{
+			URL:    protoSrv.URL,
+			Labels: map[string]string{"job": "app", "queue": "overwritten"},
+			Scope:  appScope,
+		}),
+		WithScrapeTarget(ScrapeTarget{
+			URL:    textSrv.URL,
+			Labels: map[string]string{"job": "node"},
+		}),
+		WithScrapeTarget(ScrapeTarget{
+			URL:    failingSrv.URL,
+			Labels: map[string]string{"job": "failing"},
+		}),
+	)
+	output, err := p.Produce(t.Context())
+	require.NoError(t, err)
+	require.ErrorIs(t, handledErr, errScrapeStatus)
+	assert.ErrorContains(t, handledErr, failingSrv.URL)
+
+	// The scope of the targets without a scope is the scope of the Bridge.
+	require.Len(t, output, 2)
+	assert.Equal(t, appScope, output[0].Scope)
+	assert.Equal(t, instrumentation.Scope{Name: scopeName}, output[1].Scope)
+
+	appLabels := attribute.NewSet(attribute.String("job", "app"), attribute.String("queue", "overwritten"))
+	metricdatatest.AssertEqual(t,
+		metricdata.Metrics{
+			Name:        "app_queue_size",
+			Description: "A gauge metric for testing",
+			Data: metricdata.Gauge[float64]{
+				DataPoints: []metricdata.DataPoint[float64]{
+					{Attributes: appLabels, Value: 7},
+				},
+			},
+		},
+		findMetrics(t, output[0], "app_queue_size"),
+		metricdatatest.IgnoreTimestamp(),
+	)
+	attrs, up := gaugeValue(t, findMetrics(t, output[0], upMetricName))
+	assert.Equal(t, appLabels, attrs)
+	assert.Equal(t, 1.0, up)
+	_, samples := gaugeValue(t, findMetrics(t, output[0], scrapeSamplesMetricName))
+	assert.Equal(t, 1.0, samples)
+	_, duration := gaugeValue(t, findMetrics(t, output[0], scrapeDurationMetricName))
+	assert.Positive(t, duration)
+
+	nodeJob := attribute.NewSet(attribute.String("job", "node"))
+	metricdatatest.AssertEqual(t,
+		metricdata.Metrics{
+			Name:        "node_cpu_seconds_total",
+			Description: "Seconds the CPUs spent in each mode.",
+			Data: metricdata.Sum[float64]{
+				Temporality: metricdata.CumulativeTemporality,
+				IsMonotonic: true,
+				DataPoints: []metricdata.DataPoint[float64]{
+					{
+						Attributes: attribute.NewSet(
+							attribute.String("cpu", "0"),
+							attribute.String("job", "node"),
+							attribute.String("mode", "idle"),
+						),
+						Value: 12.5,
+					},
+					{
+						Attributes: attribute.NewSet(
+							attribute.String("cpu", "0"),
+							attribute.String("job", "node"),
+							attribute.String("mode", "user"),
+						),
+						Value: 3,
+					},
+				},
+			},
+		},
+		findMetrics(t, output[1], "node_cpu_seconds_total"),
+		metricdatatest.IgnoreTimestamp(),
+	)
+	attrs, load := gaugeValue(t, findMetrics(t, output[1], "node_load1"))
+	assert.Equal(t, nodeJob, attrs)
+	assert.Equal(t, 0.5, load)
+
+	// The health metrics of both targets of the default scope are reported
+	// as a single metric.
+	names := make(map[string]int)
+	for _, m := range output[1].Metrics {
+		names[m.Name]++
+	}
+	for name, n := range names {
+		assert.Equal(t, 1, n, "metric %q reported %d times", name, n)
+	}
+	metricdatatest.AssertEqual(t,
+		metricdata.Metrics{
+			Name:        upMetricName,
+			Description: "1 if the target was scraped successfully, 0 otherwise.",
+			Data: metricdata.Gauge[float64]{
+				DataPoints: []metricdata.DataPoint[float64]{
+					{Attributes: nodeJob, Value: 1},
+					{Attributes: attribute.NewSet(attribute.String("job", "failing")), Value: 0},
+				},
+			},
+		},
+		findMetrics(t, output[1], upMetricName),
+		metricdatatest.IgnoreTimestamp(),
+	)
+}
+
+func TestProduceMergesFamilies(t *testing.T) {
+	previousHandler := otel.GetErrorHandler()
+	t.Cleanup(func() { otel.SetErrorHandler(previousHandler) })
+	var handledErr error
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		handledErr = err
+	}))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprint(w, textExposition)
+	}))
+	t.Cleanup(srv.Close)
+
+	reg := prometheus.NewRegistry()
+	newTestGauge(t, reg, "node_load1", prometheus.Labels{"job": "local"}, 1)
+	// The type of node_cpu_seconds_total scraped from the target is counter.
+	newTestGauge(t, reg, "node_cpu_seconds_total", nil, 1)
+
+	p := NewMetricProducer(
+		WithGatherer(reg),
+		WithScrapeTarget(ScrapeTarget{URL: srv.URL, Labels: map[string]string{"job": "node"}}),
+	)
+	output, err := p.Produce(t.Context())
+	require.NoError(t, err)
+	require.ErrorIs(t, handledErr, errConflictingTypes)
+	assert.ErrorContains(t, handledErr, "node_cpu_seconds_total")
+	require.Len(t, output, 1)
+
+	metricdatatest.AssertEqual(t,
+		metricdata.Metrics{
+			Name:        "node_load1",
+			Description: "A gauge metric for testing",
+			Data: metricdata.Gauge[float64]{
+				DataPoints: []metricdata.DataPoint[float64]{
+					{Attributes: attribute.NewSet(attribute.String("job", "local")), Value: 1},
+					{Attributes: attribute.NewSet(attribute.String("job", "node")), Value: 0.5},
+				},
+			},
+		},
+		findMetrics(t, output[0], "node_load1"),
+		metricdatatest.IgnoreTimestamp(),
+	)
+	// The family with a conflicting type is dropped.
+	_, ok := findMetrics(t, output[0], "node_cpu_seconds_total").Data.(metricdata.Gauge[float64])
+	assert.True(t, ok, "family of the gatherer kept")
+}
+
+func TestProduceScrapeTargetMaxBodySize(t *testing.T) {
+	previousHandler := otel.GetErrorHandler()
+	t.Cleanup(func() { otel.SetErrorHandler(previousHandler) })
+	var handledErr error
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		handledErr = err
+	}))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprint(w, textExposition)
+	}))
+	t.Cleanup(srv.Close)
+
+	p := NewMetricProducer(WithScrapeTarget(ScrapeTarget{
+		URL:         srv.URL,
+		MaxBodySize: int64(len(textExposition)),
+	}))
+	output, err := p.Produce(t.Context())
+	require.NoError(t, err)
+	require.NoError(t, handledErr, "body of the maximum size")
+	require.Len(t, output, 1)
+	_, up := gaugeValue(t, findMetrics(t, output[0], upMetricName))
+	assert.Equal(t, 1.0, up)
+
+	p = NewMetricProducer(WithScrapeTarget(ScrapeTarget{
+		URL:         srv.URL,
+		MaxBodySize: int64(len(textExposition)) - 1,
+	}))
+	output, err = p.Produce(t.Context())
+	require.NoError(t, err)
+	require.ErrorIs(t, handledErr, errBodyTooLarge)
+	require.Len(t, output, 1)
+	_, up = gaugeValue(t, findMetrics(t, output[0], upMetricName))
+	assert.Equal(t, 0.0, up)
+	_, samples := gaugeValue(t, findMetrics(t, output[0], scrapeSamplesMetricName))
+	assert.Equal(t, 0.0, samples, "no series of a truncated body")
+}
+
+func TestProduceScrapeTargetTimeout(t *testing.T) {
+	previousHandler := otel.GetErrorHandler()
+	t.Cleanup(func() { otel.SetErrorHandler(previousHandler) })
+	var handledErr error
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		handledErr = err
+	}))
+
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		<-done
+	}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { close(done) })
+
+	p := NewMetricProducer(WithScrapeTarget(ScrapeTarget{
+		URL:     srv.URL,
+		Timeout: 10 * time.Millisecond,
+	}))
+	output, err := p.Produce(t.Context())
+	require.NoError(t, err)
+	assert.Error(t, handledErr)
+
+	require.Len(t, output, 1)
+	_, up := gaugeValue(t, findMetrics(t, output[0], upMetricName))
+	assert.Equal(t, 0.0, up)
+	_, samples := gaugeValue(t, findMetrics(t, output[0], scrapeSamplesMetricName))
+	assert.Equal(t, 0.0, samples)
+}
+
+func TestProduceScrapeTargetFilters(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprint(w, textExposition)
+	}))
+	t.Cleanup(srv.Close)
+
+	p := NewMetricProducer(
+		WithScrapeTarget(ScrapeTarget{URL: srv.URL, Labels: map[string]string{"job": "node"}}),
+		WithExcludedMetrics(regexp.MustCompile("^(node_cpu_.*|scrape_.*)$")),
+		WithLabelRenames(map[string]string{"job": "service.name"}),
+	)
+	output, err := p.Produce(t.Context())
+	require.NoError(t, err)
+	require.Len(t, output, 1)
+
+	names := make([]string, 0, len(output[0].Metrics))
+	for _, m := range output[0].Metrics {
+		names = append(names, m.Name)
+	}
+	// The scrape health metrics are not filtered by name.
+	assert.Equal(t, []string{"node_load1", upMetricName, scrapeDurationMetricName, scrapeSamplesMetricName}, names)
+	attrs, _ := gaugeValue(t, findMetrics(t, output[0], upMetricName))
+	assert.Equal(t, attribute.NewSet(attribute.String("service.name", "node")), attrs)
+}
+
+func TestMergeLabels(t *testing.T) {
+	label := func(name, value string) *dto.LabelPair {
+		return &dto.LabelPair{Name: proto.String(name), Value: proto.String(value)}
+	}
+	labels := []*dto.LabelPair{label("a", "1"), label("job", "series")}
+
+	assert.Equal(t, labels, mergeLabels(labels, nil))
+	assert.Equal(t,
+		[]*dto.LabelPair{label("a", "1"), label("instance", "host:9100"), label("job", "node")},
+		mergeLabels(labels, []*dto.LabelPair{label("instance", "host:9100"), label("job", "node")}),
+	)
+}
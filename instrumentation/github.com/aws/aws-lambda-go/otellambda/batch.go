@@ -0,0 +1,295 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otellambda
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-lambda-go/events"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// xrayTraceHeader is the carrier key the AWSTraceHeader system attribute of
+// an SQS message is exposed under. It matches the header read by the AWS
+// X-Ray propagator.
+const xrayTraceHeader = "X-Amzn-Trace-Id"
+
+// Batch describes an invocation event that carries a batch of records, such
+// as an SQS or Kinesis event.
+type Batch struct {
+	// Attributes are added to the invocation span and to every span started
+	// with StartRecordSpan. They describe what is common to all records of
+	// the batch, e.g. the messaging system and destination.
+	Attributes []attribute.KeyValue
+
+	// Destination is the name of the queue or stream the batch was received
+	// from. It is used to name the spans started with StartRecordSpan.
+	Destination string
+
+	// Records are the records of the batch.
+	Records []BatchRecord
+}
+
+// BatchRecord is a single record of a Batch.
+type BatchRecord struct {
+	// ID identifies the record within the batch. It is the identifier
+	// reported back to Lambda in a partial batch failure response: the SQS
+	// message ID or the Kinesis sequence number.
+	ID string
+
+	// Carrier holds the trace context propagated with the record.
+	Carrier propagation.TextMapCarrier
+}
+
+// An EventToBatch function defines how the instrumentation should split a
+// batched invocation event into its records. It returns nil if eventJSON is
+// not a batch event, in which case the invocation is instrumented as if no
+// EventToBatch function was configured.
+type EventToBatch func(eventJSON []byte) *Batch
+
+// Compile time check our provided EventToBatch functions.
+var (
+	_ EventToBatch = SQSEventToBatch
+	_ EventToBatch = KinesisEventToBatch
+)
+
+// SQSEventToBatch is an EventToBatch function for events delivered by an SQS
+// event source mapping.
+//
+// The carrier of each record contains the String message attributes of the
+// message as well as the AWSTraceHeader system attribute, stored under the
+// X-Amzn-Trace-Id key read by the AWS X-Ray propagator.
+func SQSEventToBatch(eventJSON []byte) *Batch {
+	var event events.SQSEvent
+	if err := json.Unmarshal(eventJSON, &event); err != nil || len(event.Records) == 0 {
+		return nil
+	}
+	if event.Records[0].EventSource != "aws:sqs" {
+		return nil
+	}
+
+	b := &Batch{
+		Attributes: []attribute.KeyValue{semconv.MessagingSystemAWSSQS},
+		Records:    make([]BatchRecord, 0, len(event.Records)),
+	}
+	if queue := arnResource(event.Records[0].EventSourceARN); queue != "" {
+		b.Destination = queue
+		b.Attributes = append(b.Attributes, semconv.MessagingDestinationName(queue))
+	}
+
+	for _, msg := range event.Records {
+		carrier := propagation.HeaderCarrier{}
+		for k, v := range msg.MessageAttributes {
+			if v.StringValue != nil {
+				carrier.Set(k, *v.StringValue)
+			}
+		}
+		if h, ok := msg.Attributes["AWSTraceHeader"]; ok {
+			carrier.Set(xrayTraceHeader, h)
+		}
+		b.Records = append(b.Records, BatchRecord{ID: msg.MessageId, Carrier: carrier})
+	}
+	return b
+}
+
+// KinesisEventToBatch is an EventToBatch function for events delivered by a
+// Kinesis event source mapping.
+//
+// Kinesis records have no metadata of their own, therefore producers are
+// expected to wrap the payload in a JSON object envelope. The carrier of each
+// record contains the top-level string fields of that envelope (e.g.
+// "traceparent"). Records whose data is not a JSON object have an empty
+// carrier.
+func KinesisEventToBatch(eventJSON []byte) *Batch {
+	var event events.KinesisEvent
+	if err := json.Unmarshal(eventJSON, &event); err != nil || len(event.Records) == 0 {
+		return nil
+	}
+	if event.Records[0].EventSource != "aws:kinesis" {
+		return nil
+	}
+
+	b := &Batch{Records: make([]BatchRecord, 0, len(event.Records))}
+	if stream := strings.TrimPrefix(arnResource(event.Records[0].EventSourceArn), "stream/"); stream != "" {
+		b.Destination = stream
+		b.Attributes = append(b.Attributes,
+			semconv.AWSKinesisStreamName(stream),
+			semconv.MessagingDestinationName(stream),
+		)
+	}
+
+	for _, rec := range event.Records {
+		carrier := propagation.HeaderCarrier{}
+		var envelope map[string]any
+		if err := json.Unmarshal(rec.Kinesis.Data, &envelope); err == nil {
+			for k, v := range envelope {
+				if s, ok := v.(string); ok {
+					carrier.Set(k, s)
+				}
+			}
+		}
+		b.Records = append(b.Records, BatchRecord{ID: rec.Kinesis.SequenceNumber, Carrier: carrier})
+	}
+	return b
+}
+
+// arnResource returns the resource part of an ARN, i.e. everything after the
+// fifth colon. It returns an empty string if arn is not a valid ARN.
+func arnResource(arn string) string {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) < 6 {
+		return ""
+	}
+	return parts[5]
+}
+
+type batchStateKey struct{}
+
+// batchState holds the records of a batch invocation for the helpers
+// operating on the invocation context.
+type batchState struct {
+	tracer trace.Tracer
+	batch  *Batch
+
+	mu      sync.Mutex
+	records map[string]trace.SpanContext
+	spans   map[string]trace.Span
+	failed  []string
+}
+
+func newBatchState(tracer trace.Tracer, batch *Batch, records map[string]trace.SpanContext) *batchState {
+	return &batchState{
+		tracer:  tracer,
+		batch:   batch,
+		records: records,
+		spans:   make(map[string]trace.Span),
+	}
+}
+
+func batchStateFromContext(ctx context.Context) *batchState {
+	s, _ := ctx.Value(batchStateKey{}).(*batchState)
+	return s
+}
+
+// StartRecordSpan starts a span for processing the record with the given id
+// of the batch the invocation was started with. The span is a child of the
+// invocation span and is linked to the trace context propagated with the
+// record.
+//
+// If ctx does not belong to a batch invocation instrumented with an
+// EventToBatch function, or the batch does not contain a record with the
+// given id, ctx is returned along with a non-recording span.
+func StartRecordSpan(ctx context.Context, id string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	s := batchStateFromContext(ctx)
+	if s == nil {
+		return ctx, trace.SpanFromContext(context.Background())
+	}
+
+	s.mu.Lock()
+	sc, ok := s.records[id]
+	s.mu.Unlock()
+	if !ok {
+		return ctx, trace.SpanFromContext(context.Background())
+	}
+
+	name := "process"
+	if s.batch.Destination != "" {
+		name += " " + s.batch.Destination
+	}
+
+	attrs := make([]attribute.KeyValue, 0, len(s.batch.Attributes)+2)
+	attrs = append(attrs, s.batch.Attributes...)
+	attrs = append(attrs, semconv.MessagingOperationTypeProcess, semconv.MessagingMessageID(id))
+
+	startOpts := []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(attrs...),
+	}
+	if sc.IsValid() {
+		startOpts = append(startOpts, trace.WithLinks(trace.Link{SpanContext: sc}))
+	}
+	startOpts = append(startOpts, opts...)
+
+	ctx, span := s.tracer.Start(ctx, name, startOpts...)
+
+	s.mu.Lock()
+	s.spans[id] = span
+	s.mu.Unlock()
+	return ctx, span
+}
+
+// FailRecord marks the record with the given id of the batch the invocation
+// was started with as failed. Failed records are reported by
+// SQSEventResponse and KinesisEventResponse. If a span was started for the
+// record with StartRecordSpan, err is recorded on it and its status is set
+// to Error.
+//
+// FailRecord does nothing if ctx does not belong to a batch invocation
+// instrumented with an EventToBatch function.
+func FailRecord(ctx context.Context, id string, err error) {
+	s := batchStateFromContext(ctx)
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.records[id]; !ok {
+		return
+	}
+	for _, f := range s.failed {
+		if f == id {
+			return
+		}
+	}
+	s.failed = append(s.failed, id)
+
+	if span, ok := s.spans[id]; ok && err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.SetAttributes(semconv.ErrorType(err))
+	}
+}
+
+func (s *batchState) failedIDs() []string {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.failed...)
+}
+
+// SQSEventResponse returns the partial batch failure response reporting the
+// records marked as failed with FailRecord. It is meant to be returned from
+// handlers of SQS event source mappings with ReportBatchItemFailures enabled.
+func SQSEventResponse(ctx context.Context) events.SQSEventResponse {
+	ids := batchStateFromContext(ctx).failedIDs()
+	resp := events.SQSEventResponse{BatchItemFailures: make([]events.SQSBatchItemFailure, 0, len(ids))}
+	for _, id := range ids {
+		resp.BatchItemFailures = append(resp.BatchItemFailures, events.SQSBatchItemFailure{ItemIdentifier: id})
+	}
+	return resp
+}
+
+// KinesisEventResponse returns the partial batch failure response reporting
+// the records marked as failed with FailRecord. It is meant to be returned
+// from handlers of Kinesis event source mappings with
+// ReportBatchItemFailures enabled.
+func KinesisEventResponse(ctx context.Context) events.KinesisEventResponse {
+	ids := batchStateFromContext(ctx).failedIDs()
+	resp := events.KinesisEventResponse{BatchItemFailures: make([]events.KinesisBatchItemFailure, 0, len(ids))}
+	for _, id := range ids {
+		resp.BatchItemFailures = append(resp.BatchItemFailures, events.KinesisBatchItemFailure{ItemIdentifier: id})
+	}
+	return resp
+}
@@ -0,0 +1,157 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otellambda_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-lambda-go/otellambda"
+	"go.opentelemetry.io/contrib/propagators/aws/xray"
+)
+
+const (
+	sqsTraceparent = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	sqsXRayHeader  = "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1"
+)
+
+var sqsEventJSON = []byte(`{"Records":[
+	{"messageId":"m1","eventSource":"aws:sqs","eventSourceARN":"arn:aws:sqs:us-east-1:123456789012:orders",
+	 "messageAttributes":{"traceparent":{"stringValue":"` + sqsTraceparent + `","dataType":"String"}}},
+	{"messageId":"m2","eventSource":"aws:sqs","eventSourceARN":"arn:aws:sqs:us-east-1:123456789012:orders",
+	 "attributes":{"AWSTraceHeader":"` + sqsXRayHeader + `"}},
+	{"messageId":"m3","eventSource":"aws:sqs","eventSourceARN":"arn:aws:sqs:us-east-1:123456789012:orders"}
+]}`)
+
+type batchHandler func(context.Context, []byte) ([]byte, error)
+
+func (h batchHandler) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	return h(ctx, payload)
+}
+
+func TestSQSEventToBatch(t *testing.T) {
+	b := otellambda.SQSEventToBatch(sqsEventJSON)
+	require.NotNil(t, b)
+
+	assert.Equal(t, "orders", b.Destination)
+	assert.Equal(t, []attribute.KeyValue{
+		semconv.MessagingSystemAWSSQS,
+		semconv.MessagingDestinationName("orders"),
+	}, b.Attributes)
+
+	require.Len(t, b.Records, 3)
+	assert.Equal(t, "m1", b.Records[0].ID)
+	assert.Equal(t, sqsTraceparent, b.Records[0].Carrier.Get("traceparent"))
+	assert.Equal(t, sqsXRayHeader, b.Records[1].Carrier.Get("X-Amzn-Trace-Id"))
+	assert.Empty(t, b.Records[2].Carrier.Keys())
+}
+
+func TestSQSEventToBatchNotBatch(t *testing.T) {
+	assert.Nil(t, otellambda.SQSEventToBatch([]byte(`{"name":"value"}`)))
+	assert.Nil(t, otellambda.SQSEventToBatch([]byte(`not json`)))
+	assert.Nil(t, otellambda.SQSEventToBatch([]byte(`{"Records":[{"eventSource":"aws:kinesis"}]}`)))
+}
+
+func TestKinesisEventToBatch(t *testing.T) {
+	data := base64.StdEncoding.EncodeToString([]byte(`{"traceparent":"` + sqsTraceparent + `","payload":{"id":1}}`))
+	raw := base64.StdEncoding.EncodeToString([]byte(`raw payload`))
+	eventJSON := fmt.Appendf(nil, `{"Records":[
+		{"eventSource":"aws:kinesis","eventSourceARN":"arn:aws:kinesis:us-east-1:123456789012:stream/clicks","kinesis":{"sequenceNumber":"1","data":%q}},
+		{"eventSource":"aws:kinesis","eventSourceARN":"arn:aws:kinesis:us-east-1:123456789012:stream/clicks","kinesis":{"sequenceNumber":"2","data":%q}}
+	]}`, data, raw)
+
+	b := otellambda.KinesisEventToBatch(eventJSON)
+	require.NotNil(t, b)
+
+	assert.Equal(t, "clicks", b.Destination)
+	assert.Equal(t, []attribute.KeyValue{
+		semconv.AWSKinesisStreamName("clicks"),
+		semconv.MessagingDestinationName("clicks"),
+	}, b.Attributes)
+
+	require.Len(t, b.Records, 2)
+	assert.Equal(t, "1", b.Records[0].ID)
+	assert.Equal(t, sqsTraceparent, b.Records[0].Carrier.Get("traceparent"))
+	assert.Equal(t, "2", b.Records[1].ID)
+	assert.Empty(t, b.Records[1].Carrier.Keys())
+
+	assert.Nil(t, otellambda.KinesisEventToBatch(sqsEventJSON))
+}
+
+func TestWrapHandlerBatchLinks(t *testing.T) {
+	setEnvVars(t)
+	tp, memExporter := initMockTracerProvider()
+
+	prop := propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, xray.Propagator{})
+	var resp events.SQSEventResponse
+	handler := batchHandler(func(ctx context.Context, _ []byte) ([]byte, error) {
+		for _, id := range []string{"m1", "m2", "m3"} {
+			_, span := otellambda.StartRecordSpan(ctx, id)
+			if id == "m2" {
+				otellambda.FailRecord(ctx, id, errors.New("boom"))
+			}
+			span.End()
+		}
+		resp = otellambda.SQSEventResponse(ctx)
+		return nil, nil
+	})
+
+	wrapped := otellambda.WrapHandler(handler,
+		otellambda.WithTracerProvider(tp),
+		otellambda.WithPropagator(prop),
+		otellambda.WithEventToBatch(otellambda.SQSEventToBatch),
+	)
+	_, err := wrapped.Invoke(mockContext, sqsEventJSON)
+	require.NoError(t, err)
+
+	assert.Equal(t, []events.SQSBatchItemFailure{{ItemIdentifier: "m2"}}, resp.BatchItemFailures)
+
+	spans := memExporter.GetSpans()
+	require.Len(t, spans, 4)
+
+	invocation := spans[3]
+	assert.Equal(t, trace.SpanKindConsumer, invocation.SpanKind)
+	assert.Contains(t, invocation.Attributes, semconv.MessagingBatchMessageCount(3))
+	assert.Contains(t, invocation.Attributes, semconv.MessagingSystemAWSSQS)
+	assert.Contains(t, invocation.Attributes, semconv.MessagingOperationTypeProcess)
+	require.Len(t, invocation.Links, 2)
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", invocation.Links[0].SpanContext.TraceID().String())
+	assert.Equal(t, "5759e988bd862e3fe1be46a994272793", invocation.Links[1].SpanContext.TraceID().String())
+	assert.Equal(t, []attribute.KeyValue{semconv.MessagingMessageID("m2")}, invocation.Links[1].Attributes)
+
+	for _, s := range spans[:3] {
+		assert.Equal(t, "process orders", s.Name)
+		assert.Equal(t, trace.SpanKindConsumer, s.SpanKind)
+		assert.Equal(t, invocation.SpanContext.SpanID(), s.Parent.SpanID())
+	}
+	require.Len(t, spans[0].Links, 1)
+	assert.Equal(t, invocation.Links[0].SpanContext, spans[0].Links[0].SpanContext)
+	assert.Equal(t, codes.Error, spans[1].Status.Code)
+	assert.Contains(t, spans[1].Attributes, semconv.MessagingMessageID("m2"))
+	assert.Empty(t, spans[2].Links)
+}
+
+func TestRecordHelpersWithoutBatch(t *testing.T) {
+	ctx := context.Background()
+
+	got, span := otellambda.StartRecordSpan(ctx, "m1")
+	assert.Equal(t, ctx, got)
+	assert.False(t, span.IsRecording())
+
+	otellambda.FailRecord(ctx, "m1", errors.New("boom"))
+	assert.Empty(t, otellambda.SQSEventResponse(ctx).BatchItemFailures)
+	assert.Empty(t, otellambda.KinesisEventResponse(ctx).BatchItemFailures)
+}
@@ -5,8 +5,10 @@ package otellambda
 
 import (
 	"context"
+	"time"
 
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -76,6 +78,23 @@ type config struct {
 	// default can result in long data delays in asynchronous settings
 	Flusher Flusher
 
+	// FlushTimeout is the maximum amount of time the Flusher is given at
+	// the end of each invocation. The default value of FlushTimeout is 0,
+	// which means the flush is only bounded by the invocation deadline.
+	FlushTimeout time.Duration
+
+	// FlushDeadlineMargin is the amount of time before the invocation
+	// deadline at which the flush is abandoned so that flushing telemetry
+	// cannot make the function time out.
+	// The default value of FlushDeadlineMargin is 50 milliseconds.
+	FlushDeadlineMargin time.Duration
+
+	// MeterProvider is the MeterProvider which will be used
+	// to create the invocation metrics
+	// The default value of MeterProvider the global otel MeterProvider
+	// returned by otel.GetMeterProvider()
+	MeterProvider metric.MeterProvider
+
 	// EventToCarrier is the mechanism used to retrieve the TraceID
 	// from the event or environment and generate a TextMapCarrier which
 	// can then be used by a Propagator to extract the TraceID into our context
@@ -95,6 +114,13 @@ type config struct {
 	// The default value of TraceAttributeFn is nil, which means no attributes
 	// will be added to the span.
 	TraceAttributeFn TraceAttributeFn
+
+	// EventToBatch is the mechanism used to split batched events into
+	// their records. When it returns a Batch, the trace context of each
+	// record is added as a link to the span created by the instrumentation.
+	// The default value of EventToBatch is nil, which means batched events
+	// are not inspected.
+	EventToBatch EventToBatch
 }
 
 // WithTracerProvider configures the TracerProvider used by the
@@ -107,6 +133,22 @@ func WithTracerProvider(tracerProvider trace.TracerProvider) Option {
 	})
 }
 
+// WithMeterProvider configures the MeterProvider used by the
+// instrumentation to record the faas.invoke_duration, faas.errors,
+// faas.coldstarts and faas.timeouts metrics.
+//
+// If the MeterProvider has a ForceFlush(context.Context) error method, like
+// the MeterProvider of the go.opentelemetry.io/otel/sdk/metric package, it is
+// flushed at the end of each invocation, along with the Flusher and within
+// the same deadline, so the metrics are exported before the lambda freezes.
+//
+// By default, the global MeterProvider is used.
+func WithMeterProvider(meterProvider metric.MeterProvider) Option {
+	return optionFunc(func(c *config) {
+		c.MeterProvider = meterProvider
+	})
+}
+
 // WithFlusher sets the used flusher.
 func WithFlusher(flusher Flusher) Option {
 	return optionFunc(func(c *config) {
@@ -114,6 +156,27 @@ func WithFlusher(flusher Flusher) Option {
 	})
 }
 
+// WithFlushTimeout sets the maximum amount of time the Flusher is given at
+// the end of each invocation.
+//
+// Regardless of this option, the flush is abandoned once the invocation
+// deadline minus the margin set with WithFlushDeadlineMargin is reached.
+func WithFlushTimeout(timeout time.Duration) Option {
+	return optionFunc(func(c *config) {
+		c.FlushTimeout = timeout
+	})
+}
+
+// WithFlushDeadlineMargin sets how long before the invocation deadline the
+// flush at the end of each invocation is abandoned.
+//
+// By default, a margin of 50 milliseconds is used.
+func WithFlushDeadlineMargin(margin time.Duration) Option {
+	return optionFunc(func(c *config) {
+		c.FlushDeadlineMargin = margin
+	})
+}
+
 // WithEventToCarrier sets the used EventToCarrier.
 func WithEventToCarrier(eventToCarrier EventToCarrier) Option {
 	return optionFunc(func(c *config) {
@@ -136,3 +199,16 @@ func WithTraceAttributeFn(fn TraceAttributeFn) Option {
 		c.TraceAttributeFn = fn
 	})
 }
+
+// WithEventToBatch configures a function that splits batched events into
+// their records. The trace context propagated with each record is extracted
+// with the configured Propagator and added as a link to the invocation span,
+// and the records become available to StartRecordSpan and FailRecord.
+//
+// SQSEventToBatch and KinesisEventToBatch are provided for the SQS and
+// Kinesis event source mappings.
+func WithEventToBatch(fn EventToBatch) Option {
+	return optionFunc(func(c *config) {
+		c.EventToBatch = fn
+	})
+}
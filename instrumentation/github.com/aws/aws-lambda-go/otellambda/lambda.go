@@ -5,14 +5,19 @@ package otellambda
 
 import (
 	"context"
+	"errors"
 	"log"
 	"os"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/aws/aws-lambda-go/lambdacontext"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"go.opentelemetry.io/otel/semconv/v1.43.0/faasconv"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -21,30 +26,67 @@ const (
 	ScopeName = "go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-lambda-go/otellambda"
 )
 
+const defaultFlushDeadlineMargin = 50 * time.Millisecond
+
 var errorLogger = log.New(log.Writer(), "OTel Lambda Error: ", 0)
 
 type instrumentor struct {
 	configuration config
 	resAttrs      []attribute.KeyValue
 	tracer        trace.Tracer
+	metrics       *invocationMetrics
+}
+
+// invocationMetrics holds the instruments recording the FaaS metrics of the
+// invocations handled by an instrumentor.
+type invocationMetrics struct {
+	invokeDuration faasconv.InvokeDuration
+	errors         faasconv.Errors
+	coldstarts     faasconv.Coldstarts
+	timeouts       faasconv.Timeouts
+
+	// invoked is set once the first invocation started.
+	invoked atomic.Bool
+}
+
+func newInvocationMetrics(meter metric.Meter) *invocationMetrics {
+	m := &invocationMetrics{}
+	var err error
+	if m.invokeDuration, err = faasconv.NewInvokeDuration(meter); err != nil {
+		otel.Handle(err)
+	}
+	if m.errors, err = faasconv.NewErrors(meter); err != nil {
+		otel.Handle(err)
+	}
+	if m.coldstarts, err = faasconv.NewColdstarts(meter); err != nil {
+		otel.Handle(err)
+	}
+	if m.timeouts, err = faasconv.NewTimeouts(meter); err != nil {
+		otel.Handle(err)
+	}
+	return m
 }
 
 func newInstrumentor(opts ...Option) instrumentor {
 	cfg := config{
-		TracerProvider:   otel.GetTracerProvider(),
-		Flusher:          &noopFlusher{},
-		EventToCarrier:   emptyEventToCarrier,
-		Propagator:       otel.GetTextMapPropagator(),
-		TraceAttributeFn: emptyTraceAttributeFn,
+		TracerProvider:      otel.GetTracerProvider(),
+		MeterProvider:       otel.GetMeterProvider(),
+		Flusher:             &noopFlusher{},
+		FlushDeadlineMargin: defaultFlushDeadlineMargin,
+		EventToCarrier:      emptyEventToCarrier,
+		Propagator:          otel.GetTextMapPropagator(),
+		TraceAttributeFn:    emptyTraceAttributeFn,
 	}
 	for _, opt := range opts {
 		opt.apply(&cfg)
 	}
 
+	meter := cfg.MeterProvider.Meter(ScopeName, metric.WithInstrumentationVersion(Version))
 	return instrumentor{
 		configuration: cfg,
 		tracer:        cfg.TracerProvider.Tracer(ScopeName, trace.WithInstrumentationVersion(Version)),
 		resAttrs:      []attribute.KeyValue{},
+		metrics:       newInvocationMetrics(meter),
 	}
 }
 
@@ -57,7 +99,20 @@ func (i *instrumentor) tracingBegin(ctx context.Context, eventJSON []byte) (cont
 	var span trace.Span
 	spanName := os.Getenv("AWS_LAMBDA_FUNCTION_NAME")
 
+	spanKind := trace.SpanKindServer
 	var attributes []attribute.KeyValue
+	var links []trace.Link
+	var batch *batchState
+	if i.configuration.EventToBatch != nil {
+		if b := i.configuration.EventToBatch(eventJSON); b != nil {
+			spanKind = trace.SpanKindConsumer
+			attributes = append(attributes, semconv.FaaSTriggerPubSub, semconv.MessagingOperationTypeProcess)
+			attributes = append(attributes, b.Attributes...)
+			attributes = append(attributes, semconv.MessagingBatchMessageCount(len(b.Records)))
+			links, batch = i.batchLinks(b)
+		}
+	}
+
 	customAttrs := i.configuration.TraceAttributeFn(eventJSON)
 	attributes = append(attributes, customAttrs...)
 	lc, ok := lambdacontext.FromContext(ctx)
@@ -83,18 +138,99 @@ func (i *instrumentor) tracingBegin(ctx context.Context, eventJSON []byte) (cont
 		attributes = append(attributes, i.resAttrs...)
 	}
 
-	ctx, span = i.tracer.Start(ctx, spanName, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attributes...))
+	ctx, span = i.tracer.Start(ctx, spanName, trace.WithSpanKind(spanKind), trace.WithAttributes(attributes...), trace.WithLinks(links...))
+	if batch != nil {
+		ctx = context.WithValue(ctx, batchStateKey{}, batch)
+	}
 
 	return ctx, span
 }
 
-// Logic to wrap up OTel Tracing.
-func (i *instrumentor) tracingEnd(ctx context.Context, span trace.Span) {
+// batchLinks extracts the trace context of every record of b and returns
+// the links to add to the invocation span along with the batch state used by
+// the record helpers.
+func (i *instrumentor) batchLinks(b *Batch) ([]trace.Link, *batchState) {
+	links := make([]trace.Link, 0, len(b.Records))
+	records := make(map[string]trace.SpanContext, len(b.Records))
+	for _, r := range b.Records {
+		var sc trace.SpanContext
+		if r.Carrier != nil {
+			sc = trace.SpanContextFromContext(i.configuration.Propagator.Extract(context.Background(), r.Carrier))
+		}
+		records[r.ID] = sc
+		if sc.IsValid() {
+			links = append(links, trace.Link{
+				SpanContext: sc,
+				Attributes:  []attribute.KeyValue{semconv.MessagingMessageID(r.ID)},
+			})
+		}
+	}
+	return links, newBatchState(i.tracer, b, records)
+}
+
+// Logic to wrap up OTel Tracing. start is the time the invocation started
+// and err the error returned by the handler, if any.
+func (i *instrumentor) tracingEnd(ctx context.Context, span trace.Span, start time.Time, err error) {
 	span.End()
 
+	i.recordMetrics(ctx, start, err)
+
 	// force flush any tracing data since lambda may freeze
-	err := i.configuration.Flusher.ForceFlush(ctx)
-	if err != nil {
+	flushCtx, cancel := i.flushContext(ctx)
+	defer cancel()
+	if err := i.configuration.Flusher.ForceFlush(flushCtx); err != nil {
 		errorLogger.Println("failed to force a flush, lambda may freeze before instrumentation exported: ", err)
 	}
+	// The metrics recorded above are lost as well if the lambda freezes
+	// before they are exported.
+	if f, ok := i.configuration.MeterProvider.(Flusher); ok {
+		if err := f.ForceFlush(flushCtx); err != nil {
+			errorLogger.Println("failed to force a flush of the metrics, lambda may freeze before instrumentation exported: ", err)
+		}
+	}
+}
+
+func (i *instrumentor) recordMetrics(ctx context.Context, start time.Time, err error) {
+	var attrs []attribute.KeyValue
+	if batchStateFromContext(ctx) != nil {
+		attrs = append(attrs, i.metrics.invokeDuration.AttrTrigger(faasconv.TriggerPubSub))
+	}
+
+	// Record on a context which is not done yet, the invocation context
+	// may have already expired.
+	mctx := context.WithoutCancel(ctx)
+
+	i.metrics.invokeDuration.Record(mctx, time.Since(start).Seconds(), attrs...)
+	if !i.metrics.invoked.Swap(true) {
+		i.metrics.coldstarts.Add(mctx, 1, attrs...)
+	}
+	timedOut := errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded)
+	if timedOut {
+		i.metrics.timeouts.Add(mctx, 1, attrs...)
+	}
+	if err != nil || timedOut {
+		i.metrics.errors.Add(mctx, 1, attrs...)
+	}
+}
+
+// flushContext returns the context used to flush telemetry at the end of
+// the invocation. It is not canceled along with ctx, but expires no later
+// than the configured margin before the invocation deadline, so flushing
+// cannot make the function time out.
+func (i *instrumentor) flushContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if ok {
+		deadline = deadline.Add(-i.configuration.FlushDeadlineMargin)
+	}
+	if t := i.configuration.FlushTimeout; t > 0 {
+		if d := time.Now().Add(t); !ok || d.Before(deadline) {
+			deadline, ok = d, true
+		}
+	}
+
+	fctx := context.WithoutCancel(ctx)
+	if !ok {
+		return context.WithCancel(fctx)
+	}
+	return context.WithDeadline(fctx, deadline)
 }
@@ -0,0 +1,207 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otellambda_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/noop"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata/metricdatatest"
+
+	"go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-lambda-go/otellambda"
+)
+
+func TestInvocationMetrics(t *testing.T) {
+	setEnvVars(t)
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	errHandler := batchHandler(func(context.Context, []byte) ([]byte, error) {
+		return nil, errors.New("boom")
+	})
+	timeoutHandler := batchHandler(func(context.Context, []byte) ([]byte, error) {
+		return nil, context.DeadlineExceeded
+	})
+
+	wrapped := otellambda.WrapHandler(emptyHandler{}, otellambda.WithMeterProvider(mp))
+	_, err := wrapped.Invoke(mockContext, []byte{})
+	require.NoError(t, err)
+	_, err = wrapped.Invoke(mockContext, []byte{})
+	require.NoError(t, err)
+
+	_, err = otellambda.WrapHandler(errHandler, otellambda.WithMeterProvider(mp)).Invoke(mockContext, []byte{})
+	require.Error(t, err)
+
+	_, err = otellambda.WrapHandler(timeoutHandler, otellambda.WithMeterProvider(mp)).Invoke(mockContext, []byte{})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(t.Context(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	sm := rm.ScopeMetrics[0]
+	assert.Equal(t, otellambda.ScopeName, sm.Scope.Name)
+
+	got := make(map[string]metricdata.Metrics, len(sm.Metrics))
+	for _, m := range sm.Metrics {
+		got[m.Name] = m
+	}
+
+	sum := func(v int64) metricdata.Sum[int64] {
+		return metricdata.Sum[int64]{
+			Temporality: metricdata.CumulativeTemporality,
+			IsMonotonic: true,
+			DataPoints:  []metricdata.DataPoint[int64]{{Attributes: *attribute.EmptySet(), Value: v}},
+		}
+	}
+
+	// Every wrapped handler has its own cold start.
+	metricdatatest.AssertAggregationsEqual(t, sum(3), got["faas.coldstarts"].Data, metricdatatest.IgnoreTimestamp(), metricdatatest.IgnoreExemplars())
+	metricdatatest.AssertAggregationsEqual(t, sum(2), got["faas.errors"].Data, metricdatatest.IgnoreTimestamp(), metricdatatest.IgnoreExemplars())
+	metricdatatest.AssertAggregationsEqual(t, sum(1), got["faas.timeouts"].Data, metricdatatest.IgnoreTimestamp(), metricdatatest.IgnoreExemplars())
+
+	hist, ok := got["faas.invoke_duration"].Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, hist.DataPoints, 1)
+	assert.Equal(t, uint64(4), hist.DataPoints[0].Count)
+}
+
+type deadlineFlusher struct {
+	deadline time.Time
+	ok       bool
+	err      error
+}
+
+func (f *deadlineFlusher) ForceFlush(ctx context.Context) error {
+	f.deadline, f.ok = ctx.Deadline()
+	f.err = ctx.Err()
+	return nil
+}
+
+func TestFlushDeadline(t *testing.T) {
+	setEnvVars(t)
+
+	t.Run("NoDeadline", func(t *testing.T) {
+		flusher := &deadlineFlusher{}
+		wrapped := otellambda.WrapHandler(emptyHandler{}, otellambda.WithFlusher(flusher))
+		_, err := wrapped.Invoke(mockContext, []byte{})
+		require.NoError(t, err)
+		assert.False(t, flusher.ok)
+	})
+
+	t.Run("InvocationDeadline", func(t *testing.T) {
+		deadline := time.Now().Add(time.Minute)
+		ctx, cancel := context.WithDeadline(mockContext, deadline)
+		defer cancel()
+
+		flusher := &deadlineFlusher{}
+		wrapped := otellambda.WrapHandler(emptyHandler{},
+			otellambda.WithFlusher(flusher),
+			otellambda.WithFlushDeadlineMargin(time.Second),
+		)
+		_, err := wrapped.Invoke(ctx, []byte{})
+		require.NoError(t, err)
+		require.True(t, flusher.ok)
+		assert.Equal(t, deadline.Add(-time.Second), flusher.deadline)
+	})
+
+	t.Run("FlushTimeout", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(mockContext, time.Hour)
+		defer cancel()
+
+		flusher := &deadlineFlusher{}
+		wrapped := otellambda.WrapHandler(emptyHandler{},
+			otellambda.WithFlusher(flusher),
+			otellambda.WithFlushTimeout(time.Second),
+		)
+		_, err := wrapped.Invoke(ctx, []byte{})
+		require.NoError(t, err)
+		require.True(t, flusher.ok)
+		assert.WithinDuration(t, time.Now().Add(time.Second), flusher.deadline, time.Second)
+	})
+
+	t.Run("CanceledInvocation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(mockContext)
+		handler := batchHandler(func(context.Context, []byte) ([]byte, error) {
+			cancel()
+			return nil, nil
+		})
+
+		flusher := &deadlineFlusher{}
+		wrapped := otellambda.WrapHandler(handler, otellambda.WithFlusher(flusher))
+		_, err := wrapped.Invoke(ctx, []byte{})
+		require.NoError(t, err)
+		assert.NoError(t, flusher.err)
+	})
+}
+
+// flushingMeterProvider is a MeterProvider recording its flushes.
+type flushingMeterProvider struct {
+	noop.MeterProvider
+	*deadlineFlusher
+}
+
+func TestFlushMeterProvider(t *testing.T) {
+	setEnvVars(t)
+	deadline := time.Now().Add(time.Minute)
+	ctx, cancel := context.WithDeadline(mockContext, deadline)
+	defer cancel()
+
+	flusher := &deadlineFlusher{}
+	mp := flushingMeterProvider{deadlineFlusher: &deadlineFlusher{}}
+	wrapped := otellambda.WrapHandler(emptyHandler{},
+		otellambda.WithFlusher(flusher),
+		otellambda.WithMeterProvider(mp),
+		otellambda.WithFlushDeadlineMargin(time.Second),
+	)
+	_, err := wrapped.Invoke(ctx, []byte{})
+	require.NoError(t, err)
+
+	require.True(t, mp.ok, "meter provider not flushed within a deadline")
+	assert.Equal(t, deadline.Add(-time.Second), mp.deadline)
+	assert.Equal(t, flusher.deadline, mp.deadline, "same deadline as the Flusher")
+}
+
+// exportCounter is a metric exporter counting its exports.
+type exportCounter struct {
+	exports int
+}
+
+func (*exportCounter) Temporality(k sdkmetric.InstrumentKind) metricdata.Temporality {
+	return sdkmetric.DefaultTemporalitySelector(k)
+}
+
+func (*exportCounter) Aggregation(k sdkmetric.InstrumentKind) sdkmetric.Aggregation {
+	return sdkmetric.DefaultAggregationSelector(k)
+}
+
+func (e *exportCounter) Export(context.Context, *metricdata.ResourceMetrics) error {
+	e.exports++
+	return nil
+}
+
+func (*exportCounter) ForceFlush(context.Context) error { return nil }
+
+func (*exportCounter) Shutdown(context.Context) error { return nil }
+
+func TestFlushSDKMeterProvider(t *testing.T) {
+	setEnvVars(t)
+	exporter := &exportCounter{}
+	// The periodic reader would not export during the test without a flush.
+	reader := sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(time.Hour))
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	t.Cleanup(func() { _ = mp.Shutdown(context.Background()) })
+
+	wrapped := otellambda.WrapHandler(emptyHandler{}, otellambda.WithMeterProvider(mp))
+	_, err := wrapped.Invoke(mockContext, []byte{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, exporter.exports, "metrics exported at the end of the invocation")
+}
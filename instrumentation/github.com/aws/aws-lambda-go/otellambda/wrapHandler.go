@@ -5,6 +5,7 @@ package otellambda
 
 import (
 	"context"
+	"time"
 
 	"github.com/aws/aws-lambda-go/lambda"
 )
@@ -21,9 +22,10 @@ type wrappedHandler struct {
 var _ lambda.Handler = wrappedHandler{}
 
 // Invoke adds OTel span surrounding customer Handler invocation.
-func (h wrappedHandler) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+func (h wrappedHandler) Invoke(ctx context.Context, payload []byte) (_ []byte, err error) {
+	start := time.Now()
 	ctx, span := h.instrumentor.tracingBegin(ctx, payload)
-	defer h.instrumentor.tracingEnd(ctx, span)
+	defer func() { h.instrumentor.tracingEnd(ctx, span, start, err) }()
 
 	response, err := h.handler.Invoke(ctx, payload)
 	if err != nil {
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"time"
 )
 
 // wrappedHandlerFunction is a struct which only holds an instrumentor and is
@@ -70,12 +71,7 @@ func (whf *wrappedHandlerFunction) wrapperInternals(ctx context.Context, handler
 	response := wrappedLambdaHandler.Call(argsWrapped)[0].Interface().([]reflect.Value)
 
 	// convert return values into (any, error)
-	var err error
-	if len(response) > 0 {
-		if errVal, ok := reflect.TypeAssert[error](response[len(response)-1]); ok {
-			err = errVal
-		}
-	}
+	err := returnedError(response)
 	var val any
 	if len(response) > 1 {
 		val = response[0].Interface()
@@ -140,9 +136,10 @@ func InstrumentHandler(handlerFunc any, options ...Option) any {
 
 // Adds OTel span surrounding customer handler call.
 func (whf *wrappedHandlerFunction) wrapper(handlerFunc any) func(ctx context.Context, eventJSON []byte, event any, takesContext bool) []reflect.Value {
-	return func(ctx context.Context, eventJSON []byte, event any, takesContext bool) []reflect.Value {
+	return func(ctx context.Context, eventJSON []byte, event any, takesContext bool) (response []reflect.Value) {
+		start := time.Now()
 		ctx, span := whf.instrumentor.tracingBegin(ctx, eventJSON)
-		defer whf.instrumentor.tracingEnd(ctx, span)
+		defer func() { whf.instrumentor.tracingEnd(ctx, span, start, returnedError(response)) }()
 
 		handler := reflect.ValueOf(handlerFunc)
 		var args []reflect.Value
@@ -153,12 +150,21 @@ func (whf *wrappedHandlerFunction) wrapper(handlerFunc any) func(ctx context.Con
 			args = append(args, reflect.ValueOf(event))
 		}
 
-		response := handler.Call(args)
+		response = handler.Call(args)
 
 		return response
 	}
 }
 
+// returnedError returns the error returned by the customer handler, if any.
+func returnedError(response []reflect.Value) error {
+	if len(response) == 0 {
+		return nil
+	}
+	err, _ := reflect.TypeAssert[error](response[len(response)-1])
+	return err
+}
+
 // Determine if an any is nil or the
 // if the reflect.Value of the event is nil.
 func eventExists(event any) bool {
@@ -0,0 +1,149 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelmongo
+
+import (
+	"context"
+	"net/url"
+	"slices"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Collection wraps a [mongo.Collection] to propagate the trace context of the
+// operations it performs to the MongoDB server as the comment of the
+// commands they send. The comment is recorded in the server slow query log
+// and in system.profile, which allows correlating them with the originating
+// trace.
+//
+// The trace context is injected with the configured propagators and
+// formatted like a sqlcommenter comment, e.g.
+//
+//	traceparent='00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01'
+//
+// Find, FindOne, Aggregate, UpdateOne, UpdateMany and UpdateByID are
+// commented. A comment set by the caller is never overwritten. All other
+// methods are those of the wrapped [mongo.Collection].
+type Collection struct {
+	*mongo.Collection
+
+	propagators propagation.TextMapPropagator
+}
+
+// WrapCollection returns a Collection wrapping coll. Only the WithPropagators
+// option is used, all others are ignored.
+func WrapCollection(coll *mongo.Collection, opts ...Option) *Collection {
+	cfg := newConfig(opts...)
+	return &Collection{Collection: coll, propagators: cfg.Propagators}
+}
+
+// Find executes a find command with the trace context of ctx as comment.
+// See [mongo.Collection.Find].
+func (c *Collection) Find(ctx context.Context, filter any, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	opts = withComment(opts, c.comment(ctx),
+		func(o *options.FindOptions) bool { return o.Comment != nil },
+		func(comment string) *options.FindOptions { return options.Find().SetComment(comment) },
+	)
+	return c.Collection.Find(ctx, filter, opts...)
+}
+
+// FindOne executes a find command with the trace context of ctx as comment.
+// See [mongo.Collection.FindOne].
+func (c *Collection) FindOne(ctx context.Context, filter any, opts ...*options.FindOneOptions) *mongo.SingleResult {
+	opts = withComment(opts, c.comment(ctx),
+		func(o *options.FindOneOptions) bool { return o.Comment != nil },
+		func(comment string) *options.FindOneOptions { return options.FindOne().SetComment(comment) },
+	)
+	return c.Collection.FindOne(ctx, filter, opts...)
+}
+
+// Aggregate executes an aggregate command with the trace context of ctx as
+// comment. See [mongo.Collection.Aggregate].
+func (c *Collection) Aggregate(ctx context.Context, pipeline any, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+	opts = withComment(opts, c.comment(ctx),
+		func(o *options.AggregateOptions) bool { return o.Comment != nil },
+		func(comment string) *options.AggregateOptions { return options.Aggregate().SetComment(comment) },
+	)
+	return c.Collection.Aggregate(ctx, pipeline, opts...)
+}
+
+// UpdateOne executes an update command with the trace context of ctx as
+// comment. See [mongo.Collection.UpdateOne].
+func (c *Collection) UpdateOne(ctx context.Context, filter, update any, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	opts = withUpdateComment(opts, c.comment(ctx))
+	return c.Collection.UpdateOne(ctx, filter, update, opts...)
+}
+
+// UpdateByID executes an update command with the trace context of ctx as
+// comment. See [mongo.Collection.UpdateByID].
+func (c *Collection) UpdateByID(ctx context.Context, id, update any, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	opts = withUpdateComment(opts, c.comment(ctx))
+	return c.Collection.UpdateByID(ctx, id, update, opts...)
+}
+
+// UpdateMany executes an update command with the trace context of ctx as
+// comment. See [mongo.Collection.UpdateMany].
+func (c *Collection) UpdateMany(ctx context.Context, filter, update any, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	opts = withUpdateComment(opts, c.comment(ctx))
+	return c.Collection.UpdateMany(ctx, filter, update, opts...)
+}
+
+func (c *Collection) comment(ctx context.Context) string {
+	return traceComment(ctx, c.propagators)
+}
+
+func withUpdateComment(opts []*options.UpdateOptions, comment string) []*options.UpdateOptions {
+	return withComment(opts, comment,
+		func(o *options.UpdateOptions) bool { return o.Comment != nil },
+		func(comment string) *options.UpdateOptions { return options.Update().SetComment(comment) },
+	)
+}
+
+// withComment returns opts with an additional option created by newOpt
+// setting comment. opts is returned unchanged if comment is empty or if
+// hasComment reports that one of opts already sets a comment.
+func withComment[T any](opts []*T, comment string, hasComment func(*T) bool, newOpt func(string) *T) []*T {
+	if comment == "" {
+		return opts
+	}
+	for _, o := range opts {
+		if o != nil && hasComment(o) {
+			return opts
+		}
+	}
+	return append(slices.Clip(opts), newOpt(comment))
+}
+
+// traceComment returns the fields injected by propagators for ctx formatted
+// as a sqlcommenter comment: URL-encoded key='value' pairs sorted by key and
+// separated by commas. It returns an empty string if nothing was injected.
+func traceComment(ctx context.Context, propagators propagation.TextMapPropagator) string {
+	carrier := propagation.MapCarrier{}
+	propagators.Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return ""
+	}
+
+	keys := carrier.Keys()
+	slices.Sort(keys)
+
+	var sb strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(commentEscape(k))
+		sb.WriteString("='")
+		sb.WriteString(commentEscape(carrier[k]))
+		sb.WriteByte('\'')
+	}
+	return sb.String()
+}
+
+func commentEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
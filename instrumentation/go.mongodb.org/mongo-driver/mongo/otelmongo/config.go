@@ -5,6 +5,8 @@ package otelmongo
 
 import (
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -13,23 +15,35 @@ const ScopeName = "go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mo
 
 // config is used to configure the mongo tracer.
 type config struct {
+	MeterProvider  metric.MeterProvider
 	TracerProvider trace.TracerProvider
+	Propagators    propagation.TextMapPropagator
 
+	Meter  metric.Meter
 	Tracer trace.Tracer
 
 	CommandAttributeDisabled bool
+	QueryTextMode            QueryTextMode
+	QueryTextMaxLength       int
 }
 
 // newConfig returns a config with all Options set.
 func newConfig(opts ...Option) config {
 	cfg := config{
+		MeterProvider:            otel.GetMeterProvider(),
 		TracerProvider:           otel.GetTracerProvider(),
+		Propagators:              otel.GetTextMapPropagator(),
 		CommandAttributeDisabled: true,
 	}
 	for _, opt := range opts {
 		opt.apply(&cfg)
 	}
 
+	cfg.Meter = cfg.MeterProvider.Meter(
+		ScopeName,
+		metric.WithInstrumentationVersion(Version),
+	)
+
 	cfg.Tracer = cfg.TracerProvider.Tracer(
 		ScopeName,
 		trace.WithInstrumentationVersion(Version),
@@ -48,6 +62,16 @@ func (o optionFunc) apply(c *config) {
 	o(c)
 }
 
+// WithMeterProvider specifies a [metric.MeterProvider] to use for creating a Meter.
+// If none is specified, the global MeterProvider is used.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return optionFunc(func(cfg *config) {
+		if provider != nil {
+			cfg.MeterProvider = provider
+		}
+	})
+}
+
 // WithTracerProvider specifies a tracer provider to use for creating a tracer.
 // If none is specified, the global provider is used.
 func WithTracerProvider(provider trace.TracerProvider) Option {
@@ -58,6 +82,17 @@ func WithTracerProvider(provider trace.TracerProvider) Option {
 	})
 }
 
+// WithPropagators specifies the propagators used by a Collection to inject
+// the trace context into command comments. If none is specified, the global
+// TextMapPropagator is used.
+func WithPropagators(propagators propagation.TextMapPropagator) Option {
+	return optionFunc(func(cfg *config) {
+		if propagators != nil {
+			cfg.Propagators = propagators
+		}
+	})
+}
+
 // WithCommandAttributeDisabled specifies if the MongoDB command is added as an attribute to Spans or not.
 // This is disabled by default and the MongoDB command will not be added as an attribute
 // to Spans if this option is not provided.
@@ -66,3 +101,22 @@ func WithCommandAttributeDisabled(disabled bool) Option {
 		cfg.CommandAttributeDisabled = disabled
 	})
 }
+
+// WithQueryTextMode specifies how the MongoDB command is rendered in the
+// db.query.text attribute when it is enabled with
+// WithCommandAttributeDisabled(false). If none is specified, QueryTextRaw is
+// used.
+func WithQueryTextMode(mode QueryTextMode) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.QueryTextMode = mode
+	})
+}
+
+// WithQueryTextMaxLength specifies the maximum length in bytes of the
+// db.query.text attribute. Longer values are truncated. If none is specified,
+// or maxLength is not positive, the attribute is not truncated.
+func WithQueryTextMaxLength(maxLength int) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.QueryTextMaxLength = maxLength
+	})
+}
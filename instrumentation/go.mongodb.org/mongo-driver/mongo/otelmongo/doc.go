@@ -7,7 +7,12 @@
 // go.mongodb.org/mongo-driver/mongo.
 //
 // NewMonitor will return an event.CommandMonitor which is used to trace
-// requests.
+// requests. NewPoolMonitor and NewServerMonitor return an event.PoolMonitor
+// and an event.ServerMonitor which are used to collect connection pool and
+// server monitoring metrics.
+//
+// WrapCollection wraps a mongo.Collection to inject the trace context into
+// the comment of the commands it sends.
 //
 // This code was originally based on the following:
 //   - https://github.com/DataDog/dd-trace-go/tree/02f0449efa3cb382d499fadc873957385dcb2192/contrib/go.mongodb.org/mongo-driver/mongo
@@ -10,7 +10,6 @@ import (
 	"strconv"
 	"strings"
 
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/event"
 	"go.opentelemetry.io/otel/attribute"
 	semconv1210 "go.opentelemetry.io/otel/semconv/v1.21.0"
@@ -41,6 +40,8 @@ func NewEventMonitor() EventMonitor {
 type AttributeOptions struct {
 	collectionName           string
 	commandAttributeDisabled bool
+	queryTextObfuscated      bool
+	queryTextMaxLength       int
 }
 
 // AttributeOption is a function type that modifies AttributeOptions.
@@ -62,6 +63,22 @@ func WithCommandAttributeDisabled(disabled bool) AttributeOption {
 	}
 }
 
+// WithQueryTextObfuscated is a functional option to replace the literal
+// values of the command attributes with ?.
+func WithQueryTextObfuscated(obfuscated bool) AttributeOption {
+	return func(opts *AttributeOptions) {
+		opts.queryTextObfuscated = obfuscated
+	}
+}
+
+// WithQueryTextMaxLength is a functional option to truncate the command
+// attributes to maxLength bytes.
+func WithQueryTextMaxLength(maxLength int) AttributeOption {
+	return func(opts *AttributeOptions) {
+		opts.queryTextMaxLength = maxLength
+	}
+}
+
 // hasOptIn returns true if the comma-separated version string contains the
 // exact optIn value.
 func hasOptIn(version, optIn string) bool {
@@ -90,16 +107,17 @@ func (m EventMonitor) CommandStartedTraceAttrs(
 	return commandStartedTraceAttrs(evt, opts...)
 }
 
-// peerInfo extracts the hostname and port from a CommandStartedEvent.
+// PeerInfo extracts the hostname and port from a connection ID, e.g. the
+// ConnectionID of a CommandStartedEvent, or from a server address.
 //
 // ConnectionID is formatted as "<address>[-<connection number>]" for pooled
 // connections, so the "[-<connection number>]" suffix is stripped before
 // parsing; otherwise net.SplitHostPort fails on it and the span's peer
 // address attributes fall back to the raw, connection-specific ConnectionID.
-func peerInfo(evt *event.CommandStartedEvent) (hostname string, port int) {
+func PeerInfo(connectionID string) (hostname string, port int) {
 	port = 27017 // Default MongoDB port
 
-	host, _, _ := strings.Cut(evt.ConnectionID, "[-")
+	host, _, _ := strings.Cut(connectionID, "[-")
 	hostname, portStr, err := net.SplitHostPort(host)
 	if err != nil {
 		// If there's an error (likely because there's no port), assume default port
@@ -114,15 +132,6 @@ func peerInfo(evt *event.CommandStartedEvent) (hostname string, port int) {
 	return hostname, port
 }
 
-// sanitizeCommand converts a BSON command to a sanitized JSON string.
-// TODO: Sanitize values where possible.
-// TODO: Limit maximum size.
-func sanitizeCommand(command bson.Raw) string {
-	b, _ := bson.MarshalExtJSON(command, false, false)
-
-	return string(b)
-}
-
 // commandStartedTraceAttrs generates trace attributes for the latest semantic
 // version.
 func commandStartedTraceAttrs(evt *event.CommandStartedEvent, setters ...AttributeOption) []attribute.KeyValue {
@@ -140,7 +149,7 @@ func commandStartedTraceAttrs(evt *event.CommandStartedEvent, setters ...Attribu
 		semconv.NetworkTransportTCP,
 	)
 
-	hostname, port := peerInfo(evt)
+	hostname, port := PeerInfo(evt.ConnectionID)
 	attrs = append(
 		attrs,
 		semconv.NetworkPeerPort(port),
@@ -148,7 +157,7 @@ func commandStartedTraceAttrs(evt *event.CommandStartedEvent, setters ...Attribu
 	)
 
 	if !opts.commandAttributeDisabled {
-		attrs = append(attrs, semconv.DBQueryText(sanitizeCommand(evt.Command)))
+		attrs = append(attrs, semconv.DBQueryText(sanitizeCommand(evt.Command, opts.queryTextObfuscated, opts.queryTextMaxLength)))
 	}
 
 	if opts.collectionName != "" {
@@ -175,7 +184,7 @@ func commandStartedTraceAttrsV1210(evt *event.CommandStartedEvent, setters ...At
 		semconv1210.NetTransportTCP,
 	)
 
-	hostname, port := peerInfo(evt)
+	hostname, port := PeerInfo(evt.ConnectionID)
 	attrs = append(
 		attrs,
 		semconv1210.NetPeerPort(port),
@@ -183,7 +192,7 @@ func commandStartedTraceAttrsV1210(evt *event.CommandStartedEvent, setters ...At
 	)
 
 	if !opts.commandAttributeDisabled {
-		attrs = append(attrs, semconv1210.DBStatement(sanitizeCommand(evt.Command)))
+		attrs = append(attrs, semconv1210.DBStatement(sanitizeCommand(evt.Command, opts.queryTextObfuscated, opts.queryTextMaxLength)))
 	}
 
 	if opts.collectionName != "" {
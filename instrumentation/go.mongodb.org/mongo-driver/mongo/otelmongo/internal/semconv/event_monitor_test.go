@@ -45,7 +45,7 @@ func TestNewEventMonitor(t *testing.T) {
 }
 
 func TestPeerInfo(t *testing.T) {
-	// Test cases for peerInfo
+	// Test cases for PeerInfo
 	tests := []struct {
 		name         string
 		connectionID string
@@ -64,8 +64,7 @@ func TestPeerInfo(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			evt := &event.CommandStartedEvent{ConnectionID: tt.connectionID}
-			hostname, port := peerInfo(evt)
+			hostname, port := PeerInfo(tt.connectionID)
 			assert.Equal(t, tt.wantHostname, hostname, "Hostname does not match")
 			assert.Equal(t, tt.wantPort, port, "Port does not match")
 		})
@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package semconv
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// sanitizeCommand renders command as relaxed MongoDB Extended JSON, or with
+// all literal values replaced by ? if obfuscate is true, and truncates the
+// result to maxLen bytes if maxLen is positive.
+func sanitizeCommand(command bson.Raw, obfuscate bool, maxLen int) string {
+	var s string
+	if obfuscate {
+		var sb strings.Builder
+		obfuscateDocument(&sb, command, true)
+		s = sb.String()
+	} else {
+		b, _ := bson.MarshalExtJSON(command, false, false)
+		s = string(b)
+	}
+	return truncate(s, maxLen)
+}
+
+// obfuscateDocument writes doc to sb with all literal values replaced by ?.
+// If keepCollection is true, the string value of the first element, which is
+// the collection the command operates on, is preserved.
+func obfuscateDocument(sb *strings.Builder, doc bson.Raw, keepCollection bool) {
+	elems, err := doc.Elements()
+	if err != nil {
+		sb.WriteString("?")
+		return
+	}
+
+	sb.WriteByte('{')
+	for i, elem := range elems {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		writeJSONString(sb, elem.Key())
+		sb.WriteByte(':')
+
+		v := elem.Value()
+		if s, ok := v.StringValueOK(); ok && i == 0 && keepCollection {
+			writeJSONString(sb, s)
+			continue
+		}
+		obfuscateValue(sb, v)
+	}
+	sb.WriteByte('}')
+}
+
+func obfuscateValue(sb *strings.Builder, v bson.RawValue) {
+	switch v.Type {
+	case bson.TypeEmbeddedDocument:
+		obfuscateDocument(sb, v.Document(), false)
+	case bson.TypeArray:
+		values, err := v.Array().Values()
+		if err != nil || !hasNested(values) {
+			sb.WriteString("[?]")
+			return
+		}
+		sb.WriteByte('[')
+		for i, elem := range values {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			obfuscateValue(sb, elem)
+		}
+		sb.WriteByte(']')
+	default:
+		sb.WriteString("?")
+	}
+}
+
+// hasNested reports whether values contain a document or an array.
+func hasNested(values []bson.RawValue) bool {
+	for _, v := range values {
+		if v.Type == bson.TypeEmbeddedDocument || v.Type == bson.TypeArray {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSONString(sb *strings.Builder, s string) {
+	b, _ := json.Marshal(s)
+	sb.Write(b)
+}
+
+// truncate returns s truncated to at most maxLen bytes without splitting a
+// UTF-8 encoded rune. s is returned unchanged if maxLen is not positive.
+func truncate(s string, maxLen int) string {
+	if maxLen <= 0 || len(s) <= maxLen {
+		return s
+	}
+	for i := maxLen; i > 0 && i > maxLen-utf8.UTFMax; i-- {
+		if utf8.RuneStart(s[i]) {
+			return s[:i]
+		}
+	}
+	return s[:maxLen]
+}
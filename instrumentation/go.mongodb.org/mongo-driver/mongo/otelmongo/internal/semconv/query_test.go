@@ -0,0 +1,97 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package semconv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSanitizeCommand(t *testing.T) {
+	tests := []struct {
+		name      string
+		command   bson.D
+		obfuscate bool
+		maxLen    int
+		want      string
+	}{
+		{
+			name:      "raw",
+			command:   bson.D{{Key: "find", Value: "users"}, {Key: "filter", Value: bson.D{{Key: "name", Value: "alice"}}}},
+			obfuscate: false,
+			want:      `{"find":"users","filter":{"name":"alice"}}`,
+		},
+		{
+			name: "obfuscated find",
+			command: bson.D{
+				{Key: "find", Value: "users"},
+				{Key: "filter", Value: bson.D{
+					{Key: "age", Value: bson.D{{Key: "$gt", Value: 21}}},
+					{Key: "status", Value: bson.D{{Key: "$in", Value: bson.A{"active", "pending"}}}},
+				}},
+				{Key: "limit", Value: 10},
+				{Key: "$db", Value: "test"},
+			},
+			obfuscate: true,
+			want:      `{"find":"users","filter":{"age":{"$gt":?},"status":{"$in":[?]}},"limit":?,"$db":?}`,
+		},
+		{
+			name: "obfuscated update",
+			command: bson.D{
+				{Key: "update", Value: "users"},
+				{Key: "updates", Value: bson.A{
+					bson.D{
+						{Key: "q", Value: bson.D{{Key: "_id", Value: 1}}},
+						{Key: "u", Value: bson.D{{Key: "$set", Value: bson.D{{Key: "name", Value: "bob"}}}}},
+					},
+				}},
+			},
+			obfuscate: true,
+			want:      `{"update":"users","updates":[{"q":{"_id":?},"u":{"$set":{"name":?}}}]}`,
+		},
+		{
+			name: "obfuscated pipeline",
+			command: bson.D{
+				{Key: "aggregate", Value: "orders"},
+				{Key: "pipeline", Value: bson.A{
+					bson.D{{Key: "$match", Value: bson.D{{Key: "total", Value: bson.D{{Key: "$gte", Value: 100}}}}}},
+					bson.D{{Key: "$group", Value: bson.D{{Key: "_id", Value: "$customer"}, {Key: "n", Value: bson.D{{Key: "$sum", Value: 1}}}}}},
+				}},
+			},
+			obfuscate: true,
+			want:      `{"aggregate":"orders","pipeline":[{"$match":{"total":{"$gte":?}}},{"$group":{"_id":?,"n":{"$sum":?}}}]}`,
+		},
+		{
+			name:      "obfuscated without collection",
+			command:   bson.D{{Key: "ping", Value: 1}},
+			obfuscate: true,
+			want:      `{"ping":?}`,
+		},
+		{
+			name:      "truncated",
+			command:   bson.D{{Key: "find", Value: "users"}, {Key: "filter", Value: bson.D{{Key: "name", Value: "alice"}}}},
+			obfuscate: false,
+			maxLen:    16,
+			want:      `{"find":"users",`,
+		},
+		{
+			name:      "truncated at rune boundary",
+			command:   bson.D{{Key: "find", Value: "ñ"}},
+			obfuscate: false,
+			maxLen:    10,
+			want:      `{"find":"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := bson.Marshal(tt.command)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, sanitizeCommand(raw, tt.obfuscate, tt.maxLen))
+		})
+	}
+}
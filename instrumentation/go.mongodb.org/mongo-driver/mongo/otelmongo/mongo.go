@@ -32,6 +32,8 @@ type monitor struct {
 func (m *monitor) Started(ctx context.Context, evt *event.CommandStartedEvent) {
 	attrOptions := []semconv.AttributeOption{
 		semconv.WithCommandAttributeDisabled(m.cfg.CommandAttributeDisabled),
+		semconv.WithQueryTextObfuscated(m.cfg.QueryTextMode == QueryTextObfuscated),
+		semconv.WithQueryTextMaxLength(m.cfg.QueryTextMaxLength),
 	}
 
 	var spanName string
@@ -0,0 +1,21 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelmongo
+
+// QueryTextMode controls how a MongoDB command is rendered in the
+// db.query.text attribute.
+type QueryTextMode int
+
+const (
+	// QueryTextRaw renders the command as relaxed MongoDB Extended JSON,
+	// including all literal values it contains.
+	QueryTextRaw QueryTextMode = iota
+
+	// QueryTextObfuscated renders the command with every literal value
+	// replaced by ?, preserving the field names, operators, and the nesting
+	// of documents. Arrays of literal values are collapsed into a single ?,
+	// so that commands only differing in their values render identically.
+	// The collection the command operates on is not obfuscated.
+	QueryTextObfuscated
+)
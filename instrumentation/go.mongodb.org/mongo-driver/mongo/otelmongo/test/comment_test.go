@@ -0,0 +1,92 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
+)
+
+type commandRecorder struct {
+	sync.Mutex
+	comments map[string]bson.RawValue
+}
+
+func (r *commandRecorder) started(_ context.Context, evt *event.CommandStartedEvent) {
+	r.Lock()
+	defer r.Unlock()
+	r.comments[evt.CommandName] = evt.Command.Lookup("comment")
+}
+
+func TestCollectionComment(t *testing.T) {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x0a, 0xf7, 0x65, 0x19, 0x16, 0xcd, 0x43, 0xdd, 0x84, 0x48, 0xeb, 0x21, 0x1c, 0x80, 0x31, 0x9c},
+		SpanID:     trace.SpanID{0xb7, 0xad, 0x6b, 0x71, 0x69, 0x20, 0x33, 0x31},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ts, err := trace.ParseTraceState("vendor=a b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc = sc.WithTraceState(ts)
+	const want = "traceparent='00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01',tracestate='vendor%3Da%20b'"
+
+	cursorResponse := bson.D{
+		{Key: "ok", Value: 1},
+		{Key: "cursor", Value: bson.D{
+			{Key: "id", Value: int64(0)},
+			{Key: "ns", Value: "test-database.test-collection"},
+			{Key: "firstBatch", Value: bson.A{}},
+		}},
+	}
+	updateResponse := bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "a", Value: 2}}}}
+
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	mt.Run("comment", func(mt *mtest.T) {
+		rec := &commandRecorder{comments: make(map[string]bson.RawValue)}
+		opts := options.Client()
+		opts.Monitor = &event.CommandMonitor{Started: rec.started}
+		opts.ApplyURI("mongodb://localhost:27017/?connect=direct")
+		mt.ResetClient(opts)
+
+		coll := otelmongo.WrapCollection(
+			mt.Client.Database("test-database").Collection("test-collection"),
+			otelmongo.WithPropagators(propagation.TraceContext{}),
+		)
+		ctx := trace.ContextWithSpanContext(t.Context(), sc)
+
+		mt.AddMockResponses(cursorResponse)
+		_, err := coll.Find(ctx, bson.D{{Key: "a", Value: 1}})
+		assert.NoError(mt, err)
+		assert.Equal(mt, want, rec.comments["find"].StringValue())
+
+		mt.AddMockResponses(cursorResponse)
+		_, err = coll.Aggregate(ctx, bson.A{}, options.Aggregate().SetComment("mine"))
+		assert.NoError(mt, err)
+		assert.Equal(mt, "mine", rec.comments["aggregate"].StringValue())
+
+		mt.AddMockResponses(updateResponse)
+		_, err = coll.UpdateMany(ctx, bson.D{}, update)
+		assert.NoError(mt, err)
+		assert.Equal(mt, want, rec.comments["update"].StringValue())
+
+		// Operations without a span context are not commented.
+		mt.AddMockResponses(updateResponse)
+		_, err = coll.UpdateOne(t.Context(), bson.D{}, update)
+		assert.NoError(mt, err)
+		assert.Zero(mt, rec.comments["update"])
+	})
+}
@@ -0,0 +1,149 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo/address"
+	"go.mongodb.org/mongo-driver/mongo/description"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata/metricdatatest"
+
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
+)
+
+func collectMetrics(t *testing.T, reader metric.Reader) map[string]metricdata.Metrics {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(t.Context(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	assert.Equal(t, otelmongo.ScopeName, rm.ScopeMetrics[0].Scope.Name)
+
+	got := make(map[string]metricdata.Metrics)
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		got[m.Name] = m
+	}
+	return got
+}
+
+func TestPoolMonitor(t *testing.T) {
+	reader := metric.NewManualReader()
+	provider := metric.NewMeterProvider(metric.WithReader(reader))
+	monitor := otelmongo.NewPoolMonitor(otelmongo.WithMeterProvider(provider))
+
+	const addr = "localhost:27017"
+	for _, evt := range []*event.PoolEvent{
+		{Type: event.PoolCreated, Address: addr},
+		{Type: event.ConnectionCreated, Address: addr, ConnectionID: 1},
+		{Type: event.ConnectionReady, Address: addr, ConnectionID: 1},
+		{Type: event.ConnectionCreated, Address: addr, ConnectionID: 2},
+		{Type: event.ConnectionReady, Address: addr, ConnectionID: 2},
+		{Type: event.GetStarted, Address: addr},
+		{Type: event.GetSucceeded, Address: addr, ConnectionID: 1, Duration: time.Millisecond},
+		{Type: event.GetStarted, Address: addr},
+		{Type: event.GetSucceeded, Address: addr, ConnectionID: 2, Duration: time.Millisecond},
+		{Type: event.ConnectionReturned, Address: addr, ConnectionID: 2},
+		{Type: event.ConnectionClosed, Address: addr, ConnectionID: 2, Reason: event.ReasonIdle},
+		{Type: event.GetStarted, Address: addr},
+		{Type: event.GetFailed, Address: addr, Reason: event.ReasonTimedOut, Duration: time.Second},
+		{Type: event.GetStarted, Address: addr},
+	} {
+		monitor.Event(evt)
+	}
+
+	got := collectMetrics(t, reader)
+	pool := attribute.String("db.client.connection.pool.name", addr)
+
+	metricdatatest.AssertAggregationsEqual(t, metricdata.Sum[int64]{
+		Temporality: metricdata.CumulativeTemporality,
+		DataPoints: []metricdata.DataPoint[int64]{
+			{Attributes: attribute.NewSet(pool, attribute.String("db.client.connection.state", "idle")), Value: 0},
+			{Attributes: attribute.NewSet(pool, attribute.String("db.client.connection.state", "used")), Value: 1},
+		},
+	}, got["db.client.connection.count"].Data, metricdatatest.IgnoreTimestamp())
+
+	metricdatatest.AssertAggregationsEqual(t, metricdata.Sum[int64]{
+		Temporality: metricdata.CumulativeTemporality,
+		DataPoints:  []metricdata.DataPoint[int64]{{Attributes: attribute.NewSet(pool), Value: 1}},
+	}, got["db.client.connection.pending_requests"].Data, metricdatatest.IgnoreTimestamp())
+
+	metricdatatest.AssertAggregationsEqual(t, metricdata.Sum[int64]{
+		Temporality: metricdata.CumulativeTemporality,
+		IsMonotonic: true,
+		DataPoints:  []metricdata.DataPoint[int64]{{Attributes: attribute.NewSet(pool), Value: 1}},
+	}, got["db.client.connection.timeouts"].Data, metricdatatest.IgnoreTimestamp())
+
+	waitTime, ok := got["db.client.connection.wait_time"].Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, waitTime.DataPoints, 1)
+	assert.Equal(t, uint64(3), waitTime.DataPoints[0].Count)
+}
+
+func TestServerMonitor(t *testing.T) {
+	reader := metric.NewManualReader()
+	provider := metric.NewMeterProvider(metric.WithReader(reader))
+	monitor := otelmongo.NewServerMonitor(otelmongo.WithMeterProvider(provider))
+
+	monitor.ServerHeartbeatSucceeded(&event.ServerHeartbeatSucceededEvent{
+		ConnectionID: "db1:27018[-3]",
+		Duration:     time.Millisecond,
+	})
+	monitor.ServerHeartbeatFailed(&event.ServerHeartbeatFailedEvent{
+		ConnectionID: "db1:27018[-3]",
+		Duration:     time.Second,
+		Failure:      errors.New("connection refused"),
+	})
+	monitor.ServerDescriptionChanged(&event.ServerDescriptionChangedEvent{
+		Address:        address.Address("db1:27018"),
+		NewDescription: description.Server{Kind: description.RSPrimary},
+	})
+	monitor.TopologyDescriptionChanged(&event.TopologyDescriptionChangedEvent{
+		NewDescription: description.Topology{Kind: description.ReplicaSetWithPrimary},
+	})
+
+	got := collectMetrics(t, reader)
+	server := []attribute.KeyValue{
+		attribute.String("server.address", "db1"),
+		attribute.Int("server.port", 27018),
+	}
+
+	heartbeat, ok := got["mongodb.client.heartbeat.duration"].Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, heartbeat.DataPoints, 2)
+	var sets []attribute.Set
+	for _, dp := range heartbeat.DataPoints {
+		sets = append(sets, dp.Attributes)
+	}
+	assert.ElementsMatch(t, []attribute.Set{
+		attribute.NewSet(server...),
+		attribute.NewSet(append(server, attribute.String("error.type", "*errors.errorString"))...),
+	}, sets)
+
+	metricdatatest.AssertAggregationsEqual(t, metricdata.Sum[int64]{
+		Temporality: metricdata.CumulativeTemporality,
+		IsMonotonic: true,
+		DataPoints: []metricdata.DataPoint[int64]{{
+			Attributes: attribute.NewSet(append(server, attribute.String("mongodb.server.type", "RSPrimary"))...),
+			Value:      1,
+		}},
+	}, got["mongodb.client.server.changes"].Data, metricdatatest.IgnoreTimestamp())
+
+	metricdatatest.AssertAggregationsEqual(t, metricdata.Sum[int64]{
+		Temporality: metricdata.CumulativeTemporality,
+		IsMonotonic: true,
+		DataPoints: []metricdata.DataPoint[int64]{{
+			Attributes: attribute.NewSet(attribute.String("mongodb.topology.type", "ReplicaSetWithPrimary")),
+			Value:      1,
+		}},
+	}, got["mongodb.client.topology.changes"].Data, metricdatatest.IgnoreTimestamp())
+}
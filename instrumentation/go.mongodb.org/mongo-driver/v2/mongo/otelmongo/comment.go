@@ -0,0 +1,162 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelmongo
+
+import (
+	"context"
+	"net/url"
+	"slices"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Collection wraps a [mongo.Collection] to propagate the trace context of the
+// operations it performs to the MongoDB server as the comment of the
+// commands they send. The comment is recorded in the server slow query log
+// and in system.profile, which allows correlating them with the originating
+// trace.
+//
+// The trace context is injected with the configured propagators and
+// formatted like a sqlcommenter comment, e.g.
+//
+//	traceparent='00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01'
+//
+// Find, FindOne, Aggregate, UpdateOne, UpdateMany and UpdateByID are
+// commented. A comment set by the caller is never overwritten. All other
+// methods are those of the wrapped [mongo.Collection].
+type Collection struct {
+	*mongo.Collection
+
+	propagators propagation.TextMapPropagator
+}
+
+// WrapCollection returns a Collection wrapping coll. Only the WithPropagators
+// option is used, all others are ignored.
+func WrapCollection(coll *mongo.Collection, opts ...Option) *Collection {
+	cfg := newConfig(opts...)
+	return &Collection{Collection: coll, propagators: cfg.Propagators}
+}
+
+// Find executes a find command with the trace context of ctx as comment.
+// See [mongo.Collection.Find].
+func (c *Collection) Find(ctx context.Context, filter any, opts ...options.Lister[options.FindOptions]) (*mongo.Cursor, error) {
+	opts = withComment(opts, c.comment(ctx), func(o *options.FindOptions, comment string) {
+		if o.Comment == nil {
+			o.Comment = comment
+		}
+	})
+	return c.Collection.Find(ctx, filter, opts...)
+}
+
+// FindOne executes a find command with the trace context of ctx as comment.
+// See [mongo.Collection.FindOne].
+func (c *Collection) FindOne(ctx context.Context, filter any, opts ...options.Lister[options.FindOneOptions]) *mongo.SingleResult {
+	opts = withComment(opts, c.comment(ctx), func(o *options.FindOneOptions, comment string) {
+		if o.Comment == nil {
+			o.Comment = comment
+		}
+	})
+	return c.Collection.FindOne(ctx, filter, opts...)
+}
+
+// Aggregate executes an aggregate command with the trace context of ctx as
+// comment. See [mongo.Collection.Aggregate].
+func (c *Collection) Aggregate(ctx context.Context, pipeline any, opts ...options.Lister[options.AggregateOptions]) (*mongo.Cursor, error) {
+	opts = withComment(opts, c.comment(ctx), func(o *options.AggregateOptions, comment string) {
+		if o.Comment == nil {
+			o.Comment = comment
+		}
+	})
+	return c.Collection.Aggregate(ctx, pipeline, opts...)
+}
+
+// UpdateOne executes an update command with the trace context of ctx as
+// comment. See [mongo.Collection.UpdateOne].
+func (c *Collection) UpdateOne(ctx context.Context, filter, update any, opts ...options.Lister[options.UpdateOneOptions]) (*mongo.UpdateResult, error) {
+	opts = withComment(opts, c.comment(ctx), func(o *options.UpdateOneOptions, comment string) {
+		if o.Comment == nil {
+			o.Comment = comment
+		}
+	})
+	return c.Collection.UpdateOne(ctx, filter, update, opts...)
+}
+
+// UpdateByID executes an update command with the trace context of ctx as
+// comment. See [mongo.Collection.UpdateByID].
+func (c *Collection) UpdateByID(ctx context.Context, id, update any, opts ...options.Lister[options.UpdateOneOptions]) (*mongo.UpdateResult, error) {
+	opts = withComment(opts, c.comment(ctx), func(o *options.UpdateOneOptions, comment string) {
+		if o.Comment == nil {
+			o.Comment = comment
+		}
+	})
+	return c.Collection.UpdateByID(ctx, id, update, opts...)
+}
+
+// UpdateMany executes an update command with the trace context of ctx as
+// comment. See [mongo.Collection.UpdateMany].
+func (c *Collection) UpdateMany(ctx context.Context, filter, update any, opts ...options.Lister[options.UpdateManyOptions]) (*mongo.UpdateResult, error) {
+	opts = withComment(opts, c.comment(ctx), func(o *options.UpdateManyOptions, comment string) {
+		if o.Comment == nil {
+			o.Comment = comment
+		}
+	})
+	return c.Collection.UpdateMany(ctx, filter, update, opts...)
+}
+
+func (c *Collection) comment(ctx context.Context) string {
+	return traceComment(ctx, c.propagators)
+}
+
+// listerFunc is an [options.Lister] applying a single function.
+type listerFunc[T any] func(*T) error
+
+func (f listerFunc[T]) List() []func(*T) error {
+	return []func(*T) error{f}
+}
+
+// withComment returns opts with an additional lister calling set with
+// comment. The lister is applied last, so set can tell whether the caller
+// already set a comment. opts is returned unchanged if comment is empty.
+func withComment[T any](opts []options.Lister[T], comment string, set func(*T, string)) []options.Lister[T] {
+	if comment == "" {
+		return opts
+	}
+	return append(slices.Clip(opts), listerFunc[T](func(o *T) error {
+		set(o, comment)
+		return nil
+	}))
+}
+
+// traceComment returns the fields injected by propagators for ctx formatted
+// as a sqlcommenter comment: URL-encoded key='value' pairs sorted by key and
+// separated by commas. It returns an empty string if nothing was injected.
+func traceComment(ctx context.Context, propagators propagation.TextMapPropagator) string {
+	carrier := propagation.MapCarrier{}
+	propagators.Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return ""
+	}
+
+	keys := carrier.Keys()
+	slices.Sort(keys)
+
+	var sb strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(commentEscape(k))
+		sb.WriteString("='")
+		sb.WriteString(commentEscape(carrier[k]))
+		sb.WriteByte('\'')
+	}
+	return sb.String()
+}
+
+func commentEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
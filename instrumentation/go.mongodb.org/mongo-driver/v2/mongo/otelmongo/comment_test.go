@@ -0,0 +1,107 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelmongo
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/drivertest"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var commentSpanContext = trace.NewSpanContext(trace.SpanContextConfig{
+	TraceID:    trace.TraceID{0x0a, 0xf7, 0x65, 0x19, 0x16, 0xcd, 0x43, 0xdd, 0x84, 0x48, 0xeb, 0x21, 0x1c, 0x80, 0x31, 0x9c},
+	SpanID:     trace.SpanID{0xb7, 0xad, 0x6b, 0x71, 0x69, 0x20, 0x33, 0x31},
+	TraceFlags: trace.FlagsSampled,
+})
+
+const wantComment = "traceparent='00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01'"
+
+func TestTraceComment(t *testing.T) {
+	prop := propagation.TraceContext{}
+
+	assert.Empty(t, traceComment(t.Context(), prop))
+
+	ctx := trace.ContextWithSpanContext(t.Context(), commentSpanContext)
+	assert.Equal(t, wantComment, traceComment(ctx, prop))
+
+	ts, err := trace.ParseTraceState("vendor=a b,other=c")
+	require.NoError(t, err)
+	ctx = trace.ContextWithSpanContext(t.Context(), commentSpanContext.WithTraceState(ts))
+	assert.Equal(t, wantComment+",tracestate='vendor%3Da%20b%2Cother%3Dc'", traceComment(ctx, prop))
+}
+
+type commandRecorder struct {
+	sync.Mutex
+	comments map[string]bson.RawValue
+}
+
+func (r *commandRecorder) started(_ context.Context, evt *event.CommandStartedEvent) {
+	r.Lock()
+	defer r.Unlock()
+	r.comments[evt.CommandName] = evt.Command.Lookup("comment")
+}
+
+func TestCollectionComment(t *testing.T) {
+	md := drivertest.NewMockDeployment()
+	rec := &commandRecorder{comments: make(map[string]bson.RawValue)}
+
+	opts := options.Client()
+	opts.Deployment = md //nolint:staticcheck  // This method is the current documented way to set the mongodb mock. See https://github.com/mongodb/mongo-go-driver/blob/v2.0.0/x/mongo/driver/drivertest/opmsg_deployment_test.go#L24
+	opts.Monitor = &event.CommandMonitor{Started: rec.started}
+	opts.ApplyURI(testAddr)
+
+	client, err := mongo.Connect(opts)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, client.Disconnect(t.Context()))
+	}()
+
+	coll := WrapCollection(
+		client.Database("test-database").Collection("test-collection"),
+		WithPropagators(propagation.TraceContext{}),
+	)
+	ctx := trace.ContextWithSpanContext(t.Context(), commentSpanContext)
+
+	cursorResponse := bson.D{
+		{Key: "ok", Value: 1},
+		{Key: "cursor", Value: bson.D{
+			{Key: "id", Value: int64(0)},
+			{Key: "ns", Value: "test-database.test-collection"},
+			{Key: "firstBatch", Value: bson.A{}},
+		}},
+	}
+	updateResponse := bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}}
+
+	md.AddResponses(cursorResponse)
+	_, err = coll.Find(ctx, bson.D{{Key: "a", Value: 1}})
+	require.NoError(t, err)
+
+	md.AddResponses(cursorResponse)
+	_, err = coll.Aggregate(ctx, bson.A{}, options.Aggregate().SetComment("mine"))
+	require.NoError(t, err)
+
+	md.AddResponses(updateResponse)
+	_, err = coll.UpdateMany(ctx, bson.D{}, bson.D{{Key: "$set", Value: bson.D{{Key: "a", Value: 2}}}})
+	require.NoError(t, err)
+	assert.Equal(t, wantComment, rec.comments["update"].StringValue())
+
+	md.AddResponses(updateResponse)
+	_, err = coll.UpdateOne(t.Context(), bson.D{}, bson.D{{Key: "$set", Value: bson.D{{Key: "a", Value: 2}}}})
+	require.NoError(t, err)
+
+	assert.Equal(t, wantComment, rec.comments["find"].StringValue())
+	assert.Equal(t, "mine", rec.comments["aggregate"].StringValue())
+	// The last update was sent without a span context and is not commented.
+	assert.Zero(t, rec.comments["update"])
+}
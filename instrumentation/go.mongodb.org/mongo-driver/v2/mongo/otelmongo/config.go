@@ -7,6 +7,7 @@ import (
 	"go.mongodb.org/mongo-driver/v2/event"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -17,11 +18,14 @@ const ScopeName = "go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mo
 type config struct {
 	MeterProvider  metric.MeterProvider
 	TracerProvider trace.TracerProvider
+	Propagators    propagation.TextMapPropagator
 
 	Meter  metric.Meter
 	Tracer trace.Tracer
 
 	CommandAttributeDisabled bool
+	QueryTextMode            QueryTextMode
+	QueryTextMaxLength       int
 
 	SpanNameFormatter SpanNameFormatterFunc
 }
@@ -31,6 +35,7 @@ func newConfig(opts ...Option) config {
 	cfg := config{
 		MeterProvider:            otel.GetMeterProvider(),
 		TracerProvider:           otel.GetTracerProvider(),
+		Propagators:              otel.GetTextMapPropagator(),
 		CommandAttributeDisabled: true,
 	}
 
@@ -106,6 +111,17 @@ func WithTracerProvider(provider trace.TracerProvider) Option {
 	})
 }
 
+// WithPropagators specifies the propagators used by a Collection to inject
+// the trace context into command comments. If none is specified, the global
+// TextMapPropagator is used.
+func WithPropagators(propagators propagation.TextMapPropagator) Option {
+	return optionFunc(func(cfg *config) {
+		if propagators != nil {
+			cfg.Propagators = propagators
+		}
+	})
+}
+
 // WithCommandAttributeDisabled specifies if the MongoDB command is added as an attribute to Spans or not.
 // This is disabled by default and the MongoDB command will not be added as an attribute
 // to Spans if this option is not provided.
@@ -114,3 +130,22 @@ func WithCommandAttributeDisabled(disabled bool) Option {
 		cfg.CommandAttributeDisabled = disabled
 	})
 }
+
+// WithQueryTextMode specifies how the MongoDB command is rendered in the
+// db.query.text attribute when it is enabled with
+// WithCommandAttributeDisabled(false). If none is specified, QueryTextRaw is
+// used.
+func WithQueryTextMode(mode QueryTextMode) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.QueryTextMode = mode
+	})
+}
+
+// WithQueryTextMaxLength specifies the maximum length in bytes of the
+// db.query.text attribute. Longer values are truncated. If none is specified,
+// or maxLength is not positive, the attribute is not truncated.
+func WithQueryTextMaxLength(maxLength int) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.QueryTextMaxLength = maxLength
+	})
+}
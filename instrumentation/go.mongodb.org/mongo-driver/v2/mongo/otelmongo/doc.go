@@ -4,7 +4,12 @@
 // Package otelmongo instruments go.mongodb.org/mongo-driver/v2/mongo.
 //
 // `NewMonitor` will return an event.CommandMonitor which is used to trace
-// requests and collect its metrics.
+// requests and collect its metrics. `NewPoolMonitor` and `NewServerMonitor`
+// return an event.PoolMonitor and an event.ServerMonitor which are used to
+// collect connection pool and server monitoring metrics.
+//
+// `WrapCollection` wraps a mongo.Collection to inject the trace context into
+// the comment of the commands it sends.
 //
 // This code was originally based on the following:
 // - https://github.com/open-telemetry/opentelemetry-go-contrib/tree/323e373a6c15ae310bdd0617e3ed52d8cb8e4e6f/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo
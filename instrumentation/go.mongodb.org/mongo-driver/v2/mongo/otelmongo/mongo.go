@@ -47,7 +47,7 @@ func (m *monitor) Started(ctx context.Context, evt *event.CommandStartedEvent) {
 		semconv.NetworkTransportTCP,
 	}
 	if !m.cfg.CommandAttributeDisabled {
-		attrs = append(attrs, semconv.DBQueryText(sanitizeCommand(evt.Command)))
+		attrs = append(attrs, semconv.DBQueryText(sanitizeCommand(evt.Command, m.cfg.QueryTextMode, m.cfg.QueryTextMaxLength)))
 	}
 
 	collection, err := extractCollection(evt)
@@ -155,13 +155,6 @@ func (m *monitor) Finished(evt *event.CommandFinishedEvent, err error) {
 	span.End()
 }
 
-// TODO sanitize values where possible, then re-enable `db.statement` span attributes default.
-// TODO limit maximum size.
-func sanitizeCommand(command bson.Raw) string {
-	b, _ := bson.MarshalExtJSON(command, false, false)
-	return string(b)
-}
-
 // extractCollection extracts the collection for the given mongodb command event.
 // For CRUD operations, this is the first key/value string pair in the bson
 // document where key == "<operation>" (e.g. key == "insert").
@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelmongo
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/semconv/v1.43.0/dbconv"
+)
+
+type connKey struct {
+	Address      string
+	ConnectionID int64
+}
+
+type poolMonitor struct {
+	ConnectionCount   dbconv.ClientConnectionCount
+	PendingRequests   dbconv.ClientConnectionPendingRequests
+	WaitTime          dbconv.ClientConnectionWaitTime
+	ConnectionTimeout dbconv.ClientConnectionTimeouts
+
+	sync.Mutex
+	// conns holds the state of the connections counted in ConnectionCount.
+	conns map[connKey]dbconv.ClientConnectionStateAttr
+}
+
+// Event handles a connection pool event. The address of the pool is used as
+// the db.client.connection.pool.name attribute.
+func (m *poolMonitor) Event(evt *event.PoolEvent) {
+	ctx := context.Background()
+	pool := evt.Address
+	key := connKey{Address: evt.Address, ConnectionID: evt.ConnectionID}
+
+	switch evt.Type {
+	case event.ConnectionReady:
+		m.setState(ctx, key, dbconv.ClientConnectionStateIdle)
+	case event.ConnectionCheckedOut:
+		m.PendingRequests.Add(ctx, -1, pool)
+		m.WaitTime.Record(ctx, evt.Duration.Seconds(), pool)
+		m.setState(ctx, key, dbconv.ClientConnectionStateUsed)
+	case event.ConnectionCheckedIn:
+		m.setState(ctx, key, dbconv.ClientConnectionStateIdle)
+	case event.ConnectionClosed:
+		m.setState(ctx, key, "")
+	case event.ConnectionCheckOutStarted:
+		m.PendingRequests.Add(ctx, 1, pool)
+	case event.ConnectionCheckOutFailed:
+		m.PendingRequests.Add(ctx, -1, pool)
+		m.WaitTime.Record(ctx, evt.Duration.Seconds(), pool)
+		if evt.Reason == event.ReasonTimedOut {
+			m.ConnectionTimeout.Add(ctx, 1, pool)
+		}
+	}
+}
+
+// setState moves the connection identified by key to state, updating the
+// connection count of both its previous and new state. An empty state
+// removes the connection.
+func (m *poolMonitor) setState(ctx context.Context, key connKey, state dbconv.ClientConnectionStateAttr) {
+	m.Lock()
+	prev, ok := m.conns[key]
+	if state == "" {
+		delete(m.conns, key)
+	} else {
+		m.conns[key] = state
+	}
+	m.Unlock()
+
+	if ok && prev == state {
+		return
+	}
+	if ok {
+		m.ConnectionCount.Add(ctx, -1, key.Address, prev)
+	}
+	if state != "" {
+		m.ConnectionCount.Add(ctx, 1, key.Address, state)
+	}
+}
+
+// NewPoolMonitor creates a new mongodb event PoolMonitor recording the
+// db.client.connection.count, db.client.connection.pending_requests,
+// db.client.connection.wait_time and db.client.connection.timeouts metrics.
+// The address of each server the client connects to is used as the name of
+// its connection pool.
+func NewPoolMonitor(opts ...Option) *event.PoolMonitor {
+	cfg := newConfig(opts...)
+
+	m := &poolMonitor{conns: make(map[connKey]dbconv.ClientConnectionStateAttr)}
+	var err error
+	if m.ConnectionCount, err = dbconv.NewClientConnectionCount(cfg.Meter); err != nil {
+		otel.Handle(err)
+	}
+	if m.PendingRequests, err = dbconv.NewClientConnectionPendingRequests(cfg.Meter); err != nil {
+		otel.Handle(err)
+	}
+	if m.WaitTime, err = dbconv.NewClientConnectionWaitTime(cfg.Meter); err != nil {
+		otel.Handle(err)
+	}
+	if m.ConnectionTimeout, err = dbconv.NewClientConnectionTimeouts(cfg.Meter); err != nil {
+		otel.Handle(err)
+	}
+
+	return &event.PoolMonitor{Event: m.Event}
+}
@@ -0,0 +1,146 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelmongo
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.mongodb.org/mongo-driver/v2/mongo/address"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata/metricdatatest"
+)
+
+func collectMetrics(t *testing.T, reader metric.Reader) map[string]metricdata.Metrics {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(t.Context(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	assert.Equal(t, ScopeName, rm.ScopeMetrics[0].Scope.Name)
+
+	got := make(map[string]metricdata.Metrics)
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		got[m.Name] = m
+	}
+	return got
+}
+
+func TestPoolMonitor(t *testing.T) {
+	reader := metric.NewManualReader()
+	provider := metric.NewMeterProvider(metric.WithReader(reader))
+	monitor := NewPoolMonitor(WithMeterProvider(provider))
+
+	const addr = "localhost:27017"
+	for _, evt := range []*event.PoolEvent{
+		{Type: event.ConnectionPoolCreated, Address: addr},
+		{Type: event.ConnectionCreated, Address: addr, ConnectionID: 1},
+		{Type: event.ConnectionReady, Address: addr, ConnectionID: 1},
+		{Type: event.ConnectionCreated, Address: addr, ConnectionID: 2},
+		{Type: event.ConnectionReady, Address: addr, ConnectionID: 2},
+		{Type: event.ConnectionCheckOutStarted, Address: addr},
+		{Type: event.ConnectionCheckedOut, Address: addr, ConnectionID: 1, Duration: time.Millisecond},
+		{Type: event.ConnectionCheckOutStarted, Address: addr},
+		{Type: event.ConnectionCheckedOut, Address: addr, ConnectionID: 2, Duration: time.Millisecond},
+		{Type: event.ConnectionCheckedIn, Address: addr, ConnectionID: 2},
+		{Type: event.ConnectionClosed, Address: addr, ConnectionID: 2, Reason: event.ReasonIdle},
+		{Type: event.ConnectionCheckOutStarted, Address: addr},
+		{Type: event.ConnectionCheckOutFailed, Address: addr, Reason: event.ReasonTimedOut, Duration: time.Second},
+		{Type: event.ConnectionCheckOutStarted, Address: addr},
+	} {
+		monitor.Event(evt)
+	}
+
+	got := collectMetrics(t, reader)
+	pool := attribute.String("db.client.connection.pool.name", addr)
+
+	metricdatatest.AssertAggregationsEqual(t, metricdata.Sum[int64]{
+		Temporality: metricdata.CumulativeTemporality,
+		DataPoints: []metricdata.DataPoint[int64]{
+			{Attributes: attribute.NewSet(pool, attribute.String("db.client.connection.state", "idle")), Value: 0},
+			{Attributes: attribute.NewSet(pool, attribute.String("db.client.connection.state", "used")), Value: 1},
+		},
+	}, got["db.client.connection.count"].Data, metricdatatest.IgnoreTimestamp())
+
+	metricdatatest.AssertAggregationsEqual(t, metricdata.Sum[int64]{
+		Temporality: metricdata.CumulativeTemporality,
+		DataPoints:  []metricdata.DataPoint[int64]{{Attributes: attribute.NewSet(pool), Value: 1}},
+	}, got["db.client.connection.pending_requests"].Data, metricdatatest.IgnoreTimestamp())
+
+	metricdatatest.AssertAggregationsEqual(t, metricdata.Sum[int64]{
+		Temporality: metricdata.CumulativeTemporality,
+		IsMonotonic: true,
+		DataPoints:  []metricdata.DataPoint[int64]{{Attributes: attribute.NewSet(pool), Value: 1}},
+	}, got["db.client.connection.timeouts"].Data, metricdatatest.IgnoreTimestamp())
+
+	waitTime, ok := got["db.client.connection.wait_time"].Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, waitTime.DataPoints, 1)
+	assert.Equal(t, uint64(3), waitTime.DataPoints[0].Count)
+}
+
+func TestServerMonitor(t *testing.T) {
+	reader := metric.NewManualReader()
+	provider := metric.NewMeterProvider(metric.WithReader(reader))
+	monitor := NewServerMonitor(WithMeterProvider(provider))
+
+	monitor.ServerHeartbeatSucceeded(&event.ServerHeartbeatSucceededEvent{
+		ConnectionID: "db1:27018[-3]",
+		Duration:     time.Millisecond,
+	})
+	monitor.ServerHeartbeatFailed(&event.ServerHeartbeatFailedEvent{
+		ConnectionID: "db1:27018[-3]",
+		Duration:     time.Second,
+		Failure:      errors.New("connection refused"),
+	})
+	monitor.ServerDescriptionChanged(&event.ServerDescriptionChangedEvent{
+		Address:        address.Address("db1:27018"),
+		NewDescription: event.ServerDescription{Kind: "RSPrimary"},
+	})
+	monitor.TopologyDescriptionChanged(&event.TopologyDescriptionChangedEvent{
+		NewDescription: event.TopologyDescription{Kind: "ReplicaSetWithPrimary"},
+	})
+
+	got := collectMetrics(t, reader)
+	server := []attribute.KeyValue{
+		attribute.String("server.address", "db1"),
+		attribute.Int("server.port", 27018),
+	}
+
+	heartbeat, ok := got["mongodb.client.heartbeat.duration"].Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, heartbeat.DataPoints, 2)
+	var sets []attribute.Set
+	for _, dp := range heartbeat.DataPoints {
+		sets = append(sets, dp.Attributes)
+	}
+	assert.ElementsMatch(t, []attribute.Set{
+		attribute.NewSet(server...),
+		attribute.NewSet(append(server, attribute.String("error.type", "*errors.errorString"))...),
+	}, sets)
+
+	metricdatatest.AssertAggregationsEqual(t, metricdata.Sum[int64]{
+		Temporality: metricdata.CumulativeTemporality,
+		IsMonotonic: true,
+		DataPoints: []metricdata.DataPoint[int64]{{
+			Attributes: attribute.NewSet(append(server, attribute.String("mongodb.server.type", "RSPrimary"))...),
+			Value:      1,
+		}},
+	}, got["mongodb.client.server.changes"].Data, metricdatatest.IgnoreTimestamp())
+
+	metricdatatest.AssertAggregationsEqual(t, metricdata.Sum[int64]{
+		Temporality: metricdata.CumulativeTemporality,
+		IsMonotonic: true,
+		DataPoints: []metricdata.DataPoint[int64]{{
+			Attributes: attribute.NewSet(attribute.String("mongodb.topology.type", "ReplicaSetWithPrimary")),
+			Value:      1,
+		}},
+	}, got["mongodb.client.topology.changes"].Data, metricdatatest.IgnoreTimestamp())
+}
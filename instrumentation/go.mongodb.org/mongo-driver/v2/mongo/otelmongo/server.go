@@ -0,0 +1,113 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelmongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+)
+
+const (
+	serverTypeKey   = attribute.Key("mongodb.server.type")
+	topologyTypeKey = attribute.Key("mongodb.topology.type")
+)
+
+type serverMonitor struct {
+	HeartbeatDuration metric.Float64Histogram
+	ServerChanges     metric.Int64Counter
+	TopologyChanges   metric.Int64Counter
+}
+
+// HeartbeatSucceeded records the duration of a successful server heartbeat.
+func (m *serverMonitor) HeartbeatSucceeded(evt *event.ServerHeartbeatSucceededEvent) {
+	hostname, port := peerInfo(evt.ConnectionID)
+	m.HeartbeatDuration.Record(context.Background(), evt.Duration.Seconds(), metric.WithAttributes(
+		semconv.ServerAddress(hostname),
+		semconv.ServerPort(port),
+	))
+}
+
+// HeartbeatFailed records the duration of a failed server heartbeat.
+func (m *serverMonitor) HeartbeatFailed(evt *event.ServerHeartbeatFailedEvent) {
+	hostname, port := peerInfo(evt.ConnectionID)
+	m.HeartbeatDuration.Record(context.Background(), evt.Duration.Seconds(), metric.WithAttributes(
+		semconv.ServerAddress(hostname),
+		semconv.ServerPort(port),
+		semconv.ErrorType(evt.Failure),
+	))
+}
+
+// ServerDescriptionChanged counts changes of the description of a server,
+// e.g. a replica set member becoming primary or being marked unknown.
+func (m *serverMonitor) ServerDescriptionChanged(evt *event.ServerDescriptionChangedEvent) {
+	hostname, port := peerInfo(evt.Address.String())
+	m.ServerChanges.Add(context.Background(), 1, metric.WithAttributes(
+		semconv.ServerAddress(hostname),
+		semconv.ServerPort(port),
+		serverTypeKey.String(evt.NewDescription.Kind),
+	))
+}
+
+// TopologyDescriptionChanged counts changes of the description of the
+// deployment.
+func (m *serverMonitor) TopologyDescriptionChanged(evt *event.TopologyDescriptionChangedEvent) {
+	m.TopologyChanges.Add(context.Background(), 1, metric.WithAttributes(
+		topologyTypeKey.String(evt.NewDescription.Kind),
+	))
+}
+
+// NewServerMonitor creates a new mongodb event ServerMonitor recording the
+// following metrics:
+//
+//   - mongodb.client.heartbeat.duration: the duration of the heartbeats the
+//     client sends to each server, with the error.type attribute set for
+//     failed heartbeats.
+//   - mongodb.client.server.changes: the number of server description
+//     changes, with the new server type as the mongodb.server.type attribute.
+//   - mongodb.client.topology.changes: the number of topology description
+//     changes, with the new topology type as the mongodb.topology.type
+//     attribute.
+func NewServerMonitor(opts ...Option) *event.ServerMonitor {
+	cfg := newConfig(opts...)
+
+	m := &serverMonitor{}
+	var err error
+	if m.HeartbeatDuration, err = cfg.Meter.Float64Histogram(
+		"mongodb.client.heartbeat.duration",
+		metric.WithDescription("Duration of the heartbeats sent to MongoDB servers."),
+		metric.WithUnit("s"),
+	); err != nil {
+		otel.Handle(err)
+		m.HeartbeatDuration = noop.Float64Histogram{}
+	}
+	if m.ServerChanges, err = cfg.Meter.Int64Counter(
+		"mongodb.client.server.changes",
+		metric.WithDescription("Number of MongoDB server description changes."),
+		metric.WithUnit("{change}"),
+	); err != nil {
+		otel.Handle(err)
+		m.ServerChanges = noop.Int64Counter{}
+	}
+	if m.TopologyChanges, err = cfg.Meter.Int64Counter(
+		"mongodb.client.topology.changes",
+		metric.WithDescription("Number of MongoDB topology description changes."),
+		metric.WithUnit("{change}"),
+	); err != nil {
+		otel.Handle(err)
+		m.TopologyChanges = noop.Int64Counter{}
+	}
+
+	return &event.ServerMonitor{
+		ServerHeartbeatSucceeded:   m.HeartbeatSucceeded,
+		ServerHeartbeatFailed:      m.HeartbeatFailed,
+		ServerDescriptionChanged:   m.ServerDescriptionChanged,
+		TopologyDescriptionChanged: m.TopologyDescriptionChanged,
+	}
+}
@@ -74,6 +74,8 @@ require (
 	go.opentelemetry.io/auto/sdk v1.2.1 // indirect
 	go.opentelemetry.io/contrib/propagators/aws v1.45.0 // indirect
 	go.opentelemetry.io/contrib/propagators/b3 v1.45.0 // indirect
+	go.opentelemetry.io/contrib/propagators/cloudtrace v0.70.0 // indirect
+	go.opentelemetry.io/contrib/propagators/datadog v0.70.0 // indirect
 	go.opentelemetry.io/contrib/propagators/jaeger v1.45.0 // indirect
 	go.opentelemetry.io/contrib/propagators/ot v1.45.0 // indirect
 	go.opentelemetry.io/otel/exporters/otlp/otlptrace v1.45.0 // indirect
@@ -110,6 +112,10 @@ replace go.opentelemetry.io/contrib/propagators/ot => ../propagators/ot
 
 replace go.opentelemetry.io/contrib/propagators/jaeger => ../propagators/jaeger
 
+replace go.opentelemetry.io/contrib/propagators/datadog => ../propagators/datadog
+
+replace go.opentelemetry.io/contrib/propagators/cloudtrace => ../propagators/cloudtrace
+
 replace go.opentelemetry.io/contrib/detectors/aws/ec2/v2 => ../detectors/aws/ec2/v2
 
 replace go.opentelemetry.io/contrib/detectors/aws/ecs => ../detectors/aws/ecs
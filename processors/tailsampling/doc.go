@@ -0,0 +1,23 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package tailsampling provides an OpenTelemetry [Span Processor] sampling
+// whole traces after their spans ended, in process.
+//
+// The SpanProcessor buffers the ended spans of each trace until the local
+// root span of the trace ends, i.e. the span without a parent or with a
+// remote parent, or until a timeout. The trace is then evaluated by the
+// configured policies, and its spans are forwarded to the wrapped processor,
+// e.g. a batch span processor, if a policy keeps it. This allows keeping all
+// the failed or slow requests of a service without running a collector.
+//
+// Only the sampled spans reach span processors: the tracer provider should
+// sample all the spans, or a larger fraction of them than the policies keep.
+//
+// # Usage
+//
+// Wrap the span processor exporting the spans with the tail sampling
+// processor when configuring the tracer provider.
+//
+// [Span Processor]: https://opentelemetry.io/docs/specs/otel/trace/sdk/#span-processor
+package tailsampling
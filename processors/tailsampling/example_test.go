@@ -0,0 +1,33 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tailsampling_test
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"go.opentelemetry.io/contrib/processors/tailsampling"
+)
+
+func ExampleNewSpanProcessor() {
+	exporter := tracetest.NewInMemoryExporter()
+	trace.NewTracerProvider(
+		// Sample all the spans, the tail sampling processor decides which
+		// traces are exported.
+		trace.WithSampler(trace.AlwaysSample()),
+		trace.WithSpanProcessor(
+			tailsampling.NewSpanProcessor(
+				trace.NewBatchSpanProcessor(exporter),
+				tailsampling.WithPolicies(
+					tailsampling.ErrorPolicy(),
+					tailsampling.LatencyPolicy(500*time.Millisecond),
+					// Keep 1% of the other traces.
+					tailsampling.ProbabilisticPolicy(0.01),
+				),
+			),
+		),
+	)
+}
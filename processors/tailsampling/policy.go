@@ -0,0 +1,97 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tailsampling
+
+import (
+	"encoding/binary"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Policy returns true if the trace of the spans should be kept. The spans are
+// the spans of the trace ended in this process, in the order they ended.
+type Policy func(spans []trace.ReadOnlySpan) bool
+
+// ErrorPolicy keeps the traces with a span with an error status.
+func ErrorPolicy() Policy {
+	return func(spans []trace.ReadOnlySpan) bool {
+		for _, s := range spans {
+			if s.Status().Code == codes.Error {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// LatencyPolicy keeps the traces lasting at least threshold, from the start
+// of their first span to the end of their last span.
+func LatencyPolicy(threshold time.Duration) Policy {
+	return func(spans []trace.ReadOnlySpan) bool {
+		if len(spans) == 0 {
+			return false
+		}
+		start, end := spans[0].StartTime(), spans[0].EndTime()
+		for _, s := range spans[1:] {
+			if s.StartTime().Before(start) {
+				start = s.StartTime()
+			}
+			if s.EndTime().After(end) {
+				end = s.EndTime()
+			}
+		}
+		return end.Sub(start) >= threshold
+	}
+}
+
+// AttributePolicy keeps the traces with a span having the attribute key with
+// one of the values. Any value of the attribute matches if no value is
+// passed.
+func AttributePolicy(key attribute.Key, values ...attribute.Value) Policy {
+	return func(spans []trace.ReadOnlySpan) bool {
+		for _, s := range spans {
+			for _, attr := range s.Attributes() {
+				if attr.Key != key {
+					continue
+				}
+				if len(values) == 0 {
+					return true
+				}
+				for _, v := range values {
+					if attr.Value == v {
+						return true
+					}
+				}
+			}
+		}
+		return false
+	}
+}
+
+// ProbabilisticPolicy keeps a given fraction of the traces. The decision is
+// made from the trace ID, like the trace ID ratio based sampler of the SDK,
+// so it is consistent across services using the same fraction.
+//   - Fractions >= 1 will always keep.
+//   - Fractions <= 0 will never keep.
+//
+// It is typically used as the last policy, to keep a sample of the traces not
+// kept by the other policies.
+func ProbabilisticPolicy(fraction float64) Policy {
+	if fraction >= 1 {
+		return func([]trace.ReadOnlySpan) bool { return true }
+	}
+	fraction = max(fraction, 0)
+	upperBound := uint64(fraction * (1 << 63))
+	return func(spans []trace.ReadOnlySpan) bool {
+		if len(spans) == 0 {
+			return false
+		}
+		traceID := spans[0].SpanContext().TraceID()
+		x := binary.BigEndian.Uint64(traceID[8:16]) >> 1
+		return x < upperBound
+	}
+}
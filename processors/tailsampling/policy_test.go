@@ -0,0 +1,84 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tailsampling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func snapshots(stubs ...tracetest.SpanStub) []trace.ReadOnlySpan {
+	return tracetest.SpanStubs(stubs).Snapshots()
+}
+
+func TestErrorPolicy(t *testing.T) {
+	policy := ErrorPolicy()
+	assert.False(t, policy(nil))
+	assert.False(t, policy(snapshots(
+		tracetest.SpanStub{Status: trace.Status{Code: codes.Ok}},
+		tracetest.SpanStub{},
+	)))
+	assert.True(t, policy(snapshots(
+		tracetest.SpanStub{},
+		tracetest.SpanStub{Status: trace.Status{Code: codes.Error, Description: "failed"}},
+	)))
+}
+
+func TestLatencyPolicy(t *testing.T) {
+	start := time.Unix(1000, 0)
+	policy := LatencyPolicy(time.Second)
+	assert.False(t, policy(nil))
+	assert.False(t, policy(snapshots(
+		tracetest.SpanStub{StartTime: start, EndTime: start.Add(500 * time.Millisecond)},
+	)))
+	assert.True(t, policy(snapshots(
+		tracetest.SpanStub{StartTime: start, EndTime: start.Add(time.Second)},
+	)))
+	// The duration of the trace is from its first start to its last end.
+	assert.True(t, policy(snapshots(
+		tracetest.SpanStub{StartTime: start.Add(200 * time.Millisecond), EndTime: start.Add(900 * time.Millisecond)},
+		tracetest.SpanStub{StartTime: start, EndTime: start.Add(100 * time.Millisecond)},
+		tracetest.SpanStub{StartTime: start.Add(500 * time.Millisecond), EndTime: start.Add(1500 * time.Millisecond)},
+	)))
+}
+
+func TestAttributePolicy(t *testing.T) {
+	spans := snapshots(
+		tracetest.SpanStub{Attributes: []attribute.KeyValue{attribute.String("tenant", "gold")}},
+		tracetest.SpanStub{Attributes: []attribute.KeyValue{attribute.Int("http.response.status_code", 429)}},
+	)
+
+	assert.True(t, AttributePolicy("tenant")(spans))
+	assert.True(t, AttributePolicy("tenant", attribute.StringValue("silver"), attribute.StringValue("gold"))(spans))
+	assert.False(t, AttributePolicy("tenant", attribute.StringValue("silver"))(spans))
+	assert.True(t, AttributePolicy("http.response.status_code", attribute.IntValue(429))(spans))
+	assert.False(t, AttributePolicy("http.response.status_code", attribute.StringValue("429"))(spans))
+	assert.False(t, AttributePolicy("missing")(spans))
+}
+
+func TestProbabilisticPolicy(t *testing.T) {
+	spansOf := func(low byte) []trace.ReadOnlySpan {
+		return snapshots(tracetest.SpanStub{
+			SpanContext: oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+				TraceID: oteltrace.TraceID{8: low},
+			}),
+		})
+	}
+
+	assert.True(t, ProbabilisticPolicy(1)(spansOf(0xff)))
+	assert.False(t, ProbabilisticPolicy(0)(spansOf(0)))
+	assert.False(t, ProbabilisticPolicy(-1)(spansOf(0)))
+	assert.False(t, ProbabilisticPolicy(0.5)(nil))
+
+	half := ProbabilisticPolicy(0.5)
+	assert.True(t, half(spansOf(0x7f)))
+	assert.False(t, half(spansOf(0x80)))
+}
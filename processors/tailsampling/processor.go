@@ -0,0 +1,335 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tailsampling
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultDecisionWait      = 30 * time.Second
+	defaultMaxSpans          = 10000
+	defaultDecisionCacheSize = 10000
+)
+
+type config struct {
+	policies          []Policy
+	decisionWait      time.Duration
+	maxSpans          int
+	decisionCacheSize int
+}
+
+// Option configures the SpanProcessor.
+type Option interface {
+	apply(*config)
+}
+
+type optionFunc func(*config)
+
+func (fn optionFunc) apply(c *config) {
+	fn(c)
+}
+
+// WithPolicies adds policies deciding whether the traces are kept. A trace is
+// kept if any policy keeps it, the policies are evaluated in order. If no
+// policy is configured, all the traces are kept.
+func WithPolicies(policies ...Policy) Option {
+	return optionFunc(func(c *config) {
+		c.policies = append(c.policies, policies...)
+	})
+}
+
+// WithDecisionWait sets the maximum duration the spans of a trace are
+// buffered, from the end of its first span, when its local root span does
+// not end. The default is 30 seconds.
+func WithDecisionWait(d time.Duration) Option {
+	return optionFunc(func(c *config) {
+		if d > 0 {
+			c.decisionWait = d
+		}
+	})
+}
+
+// WithMaxSpans sets the maximum number of buffered spans. When it is reached,
+// the traces are decided in the order their first span ended, from the spans
+// buffered so far. The default is 10000.
+func WithMaxSpans(n int) Option {
+	return optionFunc(func(c *config) {
+		if n > 0 {
+			c.maxSpans = n
+		}
+	})
+}
+
+// WithDecisionCacheSize sets the number of decided traces whose decision is
+// remembered, so their spans ending late are kept or dropped as the rest of
+// the trace. The default is 10000.
+func WithDecisionCacheSize(n int) Option {
+	return optionFunc(func(c *config) {
+		if n >= 0 {
+			c.decisionCacheSize = n
+		}
+	})
+}
+
+// SpanProcessor is a [trace.SpanProcessor] implementation buffering the ended
+// spans of each trace until it can decide whether the trace is kept, and
+// forwarding the spans of the kept traces to another SpanProcessor.
+type SpanProcessor struct {
+	next         trace.SpanProcessor
+	policies     []Policy
+	decisionWait time.Duration
+	maxSpans     int
+	// now returns the current time.
+	now func() time.Time
+
+	mu sync.Mutex
+	// traces are the buffered traces by trace ID, with order holding their
+	// trace IDs in the order their first span ended.
+	traces    map[oteltrace.TraceID]*pendingTrace
+	order     *list.List
+	spanCount int
+	decisions *decisionCache
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+	wg       sync.WaitGroup
+}
+
+type pendingTrace struct {
+	spans    []trace.ReadOnlySpan
+	deadline time.Time
+	elem     *list.Element
+}
+
+var _ trace.SpanProcessor = (*SpanProcessor)(nil)
+
+// NewSpanProcessor returns a new [SpanProcessor] forwarding the spans of the
+// kept traces to next.
+func NewSpanProcessor(next trace.SpanProcessor, opts ...Option) *SpanProcessor {
+	cfg := config{
+		decisionWait:      defaultDecisionWait,
+		maxSpans:          defaultMaxSpans,
+		decisionCacheSize: defaultDecisionCacheSize,
+	}
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+
+	p := &SpanProcessor{
+		next:         next,
+		policies:     cfg.policies,
+		decisionWait: cfg.decisionWait,
+		maxSpans:     cfg.maxSpans,
+		now:          time.Now,
+		traces:       make(map[oteltrace.TraceID]*pendingTrace),
+		order:        list.New(),
+		decisions:    newDecisionCache(cfg.decisionCacheSize),
+		stopCh:       make(chan struct{}),
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		p.processExpired(min(time.Second, cfg.decisionWait))
+	}()
+	return p
+}
+
+// OnStart forwards the started span to the wrapped processor.
+func (p *SpanProcessor) OnStart(parent context.Context, s trace.ReadWriteSpan) {
+	p.next.OnStart(parent, s)
+}
+
+// OnEnd buffers the ended span. The trace of the span is decided when the
+// span is its local root.
+func (p *SpanProcessor) OnEnd(s trace.ReadOnlySpan) {
+	traceID := s.SpanContext().TraceID()
+	parent := s.Parent()
+	localRoot := !parent.IsValid() || parent.IsRemote()
+
+	var decided []decidedTrace
+	p.mu.Lock()
+	if keep, ok := p.decisions.get(traceID); ok {
+		p.mu.Unlock()
+		if keep {
+			p.next.OnEnd(s)
+		}
+		return
+	}
+
+	t, ok := p.traces[traceID]
+	if !ok {
+		t = &pendingTrace{deadline: p.now().Add(p.decisionWait)}
+		t.elem = p.order.PushBack(traceID)
+		p.traces[traceID] = t
+	}
+	t.spans = append(t.spans, s)
+	p.spanCount++
+
+	if localRoot {
+		decided = append(decided, p.decide(traceID, t))
+	}
+	// Decide the oldest traces when the buffer is full.
+	for p.spanCount > p.maxSpans {
+		front := p.order.Front()
+		id := front.Value.(oteltrace.TraceID)
+		decided = append(decided, p.decide(id, p.traces[id]))
+	}
+	p.mu.Unlock()
+
+	p.forward(decided)
+}
+
+// decidedTrace holds the spans of a decided trace.
+type decidedTrace struct {
+	spans []trace.ReadOnlySpan
+	keep  bool
+}
+
+// decide evaluates the policies for the pending trace and removes it from the
+// buffer. It must be called while holding the lock.
+func (p *SpanProcessor) decide(traceID oteltrace.TraceID, t *pendingTrace) decidedTrace {
+	delete(p.traces, traceID)
+	p.order.Remove(t.elem)
+	p.spanCount -= len(t.spans)
+
+	keep := len(p.policies) == 0
+	for _, policy := range p.policies {
+		if policy(t.spans) {
+			keep = true
+			break
+		}
+	}
+	p.decisions.put(traceID, keep)
+	return decidedTrace{spans: t.spans, keep: keep}
+}
+
+// forward passes the spans of the kept traces to the wrapped processor.
+func (p *SpanProcessor) forward(decided []decidedTrace) {
+	for _, d := range decided {
+		if !d.keep {
+			continue
+		}
+		for _, s := range d.spans {
+			p.next.OnEnd(s)
+		}
+	}
+}
+
+// processExpired decides the traces waiting for longer than the decision
+// wait, every interval, until the processor is shut down.
+func (p *SpanProcessor) processExpired(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.decideExpired()
+		case <-p.stopCh:
+			return
+		}
+	}
+}
+
+// decideExpired decides the traces waiting for longer than the decision wait.
+func (p *SpanProcessor) decideExpired() {
+	now := p.now()
+	var decided []decidedTrace
+	p.mu.Lock()
+	// The traces are ordered by deadline.
+	for e := p.order.Front(); e != nil; e = p.order.Front() {
+		id := e.Value.(oteltrace.TraceID)
+		t := p.traces[id]
+		if now.Before(t.deadline) {
+			break
+		}
+		decided = append(decided, p.decide(id, t))
+	}
+	p.mu.Unlock()
+
+	p.forward(decided)
+}
+
+// decideAll decides all the pending traces.
+func (p *SpanProcessor) decideAll() {
+	var decided []decidedTrace
+	p.mu.Lock()
+	for e := p.order.Front(); e != nil; e = p.order.Front() {
+		id := e.Value.(oteltrace.TraceID)
+		decided = append(decided, p.decide(id, p.traces[id]))
+	}
+	p.mu.Unlock()
+
+	p.forward(decided)
+}
+
+// Shutdown decides the pending traces, from the spans buffered so far, and
+// shuts down the wrapped processor.
+func (p *SpanProcessor) Shutdown(ctx context.Context) error {
+	p.stopOnce.Do(func() {
+		close(p.stopCh)
+	})
+	p.wg.Wait()
+
+	p.decideAll()
+	return p.next.Shutdown(ctx)
+}
+
+// ForceFlush decides the pending traces, from the spans buffered so far, and
+// flushes the wrapped processor.
+func (p *SpanProcessor) ForceFlush(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	p.decideAll()
+	return errors.Join(ctx.Err(), p.next.ForceFlush(ctx))
+}
+
+// decisionCache remembers the decisions of the last decided traces.
+type decisionCache struct {
+	size      int
+	decisions map[oteltrace.TraceID]bool
+	// ids is a ring buffer of the trace IDs in the order they were decided.
+	ids  []oteltrace.TraceID
+	next int
+}
+
+func newDecisionCache(size int) *decisionCache {
+	return &decisionCache{
+		size:      size,
+		decisions: make(map[oteltrace.TraceID]bool, size),
+		ids:       make([]oteltrace.TraceID, 0, size),
+	}
+}
+
+func (c *decisionCache) get(id oteltrace.TraceID) (keep, ok bool) {
+	keep, ok = c.decisions[id]
+	return keep, ok
+}
+
+func (c *decisionCache) put(id oteltrace.TraceID, keep bool) {
+	if c.size == 0 {
+		return
+	}
+	if _, ok := c.decisions[id]; ok {
+		c.decisions[id] = keep
+		return
+	}
+	if len(c.ids) < c.size {
+		c.ids = append(c.ids, id)
+	} else {
+		delete(c.decisions, c.ids[c.next])
+		c.ids[c.next] = id
+		c.next = (c.next + 1) % c.size
+	}
+	c.decisions[id] = keep
+}
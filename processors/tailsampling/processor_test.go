@@ -0,0 +1,269 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tailsampling
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+var _ trace.SpanProcessor = &recordingProcessor{}
+
+// recordingProcessor records the spans it receives.
+type recordingProcessor struct {
+	mu       sync.Mutex
+	started  int
+	ended    []trace.ReadOnlySpan
+	flushed  int
+	shutdown int
+}
+
+func (r *recordingProcessor) OnStart(context.Context, trace.ReadWriteSpan) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.started++
+}
+
+func (r *recordingProcessor) OnEnd(s trace.ReadOnlySpan) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ended = append(r.ended, s)
+}
+
+func (r *recordingProcessor) Shutdown(context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.shutdown++
+	return nil
+}
+
+func (r *recordingProcessor) ForceFlush(context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.flushed++
+	return nil
+}
+
+func (r *recordingProcessor) names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	names := make([]string, 0, len(r.ended))
+	for _, s := range r.ended {
+		names = append(names, s.Name())
+	}
+	return names
+}
+
+func newTestProcessor(t *testing.T, opts ...Option) (*SpanProcessor, *recordingProcessor, oteltrace.Tracer) {
+	t.Helper()
+	next := &recordingProcessor{}
+	p := NewSpanProcessor(next, opts...)
+	tp := trace.NewTracerProvider(trace.WithSpanProcessor(p))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	return p, next, tp.Tracer("test")
+}
+
+func TestSpanProcessorKeepsMatchingTraces(t *testing.T) {
+	p, next, tracer := newTestProcessor(t, WithPolicies(ErrorPolicy()))
+
+	ctx, root := tracer.Start(t.Context(), "failed")
+	_, child := tracer.Start(ctx, "failed-child")
+	child.SetStatus(codes.Error, "failed")
+	child.End()
+	assert.Empty(t, next.names(), "buffered until the root ends")
+	root.End()
+	assert.Equal(t, []string{"failed-child", "failed"}, next.names())
+
+	ctx, root = tracer.Start(t.Context(), "ok")
+	_, child = tracer.Start(ctx, "ok-child")
+	child.End()
+	root.End()
+	assert.Equal(t, []string{"failed-child", "failed"}, next.names())
+
+	assert.Equal(t, 4, next.started, "spans started forwarded")
+	assert.Empty(t, p.traces)
+	assert.Zero(t, p.spanCount)
+}
+
+func TestSpanProcessorPolicies(t *testing.T) {
+	_, next, tracer := newTestProcessor(t, WithPolicies(
+		LatencyPolicy(time.Second),
+		AttributePolicy("keep", attribute.BoolValue(true)),
+	))
+
+	start := time.Now()
+	_, span := tracer.Start(t.Context(), "slow", oteltrace.WithTimestamp(start))
+	span.End(oteltrace.WithTimestamp(start.Add(2 * time.Second)))
+	_, span = tracer.Start(t.Context(), "fast", oteltrace.WithTimestamp(start))
+	span.End(oteltrace.WithTimestamp(start.Add(time.Millisecond)))
+	_, span = tracer.Start(t.Context(), "attribute", oteltrace.WithAttributes(attribute.Bool("keep", true)))
+	span.End()
+
+	assert.Equal(t, []string{"slow", "attribute"}, next.names())
+}
+
+func TestSpanProcessorNoPolicy(t *testing.T) {
+	_, next, tracer := newTestProcessor(t)
+	_, span := tracer.Start(t.Context(), "span")
+	span.End()
+	assert.Equal(t, []string{"span"}, next.names())
+}
+
+func TestSpanProcessorRemoteParent(t *testing.T) {
+	_, next, tracer := newTestProcessor(t)
+	remote := oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+		TraceID:    oteltrace.TraceID{1},
+		SpanID:     oteltrace.SpanID{1},
+		TraceFlags: oteltrace.FlagsSampled,
+		Remote:     true,
+	})
+	_, span := tracer.Start(oteltrace.ContextWithRemoteSpanContext(t.Context(), remote), "server")
+	span.End()
+	assert.Equal(t, []string{"server"}, next.names(), "local root with a remote parent")
+}
+
+func TestSpanProcessorDecisionWait(t *testing.T) {
+	p, next, tracer := newTestProcessor(t, WithDecisionWait(time.Minute))
+	now := time.Now()
+	p.now = func() time.Time { return now }
+
+	ctx, root := tracer.Start(t.Context(), "root")
+	defer root.End()
+	_, child := tracer.Start(ctx, "child")
+	child.End()
+
+	now = now.Add(59 * time.Second)
+	p.decideExpired()
+	assert.Empty(t, next.names())
+
+	now = now.Add(time.Second)
+	p.decideExpired()
+	assert.Equal(t, []string{"child"}, next.names())
+	assert.Empty(t, p.traces)
+}
+
+func TestSpanProcessorMaxSpans(t *testing.T) {
+	p, next, tracer := newTestProcessor(t, WithMaxSpans(2), WithPolicies(AttributePolicy("keep")))
+
+	var roots []oteltrace.Span
+	for _, name := range []string{"first", "second", "third"} {
+		ctx, root := tracer.Start(t.Context(), name)
+		roots = append(roots, root)
+		_, child := tracer.Start(ctx, name+"-child", oteltrace.WithAttributes(attribute.Bool("keep", true)))
+		child.End()
+	}
+	// The oldest trace is decided when the buffer is full.
+	assert.Equal(t, []string{"first-child"}, next.names())
+	assert.Equal(t, 2, p.spanCount)
+
+	// The spans of the decided trace ending late follow the decision.
+	roots[0].End()
+	assert.Equal(t, []string{"first-child", "first"}, next.names())
+	assert.Equal(t, 2, p.spanCount)
+
+	for _, root := range roots[1:] {
+		root.End()
+	}
+	assert.Equal(t, []string{"first-child", "first", "second-child", "second", "third-child", "third"}, next.names())
+}
+
+func TestSpanProcessorLateSpans(t *testing.T) {
+	_, next, tracer := newTestProcessor(t, WithPolicies(AttributePolicy("keep")))
+
+	ctx, root := tracer.Start(t.Context(), "dropped")
+	_, late := tracer.Start(ctx, "dropped-late", oteltrace.WithAttributes(attribute.Bool("keep", true)))
+	root.End()
+	late.End()
+	assert.Empty(t, next.names(), "late span of a dropped trace")
+
+	ctx, root = tracer.Start(t.Context(), "kept", oteltrace.WithAttributes(attribute.Bool("keep", true)))
+	_, late = tracer.Start(ctx, "kept-late")
+	root.End()
+	late.End()
+	assert.Equal(t, []string{"kept", "kept-late"}, next.names())
+}
+
+func TestSpanProcessorDecisionCacheSize(t *testing.T) {
+	p, next, tracer := newTestProcessor(t, WithDecisionCacheSize(1))
+
+	ctx1, root1 := tracer.Start(t.Context(), "first")
+	_, late1 := tracer.Start(ctx1, "first-late")
+	root1.End()
+	ctx2, root2 := tracer.Start(t.Context(), "second")
+	_, late2 := tracer.Start(ctx2, "second-late")
+	root2.End()
+
+	// The decision of the first trace is forgotten.
+	late1.End()
+	late2.End()
+	assert.Equal(t, []string{"first", "second", "second-late"}, next.names())
+	assert.Len(t, p.traces, 1)
+
+	require.NoError(t, p.ForceFlush(t.Context()))
+	assert.Equal(t, []string{"first", "second", "second-late", "first-late"}, next.names())
+}
+
+func TestSpanProcessorForceFlush(t *testing.T) {
+	p, next, tracer := newTestProcessor(t)
+
+	ctx, root := tracer.Start(t.Context(), "root")
+	defer root.End()
+	_, child := tracer.Start(ctx, "child")
+	child.End()
+
+	require.NoError(t, p.ForceFlush(t.Context()))
+	assert.Equal(t, []string{"child"}, next.names())
+	assert.Equal(t, 1, next.flushed)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	assert.ErrorIs(t, p.ForceFlush(ctx), context.Canceled)
+}
+
+func TestSpanProcessorShutdown(t *testing.T) {
+	next := &recordingProcessor{}
+	p := NewSpanProcessor(next)
+	tp := trace.NewTracerProvider(trace.WithSpanProcessor(p))
+	tracer := tp.Tracer("test")
+
+	ctx, root := tracer.Start(t.Context(), "root")
+	_, child := tracer.Start(ctx, "child")
+	child.End()
+
+	require.NoError(t, tp.Shutdown(t.Context()))
+	assert.Equal(t, []string{"child"}, next.names())
+	assert.Equal(t, 1, next.shutdown)
+
+	root.End()
+	require.NoError(t, p.Shutdown(t.Context()), "repeated shutdown")
+}
+
+func TestSpanProcessorConcurrency(t *testing.T) {
+	_, next, tracer := newTestProcessor(t, WithMaxSpans(10), WithPolicies(ProbabilisticPolicy(0.5)))
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				ctx, root := tracer.Start(t.Context(), "root")
+				_, child := tracer.Start(ctx, "child")
+				child.End()
+				root.End()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 0, len(next.names())%2, "whole traces are kept")
+}
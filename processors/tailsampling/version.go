@@ -0,0 +1,7 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tailsampling
+
+// Version is the current release version of the tailsampling processor.
+const Version = "0.16.2"
@@ -0,0 +1,105 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package autoprop
+
+import (
+	"context"
+	"slices"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NewFirstMatchTextMapPropagator returns a TextMapPropagator extracting the
+// span context with the first of props, in order, extracting a valid span
+// context from the carrier. The context returned by that propagator is used
+// and the remaining props are not tried. The passed context is returned
+// unchanged if no propagator extracts a valid span context.
+//
+// All of props are used to inject.
+func NewFirstMatchTextMapPropagator(props ...propagation.TextMapPropagator) propagation.TextMapPropagator {
+	return firstMatch(props)
+}
+
+// firstMatch is a TextMapPropagator extracting with the first propagator
+// extracting a valid span context.
+type firstMatch []propagation.TextMapPropagator
+
+var _ propagation.TextMapPropagator = firstMatch(nil)
+
+// Inject injects with all the propagators.
+func (p firstMatch) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	for _, prop := range p {
+		prop.Inject(ctx, carrier)
+	}
+}
+
+// Extract extracts with the first propagator extracting a valid span context.
+func (p firstMatch) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	// Hide any span context of ctx, so a propagator returning ctx unchanged
+	// is not mistaken for one extracting a span context.
+	clean := trace.ContextWithSpanContext(ctx, trace.SpanContext{})
+	for _, prop := range p {
+		extracted := prop.Extract(clean, carrier)
+		if trace.SpanContextFromContext(extracted).IsValid() {
+			return extracted
+		}
+	}
+	return ctx
+}
+
+// Fields returns the union of the fields of all the propagators.
+func (p firstMatch) Fields() []string {
+	var fields []string
+	for _, prop := range p {
+		for _, f := range prop.Fields() {
+			if !slices.Contains(fields, f) {
+				fields = append(fields, f)
+			}
+		}
+	}
+	return fields
+}
+
+// NewDirectionalTextMapPropagator returns a TextMapPropagator extracting
+// with extract and injecting with inject. A nil propagator is treated as a
+// no-op implementation.
+//
+// This allows accepting more formats than the ones sent downstream, e.g.
+// extracting with the B3, X-Ray, and W3C Trace Context propagators while only
+// injecting with the W3C Trace Context propagator.
+func NewDirectionalTextMapPropagator(extract, inject propagation.TextMapPropagator) propagation.TextMapPropagator {
+	if extract == nil {
+		extract = noopPropagator
+	}
+	if inject == nil {
+		inject = noopPropagator
+	}
+	return directional{extract: extract, inject: inject}
+}
+
+// directional is a TextMapPropagator using different propagators to extract
+// and to inject.
+type directional struct {
+	extract propagation.TextMapPropagator
+	inject  propagation.TextMapPropagator
+}
+
+var _ propagation.TextMapPropagator = directional{}
+
+// Inject injects with the inject propagator.
+func (p directional) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	p.inject.Inject(ctx, carrier)
+}
+
+// Extract extracts with the extract propagator.
+func (p directional) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	return p.extract.Extract(ctx, carrier)
+}
+
+// Fields returns the fields set with Inject, i.e. the fields of the inject
+// propagator.
+func (p directional) Fields() []string {
+	return p.inject.Fields()
+}
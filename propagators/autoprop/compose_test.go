@@ -0,0 +1,96 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package autoprop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/contrib/propagators/b3"
+)
+
+var (
+	b3SpanContext = trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x0b, 15: 0x03},
+		SpanID:     trace.SpanID{0x0b, 7: 0x03},
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+	w3cSpanContext = trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x0c, 15: 0x03},
+		SpanID:     trace.SpanID{0x0c, 7: 0x03},
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+)
+
+// headers returns a carrier holding the B3 and W3C Trace Context headers of
+// the span contexts.
+func headers(t *testing.T, scs ...trace.SpanContext) propagation.MapCarrier {
+	t.Helper()
+	carrier := propagation.MapCarrier{}
+	for i, p := range []propagation.TextMapPropagator{b3.New(), propagation.TraceContext{}} {
+		if i < len(scs) && scs[i].IsValid() {
+			p.Inject(trace.ContextWithSpanContext(t.Context(), scs[i]), carrier)
+		}
+	}
+	return carrier
+}
+
+func TestFirstMatchExtract(t *testing.T) {
+	p := NewFirstMatchTextMapPropagator(b3.New(), propagation.TraceContext{})
+
+	ctx := p.Extract(t.Context(), headers(t, b3SpanContext, w3cSpanContext))
+	assert.Equal(t, b3SpanContext, trace.SpanContextFromContext(ctx), "first propagator wins")
+
+	ctx = p.Extract(t.Context(), headers(t, trace.SpanContext{}, w3cSpanContext))
+	assert.Equal(t, w3cSpanContext, trace.SpanContextFromContext(ctx), "falls back to the next propagator")
+
+	parent := trace.ContextWithRemoteSpanContext(t.Context(), b3SpanContext)
+	ctx = p.Extract(parent, headers(t, trace.SpanContext{}, w3cSpanContext))
+	assert.Equal(t, w3cSpanContext, trace.SpanContextFromContext(ctx), "span context of the context is ignored")
+
+	ctx = p.Extract(parent, propagation.MapCarrier{})
+	assert.Equal(t, parent, ctx, "context unchanged without a match")
+}
+
+func TestFirstMatchInject(t *testing.T) {
+	p := NewFirstMatchTextMapPropagator(b3.New(), propagation.TraceContext{})
+	carrier := propagation.MapCarrier{}
+	p.Inject(trace.ContextWithSpanContext(t.Context(), w3cSpanContext), carrier)
+	assert.ElementsMatch(t, []string{"b3", "traceparent"}, carrier.Keys())
+}
+
+func TestFirstMatchFields(t *testing.T) {
+	tc := propagation.TraceContext{}
+	p := NewFirstMatchTextMapPropagator(tc, tc, propagation.Baggage{})
+	assert.Equal(t, []string{"traceparent", "tracestate", "baggage"}, p.Fields())
+}
+
+func TestDirectional(t *testing.T) {
+	p := NewDirectionalTextMapPropagator(b3.New(), propagation.TraceContext{})
+
+	ctx := p.Extract(t.Context(), headers(t, b3SpanContext, w3cSpanContext))
+	assert.Equal(t, b3SpanContext, trace.SpanContextFromContext(ctx))
+
+	carrier := propagation.MapCarrier{}
+	p.Inject(ctx, carrier)
+	assert.Equal(t, []string{"traceparent"}, carrier.Keys())
+	assert.Equal(t, []string{"traceparent", "tracestate"}, p.Fields())
+}
+
+func TestDirectionalNil(t *testing.T) {
+	p := NewDirectionalTextMapPropagator(nil, nil)
+
+	ctx := p.Extract(t.Context(), headers(t, b3SpanContext, w3cSpanContext))
+	assert.False(t, trace.SpanContextFromContext(ctx).IsValid())
+
+	carrier := propagation.MapCarrier{}
+	p.Inject(trace.ContextWithSpanContext(t.Context(), w3cSpanContext), carrier)
+	assert.Empty(t, carrier)
+	assert.Empty(t, p.Fields())
+}
@@ -100,3 +100,37 @@ func ExampleGetTextMapPropagator_custom() {
 	fmt.Println(prop.Fields())
 	// Output: [my-header-val]
 }
+
+func ExampleNewDirectionalTextMapPropagator() {
+	// Accept the B3, X-Ray, and W3C Trace Context formats, using the first
+	// one found in this order, but only send the W3C Trace Context
+	// downstream.
+	b3Prop, _ := autoprop.TextMapPropagator("b3")
+	xrayProp, _ := autoprop.TextMapPropagator("xray")
+	prop := autoprop.NewDirectionalTextMapPropagator(
+		autoprop.NewFirstMatchTextMapPropagator(b3Prop, xrayProp, propagation.TraceContext{}),
+		propagation.TraceContext{},
+	)
+
+	fields := prop.Fields()
+	sort.Strings(fields)
+	fmt.Println(fields)
+	// Output: [traceparent tracestate]
+}
+
+func ExampleTextMapPropagator_directional() {
+	// The same composition, with the W3C Baggage propagated both ways, can be
+	// defined by the OTEL_PROPAGATORS environment variable:
+	//
+	//	OTEL_PROPAGATORS=extract:b3|xray|tracecontext,inject:tracecontext,baggage
+	prop, err := autoprop.TextMapPropagator("extract:b3|xray|tracecontext", "inject:tracecontext", "baggage")
+	if err != nil {
+		// Handle error appropriately.
+		panic(err)
+	}
+
+	fields := prop.Fields()
+	sort.Strings(fields)
+	fmt.Println(fields)
+	// Output: [baggage traceparent tracestate]
+}
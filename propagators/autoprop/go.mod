@@ -6,9 +6,12 @@ require (
 	github.com/stretchr/testify v1.12.1
 	go.opentelemetry.io/contrib/propagators/aws v1.45.0
 	go.opentelemetry.io/contrib/propagators/b3 v1.45.0
+	go.opentelemetry.io/contrib/propagators/cloudtrace v0.70.0
+	go.opentelemetry.io/contrib/propagators/datadog v0.70.0
 	go.opentelemetry.io/contrib/propagators/jaeger v1.45.0
 	go.opentelemetry.io/contrib/propagators/ot v1.45.0
 	go.opentelemetry.io/otel v1.45.0
+	go.opentelemetry.io/otel/trace v1.45.0
 )
 
 require (
@@ -19,7 +22,6 @@ require (
 	go.opentelemetry.io/auto/sdk v1.2.1 // indirect
 	go.opentelemetry.io/otel/metric v1.45.0 // indirect
 	go.opentelemetry.io/otel/sdk v1.45.0 // indirect
-	go.opentelemetry.io/otel/trace v1.45.0 // indirect
 	go.uber.org/multierr v1.11.0 // indirect
 	go.yaml.in/yaml/v3 v3.0.5 // indirect
 	golang.org/x/sys v0.47.0 // indirect
@@ -32,3 +34,7 @@ replace go.opentelemetry.io/contrib/propagators/b3 => ../b3
 replace go.opentelemetry.io/contrib/propagators/aws => ../aws
 
 replace go.opentelemetry.io/contrib/propagators/ot => ../ot
+
+replace go.opentelemetry.io/contrib/propagators/datadog => ../datadog
+
+replace go.opentelemetry.io/contrib/propagators/cloudtrace => ../cloudtrace
@@ -22,12 +22,22 @@ const otelPropagatorsEnvKey = "OTEL_PROPAGATORS"
 // to the once composited by props.
 //
 // The propagators supported with the OTEL_PROPAGATORS environment variable by
-// default are: tracecontext, baggage, b3, b3multi, jaeger, xray, ottrace, and
-// none. Each of these values, and their combination, are supported in
-// conformance with the OpenTelemetry specification. See
+// default are: tracecontext, baggage, b3, b3multi, jaeger, xray, ottrace,
+// datadog, cloudtrace, and none. Each of these values, and their combination,
+// are supported in conformance with the OpenTelemetry specification. See
 // https://github.com/open-telemetry/opentelemetry-specification/blob/main/specification/configuration/sdk-environment-variables.md#general-sdk-configuration
 // for more information.
 //
+// The comma separated values of OTEL_PROPAGATORS can also be combined with
+// "|" and prefixed with "extract:" or "inject:", as described by the
+// TextMapPropagator function. For example,
+//
+//	OTEL_PROPAGATORS=extract:b3|xray|tracecontext,inject:tracecontext,baggage
+//
+// extracts the span context with the first of the B3, X-Ray, and W3C Trace
+// Context propagators extracting a valid one, only injects the W3C Trace
+// Context, and propagates the W3C Baggage both ways.
+//
 // The supported environment variable propagators can be extended to include
 // custom 3rd-party TextMapPropagator. See the RegisterTextMapPropagator
 // function for more information.
@@ -68,8 +78,8 @@ var errUnknownPropagator = errors.New("unknown propagator")
 // parseEnv returns the composite TextMapPropagators defined by the
 // OTEL_PROPAGATORS environment variable. A nil TextMapPropagator is returned
 // if no propagator is defined for the environment variable. A no-op
-// TextMapPropagator will be returned if "none" is defined anywhere in the
-// environment variable.
+// TextMapPropagator will be returned if "none" is defined in the environment
+// variable without an "extract:" or "inject:" prefix.
 func parseEnv() (propagation.TextMapPropagator, error) {
 	propStrs := os.Getenv(otelPropagatorsEnvKey)
 	if propStrs == "" {
@@ -60,3 +60,9 @@ func TestNewTextMapPropagatorMultiEnvNone(t *testing.T) {
 	t.Setenv(otelPropagatorsEnvKey, "b3,none,tracecontext")
 	assert.Equal(t, noop, NewTextMapPropagator())
 }
+
+func TestNewTextMapPropagatorEnvDirectional(t *testing.T) {
+	t.Setenv(otelPropagatorsEnvKey, "extract:b3|tracecontext,inject:tracecontext,baggage")
+	p := NewTextMapPropagator()
+	assert.ElementsMatch(t, []string{"traceparent", "tracestate", "baggage"}, p.Fields())
+}
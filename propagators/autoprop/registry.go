@@ -13,6 +13,8 @@ import (
 
 	"go.opentelemetry.io/contrib/propagators/aws/xray"
 	"go.opentelemetry.io/contrib/propagators/b3"
+	"go.opentelemetry.io/contrib/propagators/cloudtrace"
+	"go.opentelemetry.io/contrib/propagators/datadog"
 	"go.opentelemetry.io/contrib/propagators/jaeger"
 	"go.opentelemetry.io/contrib/propagators/ot"
 )
@@ -21,6 +23,16 @@ import (
 // configured.
 const none = "none"
 
+const (
+	// firstMatchSeparator separates the names of the propagators composed
+	// with NewFirstMatchTextMapPropagator.
+	firstMatchSeparator = "|"
+	// extractPrefix restricts propagators to extracting.
+	extractPrefix = "extract:"
+	// injectPrefix restricts propagators to injecting.
+	injectPrefix = "inject:"
+)
+
 // noopPropagator is the no-op TextMapPropagator returned for the "none"
 // propagator name as well as for empty input. Reusing a single value keeps the
 // "none" and empty-input behavior identical.
@@ -44,6 +56,10 @@ var propagators = &registry{
 		"xray": xray.Propagator{},
 		// OpenTracing Trace.
 		"ottrace": ot.OT{},
+		// Datadog.
+		"datadog": datadog.Datadog{},
+		// Google Cloud Trace X-Cloud-Trace-Context.
+		"cloudtrace": cloudtrace.CloudTrace{},
 
 		// No-op TextMapPropagator.
 		none: noopPropagator,
@@ -96,7 +112,9 @@ func (r *registry) drop(key string) {
 // RegisterTextMapPropagator sets the TextMapPropagator p to be used when the
 // OTEL_PROPAGATORS environment variable contains the propagator name. This
 // will panic if name has already been registered or is a default
-// (tracecontext, baggage, b3, b3multi, jaeger, xray, or ottrace).
+// (tracecontext, baggage, b3, b3multi, jaeger, xray, ottrace, datadog, or
+// cloudtrace). A name containing "|" or starting with "extract:" or "inject:"
+// cannot be referenced from OTEL_PROPAGATORS.
 func RegisterTextMapPropagator(name string, p propagation.TextMapPropagator) {
 	if err := propagators.store(name, p); err != nil {
 		// envRegistry.store will return errDupReg if name is already
@@ -116,10 +134,27 @@ func RegisterTextMapPropagator(name string, p propagation.TextMapPropagator) {
 // passed names of registered TextMapPropagators. Each name must match an
 // already registered TextMapPropagator (see the RegisterTextMapPropagator
 // function for more information) or a default (tracecontext, baggage, b3,
-// b3multi, jaeger, xray, or ottrace).
+// b3multi, jaeger, xray, ottrace, datadog, or cloudtrace).
+//
+// Each argument can also combine names:
+//
+//   - Names separated by "|" are composed with
+//     NewFirstMatchTextMapPropagator, the span context is extracted with the
+//     first of them extracting a valid one (e.g. "b3|xray|tracecontext").
+//   - An "extract:" or "inject:" prefix restricts the argument to extracting
+//     or injecting. The returned TextMapPropagator is then composed with
+//     NewDirectionalTextMapPropagator from the arguments used to extract and
+//     the ones used to inject, arguments without a prefix being used for
+//     both. For example, "extract:b3|xray|tracecontext", "inject:tracecontext",
+//     and "baggage" extract the span context with the first matching of the
+//     B3, X-Ray, and W3C Trace Context propagators, inject it with the W3C
+//     Trace Context propagator, and propagate the W3C Baggage both ways.
 //
 // If "none" is included in the arguments, or no names are provided, the
-// returned TextMapPropagator will be a no-operation implementation.
+// returned TextMapPropagator will be a no-operation implementation. If it is
+// only included in arguments with an "extract:" or "inject:" prefix, only that
+// direction is a no-operation, e.g. "extract:tracecontext" and "inject:none"
+// extract the W3C Trace Context and do not inject anything.
 //
 // An error is returned for any un-registered names. The remaining, known,
 // names will be used to compose a TextMapPropagator that is returned with the
@@ -127,24 +162,65 @@ func RegisterTextMapPropagator(name string, p propagation.TextMapPropagator) {
 // returned with the error so callers can fall back to their own default.
 func TextMapPropagator(names ...string) (propagation.TextMapPropagator, error) {
 	var (
-		props   []propagation.TextMapPropagator
-		unknown []string
+		extract, inject     []propagation.TextMapPropagator
+		directed            bool
+		noExtract, noInject bool
+		unknown             []string
 	)
 
 	for _, name := range names {
-		if name == none {
-			// If "none" is passed in combination with any other propagator,
-			// the result still needs to be a no-op propagator. Therefore,
-			// short-circuit here.
-			return noopPropagator, nil
+		toExtract, toInject := true, true
+		if n, ok := strings.CutPrefix(name, extractPrefix); ok {
+			name, toInject, directed = n, false, true
+		} else if n, ok := strings.CutPrefix(name, injectPrefix); ok {
+			name, toExtract, directed = n, false, true
+		}
+
+		var group []propagation.TextMapPropagator
+		for n := range strings.SplitSeq(name, firstMatchSeparator) {
+			if n == none {
+				if toExtract && toInject {
+					// If "none" is passed in combination with any other
+					// propagator, the result still needs to be a no-op
+					// propagator. Therefore, short-circuit here.
+					return noopPropagator, nil
+				}
+				// Only the direction of the prefix is a no-op.
+				noExtract = noExtract || toExtract
+				noInject = noInject || toInject
+				continue
+			}
+
+			p, ok := propagators.load(n)
+			if !ok {
+				unknown = append(unknown, n)
+				continue
+			}
+			group = append(group, p)
 		}
 
-		p, ok := propagators.load(name)
-		if !ok {
-			unknown = append(unknown, name)
+		var p propagation.TextMapPropagator
+		switch len(group) {
+		case 0:
 			continue
+		case 1:
+			p = group[0]
+		default:
+			p = NewFirstMatchTextMapPropagator(group...)
+		}
+		if toExtract {
+			extract = append(extract, p)
 		}
-		props = append(props, p)
+		if toInject {
+			inject = append(inject, p)
+		}
+	}
+
+	if noExtract {
+		extract = nil
+	}
+	if noInject {
+		inject = nil
 	}
 
 	var err error
@@ -153,8 +229,7 @@ func TextMapPropagator(names ...string) (propagation.TextMapPropagator, error) {
 		err = fmt.Errorf("%w: %s", errUnknownPropagator, joined)
 	}
 
-	switch len(props) {
-	case 0:
+	if len(extract) == 0 && len(inject) == 0 {
 		if err != nil {
 			// Names were provided but none matched a registered propagator.
 			// Return nil so callers such as NewTextMapPropagator can fall back
@@ -164,10 +239,22 @@ func TextMapPropagator(names ...string) (propagation.TextMapPropagator, error) {
 		// No names were provided. Return the no-op propagator, matching the
 		// "none" behavior above.
 		return noopPropagator, nil
+	}
+	if directed {
+		return NewDirectionalTextMapPropagator(compose(extract), compose(inject)), err
+	}
+	return compose(extract), err
+}
+
+// compose returns a TextMapPropagator composed of props.
+func compose(props []propagation.TextMapPropagator) propagation.TextMapPropagator {
+	switch len(props) {
+	case 0:
+		return noopPropagator
 	case 1:
 		// Do not return a composite of a single propagator.
-		return props[0], err
+		return props[0]
 	default:
-		return propagation.NewCompositeTextMapPropagator(props...), err
+		return propagation.NewCompositeTextMapPropagator(props...)
 	}
 }
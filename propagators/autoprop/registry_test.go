@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var noop = propagation.NewCompositeTextMapPropagator()
@@ -93,3 +94,79 @@ func TestTextMapPropagatorAllUnknown(t *testing.T) {
 	require.ErrorIs(t, err, errUnknownPropagator)
 	assert.Nil(t, p, "all-unknown input should return a nil propagator")
 }
+
+func TestTextMapPropagatorDatadogCloudTrace(t *testing.T) {
+	p, err := TextMapPropagator("datadog", "cloudtrace")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		"x-datadog-trace-id",
+		"x-datadog-parent-id",
+		"x-datadog-sampling-priority",
+		"x-datadog-origin",
+		"x-datadog-tags",
+		"x-cloud-trace-context",
+	}, p.Fields())
+}
+
+func TestTextMapPropagatorFirstMatch(t *testing.T) {
+	p, err := TextMapPropagator("b3|tracecontext", "baggage")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"b3", "traceparent", "tracestate", "baggage"}, p.Fields())
+
+	ctx := p.Extract(t.Context(), headers(t, b3SpanContext, w3cSpanContext))
+	assert.Equal(t, b3SpanContext, trace.SpanContextFromContext(ctx))
+}
+
+func TestTextMapPropagatorDirectional(t *testing.T) {
+	p, err := TextMapPropagator("extract:b3multi|tracecontext", "inject:tracecontext", "baggage")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"traceparent", "tracestate", "baggage"}, p.Fields())
+
+	ctx := p.Extract(t.Context(), headers(t, trace.SpanContext{}, w3cSpanContext))
+	assert.Equal(t, w3cSpanContext, trace.SpanContextFromContext(ctx))
+
+	carrier := propagation.MapCarrier{}
+	p.Inject(ctx, carrier)
+	assert.Equal(t, []string{"traceparent"}, carrier.Keys())
+
+	p, err = TextMapPropagator("extract:b3multi")
+	require.NoError(t, err)
+	assert.Empty(t, p.Fields(), "no propagator injecting")
+}
+
+func TestTextMapPropagatorDirectionalNone(t *testing.T) {
+	p, err := TextMapPropagator("extract:tracecontext", "inject:none")
+	require.NoError(t, err)
+	assert.Empty(t, p.Fields(), "no propagator injecting")
+
+	ctx := p.Extract(t.Context(), headers(t, trace.SpanContext{}, w3cSpanContext))
+	assert.Equal(t, w3cSpanContext, trace.SpanContextFromContext(ctx))
+
+	carrier := propagation.MapCarrier{}
+	p.Inject(ctx, carrier)
+	assert.Empty(t, carrier.Keys())
+
+	// The arguments without prefix are not used to extract.
+	p, err = TextMapPropagator("extract:none", "tracecontext")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"traceparent", "tracestate"}, p.Fields())
+
+	ctx = p.Extract(t.Context(), headers(t, trace.SpanContext{}, w3cSpanContext))
+	assert.False(t, trace.SpanContextFromContext(ctx).IsValid())
+}
+
+func TestTextMapPropagatorCombinedUnknown(t *testing.T) {
+	p, err := TextMapPropagator("extract:invalid|tracecontext", "inject:other")
+	require.ErrorIs(t, err, errUnknownPropagator)
+	assert.ErrorContains(t, err, "invalid,other")
+	require.NotNil(t, p)
+	assert.Empty(t, p.Fields(), "no known propagator injecting")
+
+	p, err = TextMapPropagator("extract:invalid", "inject:invalid|other")
+	require.ErrorIs(t, err, errUnknownPropagator)
+	assert.Nil(t, p)
+
+	p, err = TextMapPropagator("extract:tracecontext|none")
+	require.NoError(t, err)
+	assert.Equal(t, noopPropagator, p)
+}
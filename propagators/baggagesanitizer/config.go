@@ -0,0 +1,141 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package baggagesanitizer
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	// defaultMaxMemberSize is the maximum size of a member, as defined by
+	// the W3C Baggage specification.
+	defaultMaxMemberSize = 4096
+	// defaultMaxSize is the maximum size of the baggage, as defined by the
+	// W3C Baggage specification.
+	defaultMaxSize = 8192
+)
+
+// Filter returns true if the baggage member is allowed.
+type Filter func(member baggage.Member) bool
+
+// AllowAllMembers allows all baggage members.
+var AllowAllMembers Filter = func(baggage.Member) bool { return true }
+
+// AllowKeys returns a Filter allowing the baggage members with one of keys.
+func AllowKeys(keys ...string) Filter {
+	allowed := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		allowed[k] = struct{}{}
+	}
+	return func(member baggage.Member) bool {
+		_, ok := allowed[member.Key()]
+		return ok
+	}
+}
+
+// Action is the action taken for the baggage members not allowed by the
+// Filter.
+type Action int
+
+const (
+	// Drop removes the members from the baggage.
+	Drop Action = iota
+	// Hash replaces the values of the members with the hexadecimal encoded
+	// SHA-256 hash of their values, and removes their properties. This keeps
+	// the members correlatable without propagating their values. Low entropy
+	// values can be recovered from their hashes: do not use it to protect
+	// sensitive values.
+	Hash
+)
+
+type config struct {
+	filter        Filter
+	action        Action
+	maxMemberSize int
+	maxSize       int
+	meterProvider metric.MeterProvider
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{
+		filter:        AllowAllMembers,
+		action:        Drop,
+		maxMemberSize: defaultMaxMemberSize,
+		maxSize:       defaultMaxSize,
+		meterProvider: otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+	return cfg
+}
+
+// Option configures the propagator returned by New.
+type Option interface {
+	apply(*config)
+}
+
+type optionFunc func(*config)
+
+func (fn optionFunc) apply(c *config) {
+	fn(c)
+}
+
+// WithFilter sets the Filter allowing the extracted baggage members. The
+// members not allowed are handled according to the Action set with
+// WithDisallowedAction. All the members are allowed by default.
+func WithFilter(filter Filter) Option {
+	return optionFunc(func(c *config) {
+		if filter != nil {
+			c.filter = filter
+		}
+	})
+}
+
+// WithAllowedKeys allows only the extracted baggage members with one of keys.
+// It is a shorthand for WithFilter(AllowKeys(keys...)).
+func WithAllowedKeys(keys ...string) Option {
+	return WithFilter(AllowKeys(keys...))
+}
+
+// WithDisallowedAction sets the Action taken for the members not allowed by
+// the Filter. The default is Drop.
+func WithDisallowedAction(action Action) Option {
+	return optionFunc(func(c *config) {
+		c.action = action
+	})
+}
+
+// WithMaxMemberSize sets the maximum size, in bytes, of an encoded baggage
+// member. Larger members are dropped. The default is 4096.
+func WithMaxMemberSize(size int) Option {
+	return optionFunc(func(c *config) {
+		if size > 0 {
+			c.maxMemberSize = size
+		}
+	})
+}
+
+// WithMaxSize sets the maximum size, in bytes, of the encoded baggage. The
+// members are added to the baggage in the order of their keys, the members
+// not fitting anymore are dropped. The default is 8192.
+func WithMaxSize(size int) Option {
+	return optionFunc(func(c *config) {
+		if size > 0 {
+			c.maxSize = size
+		}
+	})
+}
+
+// WithMeterProvider sets the MeterProvider used to record the number of
+// dropped members. The global MeterProvider is used by default.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return optionFunc(func(c *config) {
+		if mp != nil {
+			c.meterProvider = mp
+		}
+	})
+}
@@ -0,0 +1,20 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package baggagesanitizer provides an OpenTelemetry TextMapPropagator
+// limiting the [Baggage] extracted by another TextMapPropagator.
+//
+// Baggage received at a trust boundary, e.g. a public endpoint, can be set
+// arbitrarily by the callers. As it is propagated downstream and can be
+// copied into the attributes of every span and log record, e.g. with the
+// go.opentelemetry.io/contrib/processors/baggagecopy processors, the
+// propagator returned by [New] restricts the extracted members to the allowed
+// keys, drops or hashes the other members, and enforces size limits.
+//
+// # Usage
+//
+// Wrap the propagator extracting the baggage, e.g. the propagator passed to
+// the instrumentation of the public endpoints, with [New].
+//
+// [Baggage]: https://opentelemetry.io/docs/specs/otel/baggage/api/
+package baggagesanitizer
@@ -0,0 +1,33 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package baggagesanitizer_test
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+
+	"go.opentelemetry.io/contrib/propagators/baggagesanitizer"
+)
+
+func ExampleNew() {
+	// Only accept the tenant and region baggage members from the callers of
+	// a public endpoint, and hash the values of the other members.
+	prop := baggagesanitizer.New(
+		propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}),
+		baggagesanitizer.WithAllowedKeys("tenant", "region"),
+		baggagesanitizer.WithDisallowedAction(baggagesanitizer.Hash),
+		baggagesanitizer.WithMaxSize(1024),
+	)
+
+	carrier := propagation.MapCarrier{"baggage": "tenant=gold,email=alice@example.com"}
+	b := baggage.FromContext(prop.Extract(context.Background(), carrier))
+	fmt.Println(b.Member("tenant").Value())
+	fmt.Println(b.Member("email").Value())
+	// Output:
+	// gold
+	// ff8d9819fc0e12bf0d24892e45987e249a28dce836a85cad60e28eaaa8c6d976
+}
@@ -0,0 +1,181 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package baggagesanitizer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// ScopeName is the instrumentation scope name of the metrics of the
+// propagator.
+const ScopeName = "go.opentelemetry.io/contrib/propagators/baggagesanitizer"
+
+// reasonKey is the attribute key of the reason a member is dropped.
+const reasonKey = attribute.Key("reason")
+
+var (
+	// reasonFiltered is set for the members not allowed by the Filter.
+	reasonFiltered = metric.WithAttributeSet(attribute.NewSet(reasonKey.String("filtered")))
+	// reasonMemberSize is set for the members larger than the maximum member
+	// size.
+	reasonMemberSize = metric.WithAttributeSet(attribute.NewSet(reasonKey.String("member_size")))
+	// reasonSize is set for the members not fitting in the maximum baggage
+	// size.
+	reasonSize = metric.WithAttributeSet(attribute.NewSet(reasonKey.String("size")))
+)
+
+// propagator is a TextMapPropagator sanitizing the baggage extracted by
+// another TextMapPropagator.
+type propagator struct {
+	next          propagation.TextMapPropagator
+	filter        Filter
+	action        Action
+	maxMemberSize int
+	maxSize       int
+	dropped       metric.Int64Counter
+}
+
+var _ propagation.TextMapPropagator = (*propagator)(nil)
+
+// New returns a TextMapPropagator extracting and injecting with next, and
+// sanitizing the extracted baggage according to opts.
+//
+// The extracted baggage members not allowed by the configured Filter are
+// dropped or hashed, and the members exceeding the size limits are dropped.
+// The number of dropped members is recorded by the
+// baggage.sanitizer.dropped_members counter.
+//
+// The baggage of the context passed to Extract is kept if next does not
+// extract any baggage. The injected baggage is not modified.
+func New(next propagation.TextMapPropagator, opts ...Option) propagation.TextMapPropagator {
+	cfg := newConfig(opts)
+	p := &propagator{
+		next:          next,
+		filter:        cfg.filter,
+		action:        cfg.action,
+		maxMemberSize: cfg.maxMemberSize,
+		maxSize:       cfg.maxSize,
+	}
+
+	meter := cfg.meterProvider.Meter(ScopeName, metric.WithInstrumentationVersion(Version()))
+	var err error
+	p.dropped, err = meter.Int64Counter(
+		"baggage.sanitizer.dropped_members",
+		metric.WithDescription("Number of extracted baggage members dropped."),
+		metric.WithUnit("{member}"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+	return p
+}
+
+// Inject injects with the wrapped propagator.
+func (p *propagator) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	p.next.Inject(ctx, carrier)
+}
+
+// Extract extracts with the wrapped propagator and sanitizes the extracted
+// baggage.
+func (p *propagator) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	// Extract from a context without baggage to identify the extracted
+	// members.
+	ctx, orig := baggage.ContextWithoutBaggage(ctx), baggage.FromContext(ctx)
+	ctx = p.next.Extract(ctx, carrier)
+
+	extracted := baggage.FromContext(ctx)
+	if extracted.Len() == 0 {
+		if orig.Len() == 0 {
+			return ctx
+		}
+		return baggage.ContextWithBaggage(ctx, orig)
+	}
+	return baggage.ContextWithBaggage(ctx, p.sanitize(ctx, extracted))
+}
+
+// Fields returns the fields of the wrapped propagator.
+func (p *propagator) Fields() []string {
+	return p.next.Fields()
+}
+
+// sanitize returns the members of b allowed by the filter, or hashed, and
+// fitting in the size limits.
+func (p *propagator) sanitize(ctx context.Context, b baggage.Baggage) baggage.Baggage {
+	members := b.Members()
+	// Sort the members so the ones kept within the maximum size do not
+	// depend on the iteration order of the baggage.
+	slices.SortFunc(members, func(a, b baggage.Member) int {
+		return strings.Compare(a.Key(), b.Key())
+	})
+
+	var (
+		kept                          = members[:0]
+		size                          int
+		filtered, tooLarge, overflown int64
+	)
+	for _, m := range members {
+		if !p.filter(m) {
+			if p.action != Hash {
+				filtered++
+				continue
+			}
+			m = hash(m)
+		}
+
+		n := len(m.String())
+		if n > p.maxMemberSize {
+			tooLarge++
+			continue
+		}
+		if len(kept) > 0 {
+			// Separating comma.
+			n++
+		}
+		if size+n > p.maxSize {
+			overflown++
+			continue
+		}
+		size += n
+		kept = append(kept, m)
+	}
+
+	p.record(ctx, filtered, reasonFiltered)
+	p.record(ctx, tooLarge, reasonMemberSize)
+	p.record(ctx, overflown, reasonSize)
+
+	sanitized, err := baggage.New(kept...)
+	if err != nil {
+		otel.Handle(err)
+	}
+	return sanitized
+}
+
+func (p *propagator) record(ctx context.Context, n int64, reason metric.AddOption) {
+	if n > 0 && p.dropped != nil {
+		p.dropped.Add(ctx, n, reason)
+	}
+}
+
+// hash returns m with its value replaced by the hexadecimal encoded SHA-256
+// hash of its value, and without properties.
+func hash(m baggage.Member) baggage.Member {
+	sum := sha256.Sum256([]byte(m.Value()))
+	hashed, err := baggage.NewMemberRaw(m.Key(), hex.EncodeToString(sum[:]))
+	if err != nil {
+		// The key of an extracted member is valid.
+		otel.Handle(err)
+		return m
+	}
+	return hashed
+}
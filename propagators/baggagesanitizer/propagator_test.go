@@ -0,0 +1,142 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package baggagesanitizer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata/metricdatatest"
+)
+
+func extract(t *testing.T, p propagation.TextMapPropagator, header string) baggage.Baggage {
+	t.Helper()
+	carrier := propagation.MapCarrier{"baggage": header}
+	return baggage.FromContext(p.Extract(t.Context(), carrier))
+}
+
+// assertMembers asserts b holds the comma separated members of want.
+func assertMembers(t *testing.T, want string, b baggage.Baggage, msgAndArgs ...any) {
+	t.Helper()
+	got := make([]string, 0, b.Len())
+	for _, m := range b.Members() {
+		got = append(got, m.String())
+	}
+	assert.ElementsMatch(t, strings.Split(want, ","), got, msgAndArgs...)
+}
+
+func TestExtractAllowedKeys(t *testing.T) {
+	p := New(propagation.Baggage{}, WithAllowedKeys("tenant", "region"))
+	b := extract(t, p, "tenant=gold,user=alice;pii,region=eu")
+	assertMembers(t, "region=eu,tenant=gold", b)
+}
+
+func TestExtractFilter(t *testing.T) {
+	p := New(propagation.Baggage{}, WithFilter(func(m baggage.Member) bool {
+		return strings.HasPrefix(m.Key(), "app.")
+	}))
+	b := extract(t, p, "app.tenant=gold,user=alice")
+	assertMembers(t, "app.tenant=gold", b)
+}
+
+func TestExtractHash(t *testing.T) {
+	p := New(propagation.Baggage{}, WithAllowedKeys("tenant"), WithDisallowedAction(Hash))
+	b := extract(t, p, "tenant=gold,user=alice;pii")
+	assert.Equal(t, "gold", b.Member("tenant").Value())
+	user := b.Member("user")
+	// echo -n alice | sha256sum
+	assert.Equal(t, "2bd806c97f0e00af1a1fc3328fa763a9269723c8db8fac4f93af71db186d6e90", user.Value())
+	assert.Empty(t, user.Properties())
+}
+
+func TestExtractMaxMemberSize(t *testing.T) {
+	p := New(propagation.Baggage{}, WithMaxMemberSize(10))
+	b := extract(t, p, "short=1,long=0123456789,prop=1;p")
+	assertMembers(t, "prop=1;p,short=1", b)
+}
+
+func TestExtractMaxSize(t *testing.T) {
+	p := New(propagation.Baggage{}, WithMaxSize(15))
+	// The members are added in the order of their keys.
+	b := extract(t, p, "d=4,c=3,b=22222,a=1,e=5")
+	assertMembers(t, "a=1,b=22222,c=3", b)
+
+	p = New(propagation.Baggage{}, WithMaxSize(14))
+	b = extract(t, p, "d=4,c=3,b=22222,a=1,e=5")
+	assertMembers(t, "a=1,b=22222", b, "the member not fitting is dropped")
+
+	p = New(propagation.Baggage{}, WithMaxSize(3))
+	b = extract(t, p, "long=22222,a=1")
+	assertMembers(t, "a=1", b, "smaller members after a dropped one are kept")
+}
+
+func TestExtractKeepsContextBaggage(t *testing.T) {
+	p := New(propagation.Baggage{}, WithAllowedKeys("tenant"))
+	m, err := baggage.NewMember("local", "value")
+	require.NoError(t, err)
+	orig, err := baggage.New(m)
+	require.NoError(t, err)
+	ctx := baggage.ContextWithBaggage(t.Context(), orig)
+
+	got := baggage.FromContext(p.Extract(ctx, propagation.MapCarrier{}))
+	assert.Equal(t, orig, got, "baggage of the context is not sanitized")
+
+	got = baggage.FromContext(p.Extract(ctx, propagation.MapCarrier{"baggage": "tenant=gold,local=x"}))
+	assertMembers(t, "tenant=gold", got, "extracted baggage replaces the one of the context")
+}
+
+func TestInjectAndFields(t *testing.T) {
+	next := propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+	p := New(next, WithAllowedKeys("tenant"))
+	assert.ElementsMatch(t, next.Fields(), p.Fields())
+
+	m, err := baggage.NewMember("user", "alice")
+	require.NoError(t, err)
+	b, err := baggage.New(m)
+	require.NoError(t, err)
+	carrier := propagation.MapCarrier{}
+	p.Inject(baggage.ContextWithBaggage(t.Context(), b), carrier)
+	assert.Equal(t, "user=alice", carrier.Get("baggage"), "injected baggage is not sanitized")
+}
+
+func TestDroppedMembersMetric(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	p := New(propagation.Baggage{},
+		WithAllowedKeys("a", "b", "c", "long"),
+		WithMaxMemberSize(8),
+		WithMaxSize(7),
+		WithMeterProvider(mp),
+	)
+	b := extract(t, p, "a=1,b=2,c=3,long=22222,x=1,y=2")
+	assertMembers(t, "a=1,b=2", b)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(t.Context(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	assert.Equal(t, ScopeName, rm.ScopeMetrics[0].Scope.Name)
+	require.Len(t, rm.ScopeMetrics[0].Metrics, 1)
+
+	metricdatatest.AssertEqual(t, metricdata.Metrics{
+		Name:        "baggage.sanitizer.dropped_members",
+		Description: "Number of extracted baggage members dropped.",
+		Unit:        "{member}",
+		Data: metricdata.Sum[int64]{
+			Temporality: metricdata.CumulativeTemporality,
+			IsMonotonic: true,
+			DataPoints: []metricdata.DataPoint[int64]{
+				{Attributes: attribute.NewSet(reasonKey.String("filtered")), Value: 2},
+				{Attributes: attribute.NewSet(reasonKey.String("member_size")), Value: 1},
+				{Attributes: attribute.NewSet(reasonKey.String("size")), Value: 1},
+			},
+		},
+	}, rm.ScopeMetrics[0].Metrics[0], metricdatatest.IgnoreTimestamp())
+}
@@ -0,0 +1,10 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package baggagesanitizer
+
+// Version is the current release version of the baggage sanitizer propagator.
+func Version() string {
+	return "0.70.0"
+	// This string is updated by the pre_release.sh script during release
+}
@@ -0,0 +1,8 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package cloudtrace implements the propagation format of the
+// X-Cloud-Trace-Context header, as added to the requests by the Google Cloud
+// load balancers and used by the Google Cloud services, as defined at
+// https://cloud.google.com/trace/docs/trace-context#legacy-http-header
+package cloudtrace
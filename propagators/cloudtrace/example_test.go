@@ -0,0 +1,20 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudtrace_test
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+
+	"go.opentelemetry.io/contrib/propagators/cloudtrace"
+)
+
+func ExampleCloudTrace() {
+	// Extract the trace context from the header set by the Google Cloud load
+	// balancers, and propagate it with the W3C Trace Context downstream.
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		cloudtrace.CloudTrace{},
+	))
+}
@@ -0,0 +1,126 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudtrace
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	cloudTraceHeader = "x-cloud-trace-context"
+	traceIDWidth     = 128 / 4
+	optionsPrefix    = "o="
+
+	optionsSampled = 0x01
+)
+
+var (
+	empty = trace.SpanContext{}
+
+	errMalformedHeader = errors.New("header value of x-cloud-trace-context should be TRACE_ID/SPAN_ID;o=OPTIONS")
+	errInvalidTraceID  = errors.New("invalid trace id, must be 32 hexadecimal characters and not all zero")
+	errInvalidSpanID   = errors.New("invalid span id, must be a non zero unsigned 64-bit decimal number")
+	errInvalidOptions  = errors.New("invalid trace options, must be a decimal number")
+)
+
+// CloudTrace propagator serializes SpanContext to/from the
+// X-Cloud-Trace-Context header.
+//
+// X-Cloud-Trace-Context format:
+//
+// X-Cloud-Trace-Context: {trace-id}/{span-id};o={options}
+//
+// The trace ID is 32 hexadecimal characters, the span ID is the decimal
+// representation of the unsigned span ID, and the options are 1 if the trace
+// is sampled, 0 otherwise.
+type CloudTrace struct{}
+
+var _ propagation.TextMapPropagator = CloudTrace{}
+
+// Inject injects a context to the carrier following the
+// X-Cloud-Trace-Context format.
+func (CloudTrace) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	sc := trace.SpanFromContext(ctx).SpanContext()
+	if !sc.TraceID().IsValid() || !sc.SpanID().IsValid() {
+		return
+	}
+	spanID := sc.SpanID()
+	options := 0
+	if sc.IsSampled() {
+		options = optionsSampled
+	}
+	carrier.Set(cloudTraceHeader, fmt.Sprintf("%s/%d;%s%d",
+		sc.TraceID(), binary.BigEndian.Uint64(spanID[:]), optionsPrefix, options))
+}
+
+// Extract extracts a context from the carrier if it contains an
+// X-Cloud-Trace-Context header.
+func (CloudTrace) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	h := carrier.Get(cloudTraceHeader)
+	if h == "" {
+		return ctx
+	}
+	sc, err := extract(h)
+	if err != nil || !sc.IsValid() {
+		return ctx
+	}
+	return trace.ContextWithRemoteSpanContext(ctx, sc)
+}
+
+// Fields returns the X-Cloud-Trace-Context header key whose value is set
+// with Inject.
+func (CloudTrace) Fields() []string {
+	return []string{cloudTraceHeader}
+}
+
+// extract reconstructs a SpanContext from the X-Cloud-Trace-Context header
+// value. The options are optional, the trace is not sampled without them.
+func extract(h string) (trace.SpanContext, error) {
+	traceID, rest, ok := strings.Cut(strings.TrimSpace(h), "/")
+	if !ok {
+		return empty, errMalformedHeader
+	}
+	spanID, options, hasOptions := strings.Cut(rest, ";")
+
+	var (
+		scc trace.SpanContextConfig
+		err error
+	)
+	if len(traceID) != traceIDWidth {
+		return empty, errInvalidTraceID
+	}
+	if scc.TraceID, err = trace.TraceIDFromHex(strings.ToLower(traceID)); err != nil {
+		return empty, errInvalidTraceID
+	}
+
+	id, err := strconv.ParseUint(spanID, 10, 64)
+	if err != nil || id == 0 {
+		return empty, errInvalidSpanID
+	}
+	binary.BigEndian.PutUint64(scc.SpanID[:], id)
+
+	if hasOptions {
+		v, ok := strings.CutPrefix(options, optionsPrefix)
+		if !ok {
+			return empty, errMalformedHeader
+		}
+		o, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return empty, errInvalidOptions
+		}
+		if o&optionsSampled == optionsSampled {
+			scc.TraceFlags = trace.FlagsSampled
+		}
+	}
+
+	return trace.NewSpanContext(scc), nil
+}
@@ -0,0 +1,164 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudtrace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	traceID    = trace.TraceID{0x10, 0x54, 0x45, 0xaa, 0x7c, 0x48, 0x93, 0x4f, 0x9f, 0x6e, 0x5b, 0x13, 0x8e, 0xa1, 0x05, 0x5d}
+	traceIDStr = "105445aa7c48934f9f6e5b138ea1055d"
+	spanID     = trace.SpanID{7: 0x7b}
+	spanIDStr  = "123"
+)
+
+func TestCloudTraceExtract(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   trace.SpanContextConfig
+	}{
+		{
+			name:   "sampled",
+			header: traceIDStr + "/" + spanIDStr + ";o=1",
+			want:   trace.SpanContextConfig{TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled},
+		},
+		{
+			name:   "not sampled",
+			header: traceIDStr + "/" + spanIDStr + ";o=0",
+			want:   trace.SpanContextConfig{TraceID: traceID, SpanID: spanID},
+		},
+		{
+			name:   "no options",
+			header: traceIDStr + "/" + spanIDStr,
+			want:   trace.SpanContextConfig{TraceID: traceID, SpanID: spanID},
+		},
+		{
+			name:   "uppercase trace ID",
+			header: "105445AA7C48934F9F6E5B138EA1055D/" + spanIDStr + ";o=3",
+			want:   trace.SpanContextConfig{TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled},
+		},
+		{
+			name:   "largest span ID",
+			header: traceIDStr + "/18446744073709551615;o=1",
+			want: trace.SpanContextConfig{
+				TraceID:    traceID,
+				SpanID:     trace.SpanID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+				TraceFlags: trace.FlagsSampled,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			carrier := propagation.MapCarrier{cloudTraceHeader: tc.header}
+			ctx := CloudTrace{}.Extract(t.Context(), carrier)
+			want := trace.NewSpanContext(tc.want).WithRemote(true)
+			assert.Equal(t, want, trace.SpanContextFromContext(ctx))
+		})
+	}
+}
+
+func TestCloudTraceExtractInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		err    error
+	}{
+		{
+			name:   "no span ID",
+			header: traceIDStr,
+			err:    errMalformedHeader,
+		},
+		{
+			name:   "short trace ID",
+			header: "5445aa7c48934f9f6e5b138ea1055d/" + spanIDStr,
+			err:    errInvalidTraceID,
+		},
+		{
+			name:   "zero trace ID",
+			header: "00000000000000000000000000000000/" + spanIDStr,
+			err:    errInvalidTraceID,
+		},
+		{
+			name:   "hexadecimal span ID",
+			header: traceIDStr + "/7b;o=1",
+			err:    errInvalidSpanID,
+		},
+		{
+			name:   "zero span ID",
+			header: traceIDStr + "/0;o=1",
+			err:    errInvalidSpanID,
+		},
+		{
+			name:   "overflowing span ID",
+			header: traceIDStr + "/18446744073709551616;o=1",
+			err:    errInvalidSpanID,
+		},
+		{
+			name:   "malformed options",
+			header: traceIDStr + "/" + spanIDStr + ";1",
+			err:    errMalformedHeader,
+		},
+		{
+			name:   "invalid options",
+			header: traceIDStr + "/" + spanIDStr + ";o=true",
+			err:    errInvalidOptions,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sc, err := extract(tc.header)
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, empty, sc)
+
+			carrier := propagation.MapCarrier{cloudTraceHeader: tc.header}
+			ctx := CloudTrace{}.Extract(t.Context(), carrier)
+			assert.False(t, trace.SpanContextFromContext(ctx).IsValid())
+		})
+	}
+}
+
+func TestCloudTraceInject(t *testing.T) {
+	tests := []struct {
+		name string
+		sc   trace.SpanContextConfig
+		want propagation.MapCarrier
+	}{
+		{
+			name: "sampled",
+			sc:   trace.SpanContextConfig{TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled},
+			want: propagation.MapCarrier{cloudTraceHeader: traceIDStr + "/" + spanIDStr + ";o=1"},
+		},
+		{
+			name: "not sampled",
+			sc:   trace.SpanContextConfig{TraceID: traceID, SpanID: spanID},
+			want: propagation.MapCarrier{cloudTraceHeader: traceIDStr + "/" + spanIDStr + ";o=0"},
+		},
+		{
+			name: "invalid span context",
+			sc:   trace.SpanContextConfig{TraceID: traceID},
+			want: propagation.MapCarrier{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := trace.ContextWithSpanContext(t.Context(), trace.NewSpanContext(tc.sc))
+			carrier := propagation.MapCarrier{}
+			CloudTrace{}.Inject(ctx, carrier)
+			assert.Equal(t, tc.want, carrier)
+		})
+	}
+}
+
+func TestCloudTraceFields(t *testing.T) {
+	assert.Equal(t, []string{"x-cloud-trace-context"}, CloudTrace{}.Fields())
+}
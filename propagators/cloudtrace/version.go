@@ -0,0 +1,10 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package cloudtrace
+
+// Version is the current release version of the Cloud Trace propagator.
+func Version() string {
+	return "0.70.0"
+	// This string is updated by the pre_release.sh script during release
+}
@@ -0,0 +1,22 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package datadog implements the Datadog propagation format as used by the
+// Datadog tracing libraries.
+//
+// The trace context is carried by the following headers:
+//
+//	x-datadog-trace-id: {lower 64 bits of the trace ID, as a decimal number}
+//	x-datadog-parent-id: {span ID, as a decimal number}
+//	x-datadog-sampling-priority: {sampling priority}
+//	x-datadog-origin: {origin of the trace}
+//	x-datadog-tags: {comma separated propagated tags}
+//
+// The upper 64 bits of 128-bit trace IDs are carried by the _dd.p.tid tag of
+// the x-datadog-tags header, as 16 lowercase hexadecimal characters.
+//
+// The sampling priority, the origin and the propagated tags are stored in the
+// "dd" member of the trace state of the extracted span context, as the Datadog
+// tracing libraries do for the W3C Trace Context, so they are propagated
+// downstream unchanged.
+package datadog
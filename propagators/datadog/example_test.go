@@ -0,0 +1,16 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package datadog_test
+
+import (
+	"go.opentelemetry.io/otel"
+
+	"go.opentelemetry.io/contrib/propagators/datadog"
+)
+
+func ExampleDatadog() {
+	datadogPropagator := datadog.Datadog{}
+	// register datadog propagator
+	otel.SetTextMapPropagator(datadogPropagator)
+}
@@ -0,0 +1,301 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package datadog
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	traceIDHeader  = "x-datadog-trace-id"
+	parentIDHeader = "x-datadog-parent-id"
+	priorityHeader = "x-datadog-sampling-priority"
+	originHeader   = "x-datadog-origin"
+	tagsHeader     = "x-datadog-tags"
+
+	// propagatedTagPrefix is the prefix of the tags propagated with the
+	// x-datadog-tags header.
+	propagatedTagPrefix = "_dd.p."
+	// traceIDUpperTag is the tag holding the upper 64 bits of the trace ID.
+	traceIDUpperTag = propagatedTagPrefix + "tid"
+	// maxTagsLength is the maximum length of the x-datadog-tags header
+	// accepted by the Datadog tracing libraries.
+	maxTagsLength = 512
+
+	// traceStateKey is the key of the trace state member holding the
+	// Datadog specific trace context.
+	traceStateKey = "dd"
+)
+
+var (
+	empty = trace.SpanContext{}
+
+	errInvalidTraceIDHeader   = errors.New("invalid Datadog trace ID header found")
+	errInvalidParentIDHeader  = errors.New("invalid Datadog parent ID header found")
+	errInvalidPriorityHeader  = errors.New("invalid Datadog sampling priority header found")
+	errInvalidScope           = errors.New("require either both Datadog trace ID and parent ID or none")
+	errInvalidTraceIDUpperTag = errors.New("invalid Datadog _dd.p.tid tag found")
+)
+
+// Datadog propagator serializes SpanContext to/from Datadog headers.
+//
+// Datadog format:
+//
+//	x-datadog-trace-id: {lower 64 bits of the trace ID, decimal}
+//	x-datadog-parent-id: {span ID, decimal}
+//	x-datadog-sampling-priority: {sampling priority}
+//	x-datadog-origin: {origin}
+//	x-datadog-tags: _dd.p.tid={upper 64 bits of the trace ID, hex},...
+type Datadog struct{}
+
+var _ propagation.TextMapPropagator = Datadog{}
+
+// Inject injects a context to the carrier following the Datadog format.
+func (Datadog) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	sc := trace.SpanFromContext(ctx).SpanContext()
+	if !sc.TraceID().IsValid() || !sc.SpanID().IsValid() {
+		return
+	}
+	traceID, spanID := sc.TraceID(), sc.SpanID()
+	state := parseState(sc.TraceState().Get(traceStateKey))
+
+	carrier.Set(traceIDHeader, strconv.FormatUint(binary.BigEndian.Uint64(traceID[8:]), 10))
+	carrier.Set(parentIDHeader, strconv.FormatUint(binary.BigEndian.Uint64(spanID[:]), 10))
+	carrier.Set(priorityHeader, strconv.Itoa(state.samplingPriority(sc.IsSampled())))
+	if state.origin != "" {
+		carrier.Set(originHeader, state.origin)
+	}
+	if tags := injectTags(traceID, state.tags); tags != "" {
+		carrier.Set(tagsHeader, tags)
+	}
+}
+
+// Extract extracts a context from the carrier if it contains Datadog headers.
+func (Datadog) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	sc, err := extract(
+		carrier.Get(traceIDHeader),
+		carrier.Get(parentIDHeader),
+		carrier.Get(priorityHeader),
+		carrier.Get(originHeader),
+		carrier.Get(tagsHeader),
+	)
+	if err != nil || !sc.IsValid() {
+		return ctx
+	}
+	return trace.ContextWithRemoteSpanContext(ctx, sc)
+}
+
+// Fields returns the Datadog header keys whose values are set with Inject.
+func (Datadog) Fields() []string {
+	return []string{traceIDHeader, parentIDHeader, priorityHeader, originHeader, tagsHeader}
+}
+
+// injectTags returns the x-datadog-tags header value carrying the upper 64
+// bits of traceID and tags. Only the upper bits of the trace ID are injected
+// if the header would be longer than accepted by the Datadog tracing
+// libraries.
+func injectTags(traceID trace.TraceID, tags []tag) string {
+	var upper string
+	if binary.BigEndian.Uint64(traceID[:8]) != 0 {
+		upper = traceIDUpperTag + "=" + hex.EncodeToString(traceID[:8])
+	}
+
+	parts := make([]string, 0, len(tags)+1)
+	if upper != "" {
+		parts = append(parts, upper)
+	}
+	for _, t := range tags {
+		parts = append(parts, propagatedTagPrefix+t.key+"="+t.value)
+	}
+	if header := strings.Join(parts, ","); len(header) <= maxTagsLength {
+		return header
+	}
+	return upper
+}
+
+// extract reconstructs a SpanContext from header values based on Datadog
+// headers.
+func extract(traceID, parentID, priority, origin, tags string) (trace.SpanContext, error) {
+	if traceID == "" && parentID == "" {
+		return empty, nil
+	}
+	if traceID == "" || parentID == "" {
+		return empty, errInvalidScope
+	}
+
+	var scc trace.SpanContextConfig
+	lower, err := strconv.ParseUint(traceID, 10, 64)
+	if err != nil || lower == 0 {
+		return empty, errInvalidTraceIDHeader
+	}
+	binary.BigEndian.PutUint64(scc.TraceID[8:], lower)
+
+	span, err := strconv.ParseUint(parentID, 10, 64)
+	if err != nil || span == 0 {
+		return empty, errInvalidParentIDHeader
+	}
+	binary.BigEndian.PutUint64(scc.SpanID[:], span)
+
+	var state ddState
+	if priority != "" {
+		p, err := strconv.Atoi(priority)
+		if err != nil {
+			return empty, errInvalidPriorityHeader
+		}
+		state.priority, state.hasPriority = p, true
+		if p > 0 {
+			scc.TraceFlags = trace.FlagsSampled
+		}
+	}
+	state.origin = origin
+
+	if len(tags) <= maxTagsLength {
+		for part := range strings.SplitSeq(tags, ",") {
+			key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+			if !ok || !strings.HasPrefix(key, propagatedTagPrefix) {
+				continue
+			}
+			if key == traceIDUpperTag {
+				// An invalid tag only loses the upper bits of the trace ID.
+				_ = extractTraceIDUpper(value, &scc.TraceID)
+				continue
+			}
+			state.tags = append(state.tags, tag{
+				key:   strings.TrimPrefix(key, propagatedTagPrefix),
+				value: value,
+			})
+		}
+	}
+
+	if v := state.String(); v != "" {
+		// The state is dropped if it cannot be stored in the trace state,
+		// e.g. when it is too long.
+		if ts, err := scc.TraceState.Insert(traceStateKey, v); err == nil {
+			scc.TraceState = ts
+		}
+	}
+	return trace.NewSpanContext(scc), nil
+}
+
+// extractTraceIDUpper sets the upper 64 bits of traceID from the value of the
+// _dd.p.tid tag.
+func extractTraceIDUpper(value string, traceID *trace.TraceID) error {
+	if len(value) != 16 || strings.ToLower(value) != value {
+		return errInvalidTraceIDUpperTag
+	}
+	if _, err := hex.Decode(traceID[:8], []byte(value)); err != nil {
+		return errInvalidTraceIDUpperTag
+	}
+	return nil
+}
+
+// tag is a propagated tag, without its _dd.p. prefix.
+type tag struct {
+	key, value string
+}
+
+// ddState is the Datadog specific trace context stored in the "dd" member of
+// the trace state, encoded as the Datadog tracing libraries do:
+//
+//	s:{sampling priority};o:{origin};t.{tag key}:{tag value}
+type ddState struct {
+	priority    int
+	hasPriority bool
+	origin      string
+	tags        []tag
+}
+
+// samplingPriority returns the sampling priority of the state if it agrees
+// with the sampled flag, the default sampling priority otherwise.
+func (s ddState) samplingPriority(sampled bool) int {
+	if s.hasPriority && (s.priority > 0) == sampled {
+		return s.priority
+	}
+	if sampled {
+		return 1
+	}
+	return 0
+}
+
+// String returns the trace state member value encoding the state.
+func (s ddState) String() string {
+	var parts []string
+	if s.hasPriority {
+		parts = append(parts, "s:"+strconv.Itoa(s.priority))
+	}
+	if s.origin != "" {
+		parts = append(parts, "o:"+encodeValue(s.origin))
+	}
+	for _, t := range s.tags {
+		parts = append(parts, "t."+encodeKey(t.key)+":"+encodeValue(t.value))
+	}
+	return strings.Join(parts, ";")
+}
+
+// parseState decodes the trace state member value v. Unknown or invalid
+// members are ignored.
+func parseState(v string) ddState {
+	var s ddState
+	if v == "" {
+		return s
+	}
+	for part := range strings.SplitSeq(v, ";") {
+		key, value, ok := strings.Cut(part, ":")
+		if !ok {
+			continue
+		}
+		switch {
+		case key == "s":
+			if p, err := strconv.Atoi(value); err == nil {
+				s.priority, s.hasPriority = p, true
+			}
+		case key == "o":
+			s.origin = decodeValue(value)
+		case strings.HasPrefix(key, "t."):
+			s.tags = append(s.tags, tag{key: key[2:], value: decodeValue(value)})
+		}
+	}
+	return s
+}
+
+// encodeKey replaces the characters of key not allowed in the keys of the
+// state with '_'.
+func encodeKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r < 0x21 || r > 0x7e, r == ',', r == ';', r == ':', r == '=', r == '~':
+			return '_'
+		default:
+			return r
+		}
+	}, key)
+}
+
+// encodeValue replaces '=' with '~' and the other characters of value not
+// allowed in a trace state member value with '_'.
+func encodeValue(value string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '=':
+			return '~'
+		case r < 0x20 || r > 0x7e, r == ',', r == ';', r == '~':
+			return '_'
+		default:
+			return r
+		}
+	}, strings.TrimRight(value, " "))
+}
+
+// decodeValue reverts the replacement of '=' with '~' of encodeValue.
+func decodeValue(value string) string {
+	return strings.ReplaceAll(value, "~", "=")
+}
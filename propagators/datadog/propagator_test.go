@@ -0,0 +1,302 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package datadog
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	traceID64  = trace.TraceID{8: 0x7b, 15: 0x01}
+	traceID128 = trace.TraceID{0x64, 0xb7, 0x3c, 0x6a, 0, 0, 0, 0, 0x7b, 15: 0x01}
+	spanID     = trace.SpanID{7: 0x7b}
+
+	traceIDStr = "8863084066665136129"
+	spanIDStr  = "123"
+)
+
+func traceState(t *testing.T, dd string) trace.TraceState {
+	t.Helper()
+	ts, err := trace.TraceState{}.Insert(traceStateKey, dd)
+	require.NoError(t, err)
+	return ts
+}
+
+func TestDatadogExtract(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    trace.SpanContextConfig
+	}{
+		{
+			name: "sampled",
+			headers: map[string]string{
+				traceIDHeader:  traceIDStr,
+				parentIDHeader: spanIDStr,
+				priorityHeader: "1",
+			},
+			want: trace.SpanContextConfig{
+				TraceID:    traceID64,
+				SpanID:     spanID,
+				TraceFlags: trace.FlagsSampled,
+				TraceState: traceState(t, "s:1"),
+			},
+		},
+		{
+			name: "user rejected",
+			headers: map[string]string{
+				traceIDHeader:  traceIDStr,
+				parentIDHeader: spanIDStr,
+				priorityHeader: "-1",
+			},
+			want: trace.SpanContextConfig{
+				TraceID:    traceID64,
+				SpanID:     spanID,
+				TraceState: traceState(t, "s:-1"),
+			},
+		},
+		{
+			name: "no sampling priority",
+			headers: map[string]string{
+				traceIDHeader:  traceIDStr,
+				parentIDHeader: spanIDStr,
+			},
+			want: trace.SpanContextConfig{
+				TraceID: traceID64,
+				SpanID:  spanID,
+			},
+		},
+		{
+			name: "128-bit trace ID with tags and origin",
+			headers: map[string]string{
+				traceIDHeader:  traceIDStr,
+				parentIDHeader: spanIDStr,
+				priorityHeader: "2",
+				originHeader:   "synthetics",
+				tagsHeader:     "_dd.p.dm=-4,_dd.p.tid=64b73c6a00000000,other=ignored",
+			},
+			want: trace.SpanContextConfig{
+				TraceID:    traceID128,
+				SpanID:     spanID,
+				TraceFlags: trace.FlagsSampled,
+				TraceState: traceState(t, "s:2;o:synthetics;t.dm:-4"),
+			},
+		},
+		{
+			name: "invalid upper trace ID",
+			headers: map[string]string{
+				traceIDHeader:  traceIDStr,
+				parentIDHeader: spanIDStr,
+				priorityHeader: "1",
+				tagsHeader:     "_dd.p.tid=64B73C6A00000000",
+			},
+			want: trace.SpanContextConfig{
+				TraceID:    traceID64,
+				SpanID:     spanID,
+				TraceFlags: trace.FlagsSampled,
+				TraceState: traceState(t, "s:1"),
+			},
+		},
+		{
+			name: "tags too long",
+			headers: map[string]string{
+				traceIDHeader:  traceIDStr,
+				parentIDHeader: spanIDStr,
+				tagsHeader:     "_dd.p.tid=64b73c6a00000000,_dd.p.long=" + strings.Repeat("x", maxTagsLength),
+			},
+			want: trace.SpanContextConfig{
+				TraceID: traceID64,
+				SpanID:  spanID,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := Datadog{}.Extract(t.Context(), propagation.MapCarrier(tc.headers))
+			want := trace.NewSpanContext(tc.want).WithRemote(true)
+			assert.Equal(t, want, trace.SpanContextFromContext(ctx))
+		})
+	}
+}
+
+func TestDatadogExtractInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		err     error
+	}{
+		{
+			name:    "no headers",
+			headers: map[string]string{},
+		},
+		{
+			name:    "missing parent ID",
+			headers: map[string]string{traceIDHeader: traceIDStr},
+			err:     errInvalidScope,
+		},
+		{
+			name:    "missing trace ID",
+			headers: map[string]string{parentIDHeader: spanIDStr},
+			err:     errInvalidScope,
+		},
+		{
+			name:    "zero trace ID",
+			headers: map[string]string{traceIDHeader: "0", parentIDHeader: spanIDStr},
+			err:     errInvalidTraceIDHeader,
+		},
+		{
+			name:    "hexadecimal trace ID",
+			headers: map[string]string{traceIDHeader: "7b000000000001", parentIDHeader: spanIDStr},
+			err:     errInvalidTraceIDHeader,
+		},
+		{
+			name:    "overflowing parent ID",
+			headers: map[string]string{traceIDHeader: traceIDStr, parentIDHeader: "18446744073709551616"},
+			err:     errInvalidParentIDHeader,
+		},
+		{
+			name:    "zero parent ID",
+			headers: map[string]string{traceIDHeader: traceIDStr, parentIDHeader: "0"},
+			err:     errInvalidParentIDHeader,
+		},
+		{
+			name: "invalid sampling priority",
+			headers: map[string]string{
+				traceIDHeader:  traceIDStr,
+				parentIDHeader: spanIDStr,
+				priorityHeader: "keep",
+			},
+			err: errInvalidPriorityHeader,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			h := tc.headers
+			sc, err := extract(h[traceIDHeader], h[parentIDHeader], h[priorityHeader], h[originHeader], h[tagsHeader])
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, empty, sc)
+
+			ctx := Datadog{}.Extract(t.Context(), propagation.MapCarrier(tc.headers))
+			assert.False(t, trace.SpanContextFromContext(ctx).IsValid())
+		})
+	}
+}
+
+func TestDatadogInject(t *testing.T) {
+	tests := []struct {
+		name string
+		sc   trace.SpanContextConfig
+		want map[string]string
+	}{
+		{
+			name: "sampled",
+			sc: trace.SpanContextConfig{
+				TraceID:    traceID64,
+				SpanID:     spanID,
+				TraceFlags: trace.FlagsSampled,
+			},
+			want: map[string]string{
+				traceIDHeader:  traceIDStr,
+				parentIDHeader: spanIDStr,
+				priorityHeader: "1",
+			},
+		},
+		{
+			name: "not sampled 128-bit trace ID",
+			sc: trace.SpanContextConfig{
+				TraceID: traceID128,
+				SpanID:  spanID,
+			},
+			want: map[string]string{
+				traceIDHeader:  traceIDStr,
+				parentIDHeader: spanIDStr,
+				priorityHeader: "0",
+				tagsHeader:     "_dd.p.tid=64b73c6a00000000",
+			},
+		},
+		{
+			name: "Datadog trace state",
+			sc: trace.SpanContextConfig{
+				TraceID:    traceID128,
+				SpanID:     spanID,
+				TraceFlags: trace.FlagsSampled,
+				TraceState: traceState(t, "s:2;o:rum;t.dm:-4;t.usr.id:a~b;p:000000000000007b"),
+			},
+			want: map[string]string{
+				traceIDHeader:  traceIDStr,
+				parentIDHeader: spanIDStr,
+				priorityHeader: "2",
+				originHeader:   "rum",
+				tagsHeader:     "_dd.p.tid=64b73c6a00000000,_dd.p.dm=-4,_dd.p.usr.id=a=b",
+			},
+		},
+		{
+			name: "sampling priority disagreeing with the sampled flag",
+			sc: trace.SpanContextConfig{
+				TraceID:    traceID64,
+				SpanID:     spanID,
+				TraceState: traceState(t, "s:2"),
+			},
+			want: map[string]string{
+				traceIDHeader:  traceIDStr,
+				parentIDHeader: spanIDStr,
+				priorityHeader: "0",
+			},
+		},
+		{
+			name: "invalid span context",
+			sc:   trace.SpanContextConfig{TraceID: traceID64},
+			want: map[string]string{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := trace.ContextWithSpanContext(t.Context(), trace.NewSpanContext(tc.sc))
+			carrier := propagation.MapCarrier{}
+			Datadog{}.Inject(ctx, carrier)
+			assert.Equal(t, propagation.MapCarrier(tc.want), carrier)
+		})
+	}
+}
+
+func TestDatadogRoundTrip(t *testing.T) {
+	headers := propagation.MapCarrier{
+		traceIDHeader:  traceIDStr,
+		parentIDHeader: spanIDStr,
+		priorityHeader: "-1",
+		originHeader:   "synthetics;browser",
+		tagsHeader:     "_dd.p.tid=64b73c6a00000000,_dd.p.dm=-3,_dd.p.key=v=1",
+	}
+	ctx := Datadog{}.Extract(t.Context(), headers)
+
+	got := propagation.MapCarrier{}
+	Datadog{}.Inject(ctx, got)
+	headers[originHeader] = "synthetics_browser"
+	assert.Equal(t, headers, got)
+}
+
+func TestInjectTagsTooLong(t *testing.T) {
+	tags := []tag{{key: "long", value: strings.Repeat("x", maxTagsLength)}}
+	assert.Equal(t, "_dd.p.tid=64b73c6a00000000", injectTags(traceID128, tags))
+	assert.Empty(t, injectTags(traceID64, tags))
+}
+
+func TestDatadogFields(t *testing.T) {
+	assert.Equal(t, []string{
+		"x-datadog-trace-id",
+		"x-datadog-parent-id",
+		"x-datadog-sampling-priority",
+		"x-datadog-origin",
+		"x-datadog-tags",
+	}, Datadog{}.Fields())
+}
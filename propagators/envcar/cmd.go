@@ -0,0 +1,157 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package envcar
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope name of the spans started by Run.
+const ScopeName = "go.opentelemetry.io/contrib/propagators/envcar"
+
+type config struct {
+	propagator     propagation.TextMapPropagator
+	tracerProvider trace.TracerProvider
+}
+
+// extractPropagator is the default TextMapPropagator of Extract. The global
+// TextMapPropagator is usually not set yet when the context is extracted at
+// startup.
+var extractPropagator = propagation.NewCompositeTextMapPropagator(
+	propagation.TraceContext{}, propagation.Baggage{},
+)
+
+func newConfig(propagator propagation.TextMapPropagator, opts []Option) config {
+	cfg := config{propagator: propagator}
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+	if cfg.tracerProvider == nil {
+		cfg.tracerProvider = otel.GetTracerProvider()
+	}
+	return cfg
+}
+
+// Option configures Extract, Inject, and Run.
+type Option interface {
+	apply(*config)
+}
+
+type optionFunc func(*config)
+
+func (fn optionFunc) apply(c *config) {
+	fn(c)
+}
+
+// WithPropagator sets the TextMapPropagator used to extract and inject the
+// context. Inject and Run use the global TextMapPropagator by default.
+// Extract uses a composite of the W3C Trace Context and Baggage propagators
+// by default, as the global TextMapPropagator is usually not set yet when the
+// context is extracted at startup.
+func WithPropagator(p propagation.TextMapPropagator) Option {
+	return optionFunc(func(c *config) {
+		if p != nil {
+			c.propagator = p
+		}
+	})
+}
+
+// WithTracerProvider sets the TracerProvider used by Run to start the span of
+// the child process. The global TracerProvider is used by default.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return optionFunc(func(c *config) {
+		if tp != nil {
+			c.tracerProvider = tp
+		}
+	})
+}
+
+// Extract returns ctx with the context extracted from the current process
+// environment, e.g. the span context and baggage injected by the parent
+// process.
+//
+// It is meant to be called once, during startup, to get the root context of
+// the process:
+//
+//	ctx := envcar.Extract(context.Background())
+func Extract(ctx context.Context, opts ...Option) context.Context {
+	cfg := newConfig(extractPropagator, opts)
+	return cfg.propagator.Extract(ctx, &Carrier{})
+}
+
+// Inject injects the context of ctx, e.g. its span context and baggage, into
+// the environment of cmd. It must be called before cmd is started.
+//
+// If cmd.Env is nil, it is set to the default environment of cmd first, see
+// [exec.Cmd.Environ], so the child process still inherits it. The variables of
+// the propagator fields inherited from the current process are removed, so the
+// child process does not receive a stale context.
+func Inject(ctx context.Context, cmd *exec.Cmd, opts ...Option) {
+	cfg := newConfig(otel.GetTextMapPropagator(), opts)
+	inject(ctx, cmd, cfg.propagator)
+}
+
+func inject(ctx context.Context, cmd *exec.Cmd, prop propagation.TextMapPropagator) {
+	if cmd.Env == nil {
+		cmd.Env = cmd.Environ()
+	}
+
+	fields := make([]string, 0, len(prop.Fields()))
+	for _, f := range prop.Fields() {
+		fields = append(fields, normalize(f))
+	}
+	cmd.Env = slices.DeleteFunc(cmd.Env, func(kv string) bool {
+		key, _, _ := strings.Cut(kv, "=")
+		return slices.Contains(fields, key)
+	})
+
+	prop.Inject(ctx, &Carrier{
+		SetEnvFunc: func(key, value string) {
+			cmd.Env = append(cmd.Env, key+"="+value)
+		},
+	})
+}
+
+// Run runs cmd, as [exec.Cmd.Run], within a span lasting for the lifetime of
+// the child process. The context of the span is injected into the
+// environment of cmd, as with Inject, so the spans of the child process are
+// children of this span.
+//
+// The span is named after the executable of cmd and records its process ID
+// and exit code. Its status is set to error if cmd fails.
+func Run(ctx context.Context, cmd *exec.Cmd, opts ...Option) error {
+	cfg := newConfig(otel.GetTextMapPropagator(), opts)
+	tracer := cfg.tracerProvider.Tracer(ScopeName, trace.WithInstrumentationVersion(Version()))
+
+	name := filepath.Base(cmd.Path)
+	ctx, span := tracer.Start(ctx, name, trace.WithAttributes(
+		semconv.ProcessExecutableName(name),
+		semconv.ProcessExecutablePath(cmd.Path),
+	))
+	defer span.End()
+
+	inject(ctx, cmd, cfg.propagator)
+	err := cmd.Run()
+	if ps := cmd.ProcessState; ps != nil {
+		span.SetAttributes(
+			semconv.ProcessPID(ps.Pid()),
+			semconv.ProcessExitCode(ps.ExitCode()),
+		)
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
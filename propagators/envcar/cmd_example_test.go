@@ -0,0 +1,50 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package envcar_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+
+	"go.opentelemetry.io/contrib/propagators/envcar"
+)
+
+// An example of a command line tool continuing the trace of its parent
+// process, and propagating it to the command it runs.
+func ExampleRun() {
+	// Simulate the trace context set by the parent process.
+	orig, ok := os.LookupEnv("TRACEPARENT")
+	defer func() {
+		if ok {
+			_ = os.Setenv("TRACEPARENT", orig)
+			return
+		}
+		_ = os.Unsetenv("TRACEPARENT")
+	}()
+	_ = os.Setenv("TRACEPARENT", "00-0102030405060708090a0b0c0d0e0f10-0102030405060708-01")
+
+	// Extract the root context of the process once, during startup.
+	ctx := envcar.Extract(context.Background())
+
+	// Set the global TextMapPropagator, used by Run to inject the context.
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{},
+	))
+
+	// Run the command within a span, the trace context of the span is
+	// passed to the command. No span is recorded here as no TracerProvider
+	// is configured, so the command continues the trace of the parent
+	// process.
+	cmd := exec.CommandContext(ctx, "printenv", "TRACEPARENT")
+	cmd.Stdout = os.Stdout
+	if err := envcar.Run(ctx, cmd); err != nil {
+		fmt.Println("error:", err)
+	}
+	// Output: 00-0102030405060708090a0b0c0d0e0f10-0102030405060708-01
+}
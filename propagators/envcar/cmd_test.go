@@ -0,0 +1,174 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package envcar_test
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/contrib/propagators/envcar"
+)
+
+const (
+	traceparent = "00-000000000000007b00000000000001c8-000000000000007b-01"
+	childEnv    = "echo TRACEPARENT=$TRACEPARENT; echo TRACESTATE=$TRACESTATE; echo BAGGAGE=$BAGGAGE"
+)
+
+func parentContext(t *testing.T) trace.SpanContext {
+	t.Helper()
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	})
+}
+
+// setGlobalPropagator sets the global TextMapPropagator to p for the
+// duration of the test.
+func setGlobalPropagator(t *testing.T, p propagation.TextMapPropagator) {
+	t.Helper()
+	orig := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(p)
+	t.Cleanup(func() { otel.SetTextMapPropagator(orig) })
+}
+
+func TestExtract(t *testing.T) {
+	t.Setenv("TRACEPARENT", traceparent)
+	t.Setenv("BAGGAGE", "tenant=gold")
+	// The global TextMapPropagator is not used by default.
+	setGlobalPropagator(t, propagation.TraceContext{})
+
+	ctx := envcar.Extract(t.Context())
+	sc := trace.SpanContextFromContext(ctx)
+	assert.Equal(t, parentContext(t).WithRemote(true), sc)
+	assert.Equal(t, "gold", baggage.FromContext(ctx).Member("tenant").Value())
+
+	ctx = envcar.Extract(t.Context(), envcar.WithPropagator(prop))
+	assert.Equal(t, parentContext(t).WithRemote(true), trace.SpanContextFromContext(ctx))
+	assert.Equal(t, 0, baggage.FromContext(ctx).Len(), "baggage not extracted by the propagator")
+}
+
+func TestInject(t *testing.T) {
+	// Stale context inherited from the current process.
+	t.Setenv("TRACESTATE", "stale=1")
+	t.Setenv("BAGGAGE", "stale=1")
+
+	m, err := baggage.NewMember("tenant", "gold")
+	require.NoError(t, err)
+	b, err := baggage.New(m)
+	require.NoError(t, err)
+	ctx := trace.ContextWithSpanContext(t.Context(), parentContext(t))
+	ctx = baggage.ContextWithBaggage(ctx, b)
+
+	setGlobalPropagator(t, propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	cmd := exec.CommandContext(t.Context(), "sh", "-c", childEnv)
+	envcar.Inject(ctx, cmd)
+	require.NotNil(t, cmd.Env, "environment of the current process inherited")
+
+	out, err := cmd.Output()
+	require.NoError(t, err)
+	assert.Equal(t, "TRACEPARENT="+traceparent+"\nTRACESTATE=\nBAGGAGE=tenant=gold\n", string(out))
+}
+
+func TestInjectGlobalPropagator(t *testing.T) {
+	m, err := baggage.NewMember("tenant", "gold")
+	require.NoError(t, err)
+	b, err := baggage.New(m)
+	require.NoError(t, err)
+	ctx := trace.ContextWithSpanContext(t.Context(), parentContext(t))
+	ctx = baggage.ContextWithBaggage(ctx, b)
+
+	// The baggage is not propagated by the global TextMapPropagator.
+	setGlobalPropagator(t, propagation.TraceContext{})
+	cmd := exec.CommandContext(t.Context(), "true")
+	cmd.Env = []string{}
+	envcar.Inject(ctx, cmd)
+	assert.Equal(t, []string{"TRACEPARENT=" + traceparent}, cmd.Env)
+}
+
+func TestInjectKeepsEnv(t *testing.T) {
+	ctx := trace.ContextWithSpanContext(t.Context(), parentContext(t))
+	cmd := exec.CommandContext(t.Context(), "sh", "-c", "echo $CUSTOM $TRACEPARENT")
+	cmd.Env = []string{"CUSTOM=value", "TRACEPARENT=stale"}
+	envcar.Inject(ctx, cmd, envcar.WithPropagator(prop))
+
+	assert.Equal(t, []string{"CUSTOM=value", "TRACEPARENT=" + traceparent}, cmd.Env)
+	out, err := cmd.Output()
+	require.NoError(t, err)
+	assert.Equal(t, "value "+traceparent+"\n", string(out))
+}
+
+func TestInjectWorkingDirectory(t *testing.T) {
+	dir := t.TempDir()
+	ctx := trace.ContextWithSpanContext(t.Context(), parentContext(t))
+	cmd := exec.CommandContext(t.Context(), "printenv", "PWD")
+	cmd.Dir = dir
+	envcar.Inject(ctx, cmd, envcar.WithPropagator(prop))
+
+	out, err := cmd.Output()
+	require.NoError(t, err)
+	assert.Equal(t, dir+"\n", string(out), "PWD set to the working directory")
+}
+
+func TestRun(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	ctx := trace.ContextWithSpanContext(t.Context(), parentContext(t))
+	setGlobalPropagator(t, propagation.TraceContext{})
+
+	var out strings.Builder
+	cmd := exec.CommandContext(t.Context(), "sh", "-c", "echo $TRACEPARENT")
+	cmd.Stdout = &out
+	require.NoError(t, envcar.Run(ctx, cmd, envcar.WithTracerProvider(tp)))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "sh", span.Name())
+	assert.Equal(t, spanID, span.Parent().SpanID())
+	assert.Equal(t, envcar.ScopeName, span.InstrumentationScope().Name)
+	assert.Equal(t, codes.Unset, span.Status().Code)
+	assert.Contains(t, span.Attributes(), semconv.ProcessExecutableName("sh"))
+	assert.Contains(t, span.Attributes(), semconv.ProcessExitCode(0))
+	assert.Contains(t, span.Attributes(), semconv.ProcessPID(cmd.ProcessState.Pid()))
+
+	// The child process is a child of the span.
+	sc := span.SpanContext()
+	want := "00-" + sc.TraceID().String() + "-" + sc.SpanID().String() + "-01\n"
+	assert.Equal(t, want, out.String())
+}
+
+func TestRunError(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	cmd := exec.CommandContext(t.Context(), "sh", "-c", "exit 3")
+	err := envcar.Run(t.Context(), cmd, envcar.WithTracerProvider(tp))
+	var exitErr *exec.ExitError
+	require.ErrorAs(t, err, &exitErr)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Contains(t, spans[0].Attributes(), semconv.ProcessExitCode(3))
+
+	cmd = exec.CommandContext(t.Context(), "envcar-missing-executable")
+	require.Error(t, envcar.Run(t.Context(), cmd, envcar.WithTracerProvider(tp)))
+	spans = sr.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, "envcar-missing-executable", spans[1].Name())
+}
@@ -14,6 +14,14 @@
 // environment as a live parent-context source and may observe later environment
 // variable changes.
 //
+// [Extract] returns the context extracted from the process environment at
+// startup. [Inject] injects a context into the environment of an [exec.Cmd],
+// and [Run] runs an [exec.Cmd] within a span lasting for the lifetime of the
+// child process.
+// [Extract] uses the W3C Trace Context and Baggage propagators by default, as
+// the global TextMapPropagator is usually not set yet at startup. [Inject] and
+// [Run] use the global TextMapPropagator by default.
+//
 // Note that environment variables can be visible to code in the same process
 // and, on many systems, to other users or processes with sufficient
 // permissions. Do not use this carrier for sensitive context.
@@ -0,0 +1,9 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package autosampler provides an OpenTelemetry Sampler creation function
+// supporting the OTEL_TRACES_SAMPLER and OTEL_TRACES_SAMPLER_ARG environment
+// variables. Along with the samplers of the OpenTelemetry SDK, the samplers of
+// the opentelemetry-go-contrib project are supported, and other samplers can
+// be registered with RegisterSampler.
+package autosampler
@@ -0,0 +1,39 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package autosampler_test
+
+import (
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"go.opentelemetry.io/contrib/samplers/autosampler"
+)
+
+func ExampleNewSampler() {
+	// The sampler is configured with the OTEL_TRACES_SAMPLER and
+	// OTEL_TRACES_SAMPLER_ARG environment variables, e.g.
+	// OTEL_TRACES_SAMPLER=parentbased_consistent_threshold and
+	// OTEL_TRACES_SAMPLER_ARG=0.1.
+	sampler, err := autosampler.NewSampler()
+	if err != nil {
+		otel.Handle(err)
+	}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(sampler))
+	otel.SetTracerProvider(tp)
+}
+
+func ExampleRegisterSampler() {
+	autosampler.RegisterSampler("never", func(string) (sdktrace.Sampler, error) {
+		return sdktrace.NeverSample(), nil
+	})
+
+	_ = os.Setenv("OTEL_TRACES_SAMPLER", "never")
+	defer os.Unsetenv("OTEL_TRACES_SAMPLER")
+	sampler, _ := autosampler.NewSampler()
+	fmt.Println(sampler.Description())
+	// Output: AlwaysOffSampler
+}
@@ -0,0 +1,185 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package autosampler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+
+	"go.opentelemetry.io/contrib/samplers/jaegerremote"
+	"go.opentelemetry.io/contrib/samplers/probability/consistent"
+)
+
+var (
+	// errUnknownSampler is returned when an unknown sampler name is used in
+	// the OTEL_TRACES_SAMPLER environment variable.
+	errUnknownSampler = errors.New("unknown sampler")
+
+	// errInvalidSamplerArg is returned when the OTEL_TRACES_SAMPLER_ARG
+	// environment variable is invalid for the sampler.
+	errInvalidSamplerArg = errors.New("invalid sampler argument")
+
+	// errDuplicateRegistration is returned when an duplicate registration is
+	// detected.
+	errDuplicateRegistration = errors.New("duplicate registration")
+)
+
+// samplers is the registry of Sampler factories registered with this
+// package. It includes the OpenTelemetry defaults and the contrib samplers at
+// startup.
+var samplers = &registry{
+	names: map[string]func(string) (trace.Sampler, error){
+		"always_on":  func(string) (trace.Sampler, error) { return trace.AlwaysSample(), nil },
+		"always_off": func(string) (trace.Sampler, error) { return trace.NeverSample(), nil },
+		"traceidratio": func(arg string) (trace.Sampler, error) {
+			ratio, err := parseRatio(arg)
+			return trace.TraceIDRatioBased(ratio), err
+		},
+		"parentbased_always_on": func(string) (trace.Sampler, error) {
+			return trace.ParentBased(trace.AlwaysSample()), nil
+		},
+		"parentbased_always_off": func(string) (trace.Sampler, error) {
+			return trace.ParentBased(trace.NeverSample()), nil
+		},
+		"parentbased_traceidratio": func(arg string) (trace.Sampler, error) {
+			ratio, err := parseRatio(arg)
+			return trace.ParentBased(trace.TraceIDRatioBased(ratio)), err
+		},
+		"jaeger_remote": func(string) (trace.Sampler, error) {
+			remote := jaegerremote.New(serviceName())
+			return &closableSampler{Sampler: remote, remote: remote}, nil
+		},
+		"parentbased_jaeger_remote": func(string) (trace.Sampler, error) {
+			remote := jaegerremote.New(serviceName())
+			return &closableSampler{Sampler: trace.ParentBased(remote), remote: remote}, nil
+		},
+		"consistent_probability": func(arg string) (trace.Sampler, error) {
+			ratio, err := parseRatio(arg)
+			return consistent.ProbabilityBased(ratio), err
+		},
+		"parentbased_consistent_probability": func(arg string) (trace.Sampler, error) {
+			ratio, err := parseRatio(arg)
+			return consistent.ParentProbabilityBased(consistent.ProbabilityBased(ratio)), err
+		},
+		"consistent_threshold": func(arg string) (trace.Sampler, error) {
+			ratio, err := parseRatio(arg)
+			return consistent.ThresholdBased(ratio), err
+		},
+		"parentbased_consistent_threshold": func(arg string) (trace.Sampler, error) {
+			ratio, err := parseRatio(arg)
+			return consistent.ParentThresholdBased(consistent.ThresholdBased(ratio)), err
+		},
+	},
+}
+
+// closableSampler is a Sampler using a Jaeger remote sampler, implementing
+// io.Closer to stop the polling of the remote sampler and release its
+// connection.
+type closableSampler struct {
+	trace.Sampler
+	remote *jaegerremote.Sampler
+}
+
+var _ io.Closer = (*closableSampler)(nil)
+
+// Close closes the Jaeger remote sampler.
+func (s *closableSampler) Close() error {
+	s.remote.Close()
+	return nil
+}
+
+// parseRatio returns the sampling ratio of the sampler argument. A ratio of
+// 1 is returned along with an error if the argument is invalid, and without
+// an error if it is empty.
+func parseRatio(arg string) (float64, error) {
+	if arg == "" {
+		return 1, nil
+	}
+	ratio, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return 1, fmt.Errorf("%w: %w", errInvalidSamplerArg, err)
+	}
+	if ratio < 0 || ratio > 1 {
+		return 1, fmt.Errorf("%w: ratio %s out of the range [0, 1]", errInvalidSamplerArg, arg)
+	}
+	return ratio, nil
+}
+
+// serviceName returns the service name of the default resource, configured
+// with the OTEL_SERVICE_NAME or OTEL_RESOURCE_ATTRIBUTES environment
+// variables.
+func serviceName() string {
+	// Note: the default resource is only detected once, read the environment
+	// each time.
+	if name, ok := resource.Environment().Set().Value(semconv.ServiceNameKey); ok {
+		return name.AsString()
+	}
+	name, _ := resource.Default().Set().Value(semconv.ServiceNameKey)
+	return name.AsString()
+}
+
+// registry maintains a map of sampler names to Sampler factories that is safe
+// for concurrent use by multiple goroutines without additional locking or
+// coordination.
+type registry struct {
+	mu    sync.Mutex
+	names map[string]func(string) (trace.Sampler, error)
+}
+
+// load returns the Sampler created by the factory registered with the key,
+// called with arg. errUnknownSampler is returned if the registration is
+// missing, and the error of the factory if not nil.
+func (r *registry) load(key, arg string) (trace.Sampler, error) {
+	r.mu.Lock()
+	factory, ok := r.names[key]
+	r.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", errUnknownSampler, key)
+	}
+	return factory(arg)
+}
+
+// store sets the factory for a key if is not already in the registry.
+// errDuplicateRegistration is returned if the registry already contains key.
+func (r *registry) store(key string, factory func(string) (trace.Sampler, error)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.names == nil {
+		r.names = map[string]func(string) (trace.Sampler, error){key: factory}
+		return nil
+	}
+	if _, ok := r.names[key]; ok {
+		return fmt.Errorf("%w: %q", errDuplicateRegistration, key)
+	}
+	r.names[key] = factory
+	return nil
+}
+
+// drop removes key from the registry if it exists, otherwise nothing.
+func (r *registry) drop(key string) {
+	r.mu.Lock()
+	delete(r.names, key)
+	r.mu.Unlock()
+}
+
+// RegisterSampler sets the Sampler factory to be used when the
+// OTEL_TRACES_SAMPLER environment variable contains the sampler name. The
+// factory is called with the value of the OTEL_TRACES_SAMPLER_ARG environment
+// variable, empty if unset. This will panic if name has already been
+// registered or is a default.
+func RegisterSampler(name string, factory func(arg string) (trace.Sampler, error)) {
+	if err := samplers.store(name, factory); err != nil {
+		// Panic so the user is made aware of the duplicate registration,
+		// which could be done by malicious code trying to intercept the
+		// sampling decisions.
+		panic(err)
+	}
+}
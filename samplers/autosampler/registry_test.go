@@ -0,0 +1,119 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package autosampler
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+func alwaysOn(string) (trace.Sampler, error) {
+	return trace.AlwaysSample(), nil
+}
+
+func TestRegistryEmptyStore(t *testing.T) {
+	r := registry{}
+	assert.NotPanics(t, func() {
+		require.NoError(t, r.store("first", alwaysOn))
+	})
+}
+
+func TestRegistryEmptyLoad(t *testing.T) {
+	r := registry{}
+	assert.NotPanics(t, func() {
+		s, err := r.load("non-existent", "")
+		assert.ErrorIs(t, err, errUnknownSampler)
+		assert.Nil(t, s, "non-nil sampler returned")
+	})
+}
+
+func TestRegistryConcurrentSafe(t *testing.T) {
+	const samplerName = "sampler"
+
+	r := registry{}
+	assert.NotPanics(t, func() {
+		require.NoError(t, r.store(samplerName, alwaysOn))
+	})
+
+	var wg sync.WaitGroup
+
+	wg.Go(func() {
+		assert.NotPanics(t, func() {
+			require.ErrorIs(t, r.store(samplerName, alwaysOn), errDuplicateRegistration)
+		})
+	})
+
+	wg.Go(func() {
+		assert.NotPanics(t, func() {
+			s, err := r.load(samplerName, "")
+			assert.NoError(t, err)
+			assert.Equal(t, trace.AlwaysSample(), s, "wrong sampler returned")
+		})
+	})
+
+	wg.Wait()
+}
+
+func TestRegisterSampler(t *testing.T) {
+	const samplerName = "custom"
+	var gotArg string
+	RegisterSampler(samplerName, func(arg string) (trace.Sampler, error) {
+		gotArg = arg
+		return trace.NeverSample(), nil
+	})
+	t.Cleanup(func() { samplers.drop(samplerName) })
+
+	s, err := samplers.load(samplerName, "arg")
+	require.NoError(t, err)
+	assert.Equal(t, trace.NeverSample(), s, "wrong sampler stored")
+	assert.Equal(t, "arg", gotArg)
+}
+
+func TestDuplicateRegisterSamplerPanics(t *testing.T) {
+	const samplerName = "custom"
+	RegisterSampler(samplerName, alwaysOn)
+	t.Cleanup(func() { samplers.drop(samplerName) })
+
+	errString := fmt.Sprintf("%s: %q", errDuplicateRegistration, samplerName)
+	assert.PanicsWithError(t, errString, func() {
+		RegisterSampler(samplerName, alwaysOn)
+	})
+}
+
+func TestRegisterDefaultSamplerPanics(t *testing.T) {
+	for _, name := range []string{"always_on", "traceidratio", "jaeger_remote", "consistent_threshold"} {
+		assert.Panics(t, func() { RegisterSampler(name, alwaysOn) }, name)
+	}
+}
+
+func TestParseRatio(t *testing.T) {
+	for _, tc := range []struct {
+		arg     string
+		want    float64
+		wantErr bool
+	}{
+		{"", 1, false},
+		{"0", 0, false},
+		{"0.25", 0.25, false},
+		{"1", 1, false},
+		{"abc", 1, true},
+		{"-0.1", 1, true},
+		{"1.5", 1, true},
+	} {
+		t.Run(tc.arg, func(t *testing.T) {
+			ratio, err := parseRatio(tc.arg)
+			assert.Equal(t, tc.want, ratio)
+			if tc.wantErr {
+				assert.ErrorIs(t, err, errInvalidSamplerArg)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package autosampler
+
+import (
+	"os"
+
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	// otelTracesSamplerEnvKey is the environment variable name identifying
+	// the sampler to use.
+	otelTracesSamplerEnvKey = "OTEL_TRACES_SAMPLER"
+	// otelTracesSamplerArgEnvKey is the environment variable name of the
+	// argument of the sampler.
+	otelTracesSamplerArgEnvKey = "OTEL_TRACES_SAMPLER_ARG"
+)
+
+type config struct {
+	fallback trace.Sampler
+}
+
+// Option applies an autosampler configuration option.
+type Option interface {
+	apply(cfg *config)
+}
+
+type optionFunc func(cfg *config)
+
+func (fn optionFunc) apply(cfg *config) {
+	fn(cfg)
+}
+
+// WithFallbackSampler sets the fallback sampler to use when no sampler is
+// configured through the OTEL_TRACES_SAMPLER environment variable.
+func WithFallbackSampler(sampler trace.Sampler) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.fallback = sampler
+	})
+}
+
+// NewSampler returns a configured [go.opentelemetry.io/otel/sdk/trace.Sampler]
+// defined using the environment variables described below.
+//
+// OTEL_TRACES_SAMPLER defines the sampler; supported values:
+//   - "always_on" - [trace.AlwaysSample]
+//   - "always_off" - [trace.NeverSample]
+//   - "traceidratio" - [trace.TraceIDRatioBased]
+//   - "parentbased_always_on" (default) - [trace.ParentBased] with an
+//     [trace.AlwaysSample] root sampler
+//   - "parentbased_always_off" - [trace.ParentBased] with a
+//     [trace.NeverSample] root sampler
+//   - "parentbased_traceidratio" - [trace.ParentBased] with a
+//     [trace.TraceIDRatioBased] root sampler
+//   - "jaeger_remote" - Jaeger remote sampler; see
+//     [go.opentelemetry.io/contrib/samplers/jaegerremote]
+//   - "parentbased_jaeger_remote" - [trace.ParentBased] with a Jaeger remote
+//     root sampler
+//   - "consistent_probability" - power-of-two consistent probability sampler;
+//     see [go.opentelemetry.io/contrib/samplers/probability/consistent.ProbabilityBased]
+//   - "parentbased_consistent_probability" - see
+//     [go.opentelemetry.io/contrib/samplers/probability/consistent.ParentProbabilityBased]
+//   - "consistent_threshold" - threshold consistent probability sampler; see
+//     [go.opentelemetry.io/contrib/samplers/probability/consistent.ThresholdBased]
+//   - "parentbased_consistent_threshold" - see
+//     [go.opentelemetry.io/contrib/samplers/probability/consistent.ParentThresholdBased]
+//
+// OTEL_TRACES_SAMPLER_ARG defines the argument of the sampler. It is the
+// sampling ratio, in the interval [0, 1], of the ratio, probability and
+// threshold samplers, 1 if unset. The Jaeger remote samplers read their
+// comma-separated key=value arguments, e.g. endpoint, from it.
+//
+// The service name of the Jaeger remote samplers is the service name defined
+// by the OTEL_SERVICE_NAME or OTEL_RESOURCE_ATTRIBUTES environment variables.
+// The Jaeger remote samplers poll the sampling strategies in the background:
+// the returned sampler implements [io.Closer], close it when shutting down to
+// stop the polling and release its connection.
+//
+// An error is returned if an environment value is set to an unhandled value.
+// If OTEL_TRACES_SAMPLER_ARG is an invalid ratio, the sampler with a ratio of
+// 1 is returned along with the error.
+//
+// Use [RegisterSampler] to handle more values of OTEL_TRACES_SAMPLER.
+//
+// Use [WithFallbackSampler] option to change the returned sampler when
+// OTEL_TRACES_SAMPLER is unset or empty.
+func NewSampler(opts ...Option) (trace.Sampler, error) {
+	var cfg config
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+
+	name := os.Getenv(otelTracesSamplerEnvKey)
+	if name == "" {
+		if cfg.fallback != nil {
+			return cfg.fallback, nil
+		}
+		name = "parentbased_always_on"
+	}
+
+	return samplers.load(name, os.Getenv(otelTracesSamplerArgEnvKey))
+}
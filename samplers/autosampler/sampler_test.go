@@ -0,0 +1,100 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package autosampler
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestNewSampler(t *testing.T) {
+	for _, tc := range []struct {
+		sampler string
+		arg     string
+		want    string
+	}{
+		{"", "", "ParentBased{root:AlwaysOnSampler,remoteParentSampled:AlwaysOnSampler,remoteParentNotSampled:AlwaysOffSampler,localParentSampled:AlwaysOnSampler,localParentNotSampled:AlwaysOffSampler}"},
+		{"always_on", "", "AlwaysOnSampler"},
+		{"always_off", "", "AlwaysOffSampler"},
+		{"traceidratio", "0.25", "TraceIDRatioBased{0.25}"},
+		{"traceidratio", "", "TraceIDRatioBased{1}"},
+		{"parentbased_always_off", "", "ParentBased{root:AlwaysOffSampler,remoteParentSampled:AlwaysOnSampler,remoteParentNotSampled:AlwaysOffSampler,localParentSampled:AlwaysOnSampler,localParentNotSampled:AlwaysOffSampler}"},
+		{"parentbased_traceidratio", "0.5", "ParentBased{root:TraceIDRatioBased{0.5},remoteParentSampled:AlwaysOnSampler,remoteParentNotSampled:AlwaysOffSampler,localParentSampled:AlwaysOnSampler,localParentNotSampled:AlwaysOffSampler}"},
+		{"consistent_probability", "0.5", "ProbabilityBased{0.5}"},
+		{"parentbased_consistent_probability", "0.5", "ParentProbabilityBased{root:ProbabilityBased{0.5},remoteParentSampled:AlwaysOnSampler,remoteParentNotSampled:AlwaysOffSampler,localParentSampled:AlwaysOnSampler,localParentNotSampled:AlwaysOffSampler}"},
+		{"consistent_threshold", "0.1", "ThresholdBased{0.1}"},
+		{"parentbased_consistent_threshold", "0.1", "ParentThresholdBased{root:ThresholdBased{0.1},remoteParentSampled:AlwaysOnSampler,remoteParentNotSampled:AlwaysOffSampler,localParentSampled:AlwaysOnSampler,localParentNotSampled:AlwaysOffSampler}"},
+	} {
+		t.Run(tc.sampler, func(t *testing.T) {
+			t.Setenv(otelTracesSamplerEnvKey, tc.sampler)
+			t.Setenv(otelTracesSamplerArgEnvKey, tc.arg)
+
+			s, err := NewSampler()
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, s.Description())
+		})
+	}
+}
+
+func TestNewSamplerJaegerRemote(t *testing.T) {
+	t.Setenv(otelTracesSamplerArgEnvKey, "endpoint=http://localhost:1/sampling,pollingIntervalMs=60000")
+
+	t.Run("jaeger_remote", func(t *testing.T) {
+		t.Setenv(otelTracesSamplerEnvKey, "jaeger_remote")
+		s, err := NewSampler()
+		require.NoError(t, err)
+		assert.Equal(t, "JaegerRemoteSampler{}", s.Description())
+		require.Implements(t, (*io.Closer)(nil), s)
+		assert.NoError(t, s.(io.Closer).Close())
+	})
+
+	t.Run("parentbased_jaeger_remote", func(t *testing.T) {
+		t.Setenv(otelTracesSamplerEnvKey, "parentbased_jaeger_remote")
+		s, err := NewSampler()
+		require.NoError(t, err)
+		assert.Contains(t, s.Description(), "ParentBased{root:JaegerRemoteSampler{}")
+		require.Implements(t, (*io.Closer)(nil), s)
+		assert.NoError(t, s.(io.Closer).Close())
+	})
+}
+
+func TestNewSamplerFallback(t *testing.T) {
+	fallback := trace.TraceIDRatioBased(0.1)
+
+	t.Setenv(otelTracesSamplerEnvKey, "")
+	s, err := NewSampler(WithFallbackSampler(fallback))
+	require.NoError(t, err)
+	assert.Equal(t, fallback, s)
+
+	// The environment takes precedence over the fallback.
+	t.Setenv(otelTracesSamplerEnvKey, "always_off")
+	s, err = NewSampler(WithFallbackSampler(fallback))
+	require.NoError(t, err)
+	assert.Equal(t, trace.NeverSample(), s)
+}
+
+func TestNewSamplerUnknown(t *testing.T) {
+	t.Setenv(otelTracesSamplerEnvKey, "unknown")
+	s, err := NewSampler()
+	assert.ErrorIs(t, err, errUnknownSampler)
+	assert.Nil(t, s)
+}
+
+func TestNewSamplerInvalidArg(t *testing.T) {
+	t.Setenv(otelTracesSamplerEnvKey, "traceidratio")
+	t.Setenv(otelTracesSamplerArgEnvKey, "2")
+	s, err := NewSampler()
+	assert.ErrorIs(t, err, errInvalidSamplerArg)
+	// The ratio 1 is used for invalid arguments.
+	assert.Equal(t, "TraceIDRatioBased{1}", s.Description())
+}
+
+func TestServiceName(t *testing.T) {
+	t.Setenv("OTEL_SERVICE_NAME", "my-service")
+	assert.Equal(t, "my-service", serviceName())
+}
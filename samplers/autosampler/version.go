@@ -0,0 +1,7 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package autosampler
+
+// Version is the current release version of the autosampler module.
+const Version = "0.37.2"
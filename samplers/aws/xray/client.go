@@ -0,0 +1,153 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package xray
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// samplingRule is a sampling rule returned by the GetSamplingRules API.
+type samplingRule struct {
+	RuleName      string            `json:"RuleName"`
+	RuleARN       string            `json:"RuleARN"`
+	Priority      int64             `json:"Priority"`
+	FixedRate     float64           `json:"FixedRate"`
+	ReservoirSize int64             `json:"ReservoirSize"`
+	ServiceName   string            `json:"ServiceName"`
+	ServiceType   string            `json:"ServiceType"`
+	Host          string            `json:"Host"`
+	HTTPMethod    string            `json:"HTTPMethod"`
+	URLPath       string            `json:"URLPath"`
+	ResourceARN   string            `json:"ResourceARN"`
+	Attributes    map[string]string `json:"Attributes"`
+	Version       int64             `json:"Version"`
+}
+
+type samplingRuleRecord struct {
+	SamplingRule *samplingRule `json:"SamplingRule"`
+}
+
+type getSamplingRulesInput struct {
+	NextToken *string `json:"NextToken"`
+}
+
+type getSamplingRulesOutput struct {
+	SamplingRuleRecords []*samplingRuleRecord `json:"SamplingRuleRecords"`
+	NextToken           *string               `json:"NextToken"`
+}
+
+// samplingStatisticsDocument holds the statistics of a sampling rule sent
+// with the GetSamplingTargets API.
+type samplingStatisticsDocument struct {
+	ClientID     string  `json:"ClientID"`
+	RuleName     string  `json:"RuleName"`
+	RequestCount int64   `json:"RequestCount"`
+	SampledCount int64   `json:"SampledCount"`
+	BorrowCount  int64   `json:"BorrowCount"`
+	Timestamp    float64 `json:"Timestamp"`
+}
+
+type getSamplingTargetsInput struct {
+	SamplingStatisticsDocuments []*samplingStatisticsDocument `json:"SamplingStatisticsDocuments"`
+}
+
+// samplingTargetDocument is the sampling target of a rule returned by the
+// GetSamplingTargets API.
+type samplingTargetDocument struct {
+	RuleName          string   `json:"RuleName"`
+	FixedRate         float64  `json:"FixedRate"`
+	ReservoirQuota    *int64   `json:"ReservoirQuota"`
+	ReservoirQuotaTTL *float64 `json:"ReservoirQuotaTTL"`
+	Interval          *int64   `json:"Interval"`
+}
+
+type unprocessedStatistic struct {
+	ErrorCode string `json:"ErrorCode"`
+	Message   string `json:"Message"`
+	RuleName  string `json:"RuleName"`
+}
+
+type getSamplingTargetsOutput struct {
+	SamplingTargetDocuments []*samplingTargetDocument `json:"SamplingTargetDocuments"`
+	LastRuleModification    *float64                  `json:"LastRuleModification"`
+	UnprocessedStatistics   []*unprocessedStatistic   `json:"UnprocessedStatistics"`
+}
+
+// xrayClient calls the X-Ray sampling APIs of an endpoint proxying the X-Ray
+// service, like the X-Ray daemon.
+type xrayClient struct {
+	httpClient         *http.Client
+	samplingRulesURL   string
+	samplingTargetsURL string
+}
+
+func newClient(endpoint url.URL) *xrayClient {
+	return &xrayClient{
+		httpClient:         &http.Client{Timeout: defaultRequestTimeout},
+		samplingRulesURL:   endpoint.JoinPath("GetSamplingRules").String(),
+		samplingTargetsURL: endpoint.JoinPath("SamplingTargets").String(),
+	}
+}
+
+// getSamplingRules returns all the sampling rules, fetching every page.
+func (c *xrayClient) getSamplingRules(ctx context.Context) ([]*samplingRule, error) {
+	var (
+		rules []*samplingRule
+		input getSamplingRulesInput
+	)
+	for {
+		var output getSamplingRulesOutput
+		if err := c.post(ctx, c.samplingRulesURL, &input, &output); err != nil {
+			return nil, fmt.Errorf("failed to get sampling rules: %w", err)
+		}
+		for _, record := range output.SamplingRuleRecords {
+			if record != nil && record.SamplingRule != nil {
+				rules = append(rules, record.SamplingRule)
+			}
+		}
+		if output.NextToken == nil || *output.NextToken == "" {
+			return rules, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+// getSamplingTargets sends the statistics of the sampling rules and returns
+// the sampling targets of this client.
+func (c *xrayClient) getSamplingTargets(ctx context.Context, statistics []*samplingStatisticsDocument) (*getSamplingTargetsOutput, error) {
+	input := getSamplingTargetsInput{SamplingStatisticsDocuments: statistics}
+	var output getSamplingTargetsOutput
+	if err := c.post(ctx, c.samplingTargetsURL, &input, &output); err != nil {
+		return nil, fmt.Errorf("failed to get sampling targets: %w", err)
+	}
+	return &output, nil
+}
+
+func (c *xrayClient) post(ctx context.Context, url string, input, output any) error {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(output)
+}
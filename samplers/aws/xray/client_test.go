@@ -0,0 +1,114 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package xray
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientGetSamplingRulesPages(t *testing.T) {
+	var tokens []*string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/xray/GetSamplingRules", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		var in getSamplingRulesInput
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&in))
+		tokens = append(tokens, in.NextToken)
+
+		out := getSamplingRulesOutput{
+			SamplingRuleRecords: []*samplingRuleRecord{{SamplingRule: &samplingRule{RuleName: "first"}}},
+		}
+		if in.NextToken != nil {
+			out.SamplingRuleRecords = []*samplingRuleRecord{{SamplingRule: &samplingRule{RuleName: "second"}}, {}}
+		} else {
+			next := "next"
+			out.NextToken = &next
+		}
+		_ = json.NewEncoder(w).Encode(out)
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL + "/xray")
+	require.NoError(t, err)
+	rules, err := newClient(*u).getSamplingRules(t.Context())
+	require.NoError(t, err)
+
+	require.Len(t, rules, 2)
+	assert.Equal(t, "first", rules[0].RuleName)
+	assert.Equal(t, "second", rules[1].RuleName)
+	require.Len(t, tokens, 2)
+	assert.Nil(t, tokens[0])
+	assert.Equal(t, "next", *tokens[1])
+}
+
+func TestClientGetSamplingTargets(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/SamplingTargets", r.URL.Path)
+		var in map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&in))
+		assert.Equal(t, map[string]any{
+			"SamplingStatisticsDocuments": []any{map[string]any{
+				"ClientID":     "client",
+				"RuleName":     "rule",
+				"RequestCount": 3.0,
+				"SampledCount": 2.0,
+				"BorrowCount":  1.0,
+				"Timestamp":    1000.5,
+			}},
+		}, in)
+		_, _ = w.Write([]byte(`{
+  "SamplingTargetDocuments": [
+    {"RuleName": "rule", "FixedRate": 0.1, "ReservoirQuota": 5, "ReservoirQuotaTTL": 1001.5, "Interval": 10}
+  ],
+  "LastRuleModification": 999,
+  "UnprocessedStatistics": []
+}`))
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	out, err := newClient(*u).getSamplingTargets(t.Context(), []*samplingStatisticsDocument{{
+		ClientID:     "client",
+		RuleName:     "rule",
+		RequestCount: 3,
+		SampledCount: 2,
+		BorrowCount:  1,
+		Timestamp:    1000.5,
+	}})
+	require.NoError(t, err)
+
+	require.Len(t, out.SamplingTargetDocuments, 1)
+	target := out.SamplingTargetDocuments[0]
+	assert.Equal(t, "rule", target.RuleName)
+	assert.Equal(t, 0.1, target.FixedRate)
+	assert.Equal(t, int64(5), *target.ReservoirQuota)
+	assert.Equal(t, 1001.5, *target.ReservoirQuotaTTL)
+	assert.Equal(t, int64(10), *target.Interval)
+	assert.Equal(t, 999.0, *out.LastRuleModification)
+}
+
+func TestClientErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	c := newClient(*u)
+
+	_, err = c.getSamplingRules(t.Context())
+	assert.ErrorContains(t, err, "failed to get sampling rules: unexpected status code 503")
+	_, err = c.getSamplingTargets(t.Context(), nil)
+	assert.ErrorContains(t, err, "failed to get sampling targets: unexpected status code 503")
+}
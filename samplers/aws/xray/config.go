@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package xray
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+const (
+	defaultRequestTimeout               = 10 * time.Second
+	defaultSamplingRulesPollingInterval = 5 * time.Minute
+	defaultSamplingTargetsInterval      = 10 * time.Second
+	// rulesExpiration is the duration after which the rules are considered
+	// stale, when they fail to be refreshed, and the local rules are used.
+	rulesExpiration = time.Hour
+)
+
+type config struct {
+	endpoint                     url.URL
+	samplingRulesPollingInterval time.Duration
+	serviceType                  string
+	resourceARN                  string
+	localRules                   LocalSamplingRules
+	logger                       logr.Logger
+}
+
+// newConfig returns an appropriately configured config.
+func newConfig(options ...Option) config {
+	c := config{
+		endpoint: url.URL{
+			Scheme: "http",
+			Host:   "127.0.0.1:2000",
+		},
+		samplingRulesPollingInterval: defaultSamplingRulesPollingInterval,
+		localRules:                   DefaultLocalSamplingRules(),
+		logger:                       logr.Discard(),
+	}
+	for _, option := range options {
+		option.apply(&c)
+	}
+	return c
+}
+
+// Option applies configuration settings to a Sampler.
+type Option interface {
+	apply(*config)
+}
+
+type optionFunc func(*config)
+
+func (fn optionFunc) apply(c *config) {
+	fn(c)
+}
+
+// WithEndpoint creates an Option that sets the endpoint serving the X-Ray
+// GetSamplingRules and GetSamplingTargets APIs, at the GetSamplingRules and
+// SamplingTargets paths, like the X-Ray daemon. The default is
+// http://127.0.0.1:2000.
+func WithEndpoint(endpoint url.URL) Option {
+	return optionFunc(func(c *config) {
+		c.endpoint = endpoint
+	})
+}
+
+// WithSamplingRulesPollingInterval creates an Option that sets how often the
+// sampler polls the sampling rules. The default is 5 minutes.
+func WithSamplingRulesPollingInterval(interval time.Duration) Option {
+	return optionFunc(func(c *config) {
+		c.samplingRulesPollingInterval = interval
+	})
+}
+
+// WithServiceType creates an Option that sets the service type matched by the
+// sampling rules, e.g. AWS::EC2::Instance or AWS::ECS::Container.
+func WithServiceType(serviceType string) Option {
+	return optionFunc(func(c *config) {
+		c.serviceType = serviceType
+	})
+}
+
+// WithResourceARN creates an Option that sets the ARN of the AWS resource
+// running the service, matched by the sampling rules. The cloud.resource_id
+// attribute of a span takes precedence over it.
+func WithResourceARN(arn string) Option {
+	return optionFunc(func(c *config) {
+		c.resourceARN = arn
+	})
+}
+
+// WithLocalSamplingRules creates an Option that sets the sampling rules used
+// until the sampling rules are fetched, or when they could not be refreshed
+// for an hour. The default is [DefaultLocalSamplingRules].
+func WithLocalSamplingRules(rules LocalSamplingRules) Option {
+	return optionFunc(func(c *config) {
+		c.localRules = rules
+	})
+}
+
+// WithLogger configures the sampler to log operation and debug information with logger.
+func WithLogger(logger logr.Logger) Option {
+	return optionFunc(func(c *config) {
+		c.logger = logger
+	})
+}
@@ -0,0 +1,13 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package xray provides a Sampler applying the AWS X-Ray centralized
+// sampling rules.
+//
+// The sampling rules are polled with the GetSamplingRules API, and the
+// reservoir quotas and fixed rates assigned to this client are polled with
+// the GetSamplingTargets API, from an endpoint proxying the X-Ray service,
+// e.g. the X-Ray daemon or the OpenTelemetry Collector awsproxy extension.
+// Until the rules are fetched, or when they have not been refreshed for an
+// hour, the local sampling rules are used instead.
+package xray
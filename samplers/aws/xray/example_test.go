@@ -0,0 +1,34 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package xray_test
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"go.opentelemetry.io/contrib/samplers/aws/xray"
+)
+
+func ExampleNew() {
+	sampler, err := xray.New(
+		"your-service-name",
+		// The X-Ray daemon, or a local stand-in, serving the sampling APIs.
+		xray.WithEndpoint(url.URL{Scheme: "http", Host: "localhost:2000"}),
+		xray.WithSamplingRulesPollingInterval(time.Minute),
+		xray.WithServiceType("AWS::EC2::Instance"),
+	)
+	if err != nil {
+		otel.Handle(err)
+		return
+	}
+	defer sampler.Close()
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.ParentBased(sampler)))
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+	otel.SetTracerProvider(tp)
+}
@@ -0,0 +1,84 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package xray
+
+import (
+	"errors"
+	"fmt"
+)
+
+var errInvalidLocalRule = errors.New("invalid local sampling rule")
+
+// LocalSamplingRules are sampling rules applied without the X-Ray service.
+// They have the JSON encoding of the version 2 of the local sampling rules
+// files of the X-Ray SDKs.
+type LocalSamplingRules struct {
+	// Rules are matched in order, the first matching rule samples the span.
+	Rules []LocalSamplingRule `json:"rules"`
+	// Default samples the spans not matched by any rule. Its Host,
+	// HTTPMethod and URLPath are ignored.
+	Default LocalSamplingRule `json:"default"`
+}
+
+// LocalSamplingRule is a local sampling rule.
+type LocalSamplingRule struct {
+	Description string `json:"description"`
+	// Host, HTTPMethod and URLPath are the case-insensitive patterns matched
+	// by the rule, where '*' matches any sequence of characters and '?'
+	// matches a single character. An empty pattern matches anything.
+	Host       string `json:"host"`
+	HTTPMethod string `json:"http_method"`
+	URLPath    string `json:"url_path"`
+	// FixedTarget is the number of spans sampled each second before the
+	// Rate applies.
+	FixedTarget int64 `json:"fixed_target"`
+	// Rate is the fraction, in the range [0, 1], of the spans sampled once
+	// the FixedTarget is reached.
+	Rate float64 `json:"rate"`
+}
+
+// DefaultLocalSamplingRules returns the default local sampling rules of the
+// X-Ray SDKs, sampling the first span of each second and 5% of the other
+// spans.
+func DefaultLocalSamplingRules() LocalSamplingRules {
+	return LocalSamplingRules{
+		Default: LocalSamplingRule{
+			FixedTarget: 1,
+			Rate:        0.05,
+		},
+	}
+}
+
+func (r LocalSamplingRule) validate() error {
+	if r.FixedTarget < 0 {
+		return fmt.Errorf("%w %q: negative fixed target %d", errInvalidLocalRule, r.Description, r.FixedTarget)
+	}
+	if r.Rate < 0 || r.Rate > 1 {
+		return fmt.Errorf("%w %q: rate %g out of the range [0, 1]", errInvalidLocalRule, r.Description, r.Rate)
+	}
+	return nil
+}
+
+// newLocalRules returns the rules of the local sampling rules, ending with the
+// default rule.
+func newLocalRules(rules LocalSamplingRules) ([]*rule, error) {
+	var errs []error
+	local := make([]*rule, 0, len(rules.Rules)+1)
+	for _, r := range rules.Rules {
+		if err := r.validate(); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		local = append(local, newLocalRule(r))
+	}
+
+	def := rules.Default
+	def.Host, def.HTTPMethod, def.URLPath = "", "", ""
+	if err := def.validate(); err != nil {
+		errs = append(errs, err)
+		def = DefaultLocalSamplingRules().Default
+	}
+	local = append(local, newLocalRule(def))
+	return local, errors.Join(errs...)
+}
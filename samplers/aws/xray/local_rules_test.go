@@ -0,0 +1,81 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package xray
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalSamplingRulesJSON(t *testing.T) {
+	data := `{
+  "version": 2,
+  "rules": [
+    {
+      "description": "Player moves.",
+      "host": "*",
+      "http_method": "*",
+      "url_path": "/api/move/*",
+      "fixed_target": 0,
+      "rate": 0.05
+    }
+  ],
+  "default": {
+    "fixed_target": 1,
+    "rate": 0.1
+  }
+}`
+	var rules LocalSamplingRules
+	require.NoError(t, json.Unmarshal([]byte(data), &rules))
+	assert.Equal(t, LocalSamplingRules{
+		Rules: []LocalSamplingRule{{
+			Description: "Player moves.",
+			Host:        "*",
+			HTTPMethod:  "*",
+			URLPath:     "/api/move/*",
+			FixedTarget: 0,
+			Rate:        0.05,
+		}},
+		Default: LocalSamplingRule{FixedTarget: 1, Rate: 0.1},
+	}, rules)
+}
+
+func TestNewLocalRules(t *testing.T) {
+	rules, err := newLocalRules(LocalSamplingRules{
+		Rules: []LocalSamplingRule{
+			{Description: "health", URLPath: "/health", Rate: 0},
+		},
+		Default: LocalSamplingRule{Host: "ignored", FixedTarget: 2, Rate: 0.5},
+	})
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	assert.Equal(t, "/health", rules[0].URLPath)
+	assert.Equal(t, "*", rules[0].Host)
+
+	def := rules[1]
+	assert.Equal(t, "*", def.Host)
+	assert.Equal(t, int64(2), def.reservoir.quota)
+	assert.Equal(t, 0.5, def.fixedRate)
+}
+
+func TestNewLocalRulesInvalid(t *testing.T) {
+	rules, err := newLocalRules(LocalSamplingRules{
+		Rules: []LocalSamplingRule{
+			{Description: "negative", FixedTarget: -1},
+			{Description: "rate", Rate: 1.5},
+		},
+		Default: LocalSamplingRule{Rate: -0.1},
+	})
+	assert.ErrorIs(t, err, errInvalidLocalRule)
+	assert.ErrorContains(t, err, `"negative": negative fixed target -1`)
+	assert.ErrorContains(t, err, `"rate": rate 1.5 out of the range [0, 1]`)
+
+	// The invalid default rule is replaced with the default.
+	require.Len(t, rules, 1)
+	assert.Equal(t, int64(1), rules[0].reservoir.quota)
+	assert.Equal(t, 0.05, rules[0].fixedRate)
+}
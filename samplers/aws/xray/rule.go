@@ -0,0 +1,249 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package xray
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// samplingRequest holds the properties of a span matched by the sampling
+// rules.
+type samplingRequest struct {
+	serviceName string
+	serviceType string
+	resourceARN string
+	host        string
+	httpMethod  string
+	urlPath     string
+	attributes  []attribute.KeyValue
+}
+
+// reservoir limits the number of spans sampled each second by a rule.
+type reservoir struct {
+	// quota is the number of spans sampled per second.
+	quota int64
+	// expiring is whether the quota expires at expiresAt. The quota of the
+	// local rules never expires.
+	expiring  bool
+	expiresAt time.Time
+	// borrow is whether a span per second is sampled when the quota is
+	// expired, borrowed from the reservoir of the rule shared by all the
+	// clients.
+	borrow bool
+
+	currentSecond int64
+	used          int64
+}
+
+// expired reports whether the quota is expired at now, or not assigned yet.
+func (r *reservoir) expired(now time.Time) bool {
+	return r.expiring && !now.Before(r.expiresAt)
+}
+
+// take reports whether a span is sampled by the reservoir at now, and whether
+// it is borrowed.
+func (r *reservoir) take(now time.Time) (ok, borrowed bool) {
+	if second := now.Unix(); second != r.currentSecond {
+		r.currentSecond = second
+		r.used = 0
+	}
+
+	if r.expired(now) {
+		if r.borrow && r.used < 1 {
+			r.used++
+			return true, true
+		}
+		return false, false
+	}
+	if r.used < r.quota {
+		r.used++
+		return true, false
+	}
+	return false, false
+}
+
+// rule is a sampling rule along with its sampling state.
+type rule struct {
+	// samplingRule holds the properties of the rule, it is not modified.
+	samplingRule
+
+	mu sync.Mutex
+	// fixedRate is the fixed rate of the rule, or of its sampling target.
+	fixedRate    float64
+	ratioSampler trace.Sampler
+	reservoir    reservoir
+
+	requests int64
+	sampled  int64
+	borrowed int64
+}
+
+// newRemoteRule returns a rule of the X-Ray service. It borrows from the
+// reservoir of the rule until its quota is assigned with the sampling
+// targets.
+func newRemoteRule(r *samplingRule) *rule {
+	return &rule{
+		samplingRule: *r,
+		fixedRate:    r.FixedRate,
+		ratioSampler: trace.TraceIDRatioBased(r.FixedRate),
+		reservoir: reservoir{
+			expiring: true,
+			borrow:   r.ReservoirSize > 0,
+		},
+	}
+}
+
+// newLocalRule returns a rule of the local sampling rules.
+func newLocalRule(r LocalSamplingRule) *rule {
+	return &rule{
+		samplingRule: samplingRule{
+			RuleName:    r.Description,
+			FixedRate:   r.Rate,
+			ServiceName: "*",
+			ServiceType: "*",
+			Host:        orWildcard(r.Host),
+			HTTPMethod:  orWildcard(r.HTTPMethod),
+			URLPath:     orWildcard(r.URLPath),
+			ResourceARN: "*",
+		},
+		fixedRate:    r.Rate,
+		ratioSampler: trace.TraceIDRatioBased(r.Rate),
+		reservoir:    reservoir{quota: r.FixedTarget},
+	}
+}
+
+func orWildcard(pattern string) string {
+	if pattern == "" {
+		return "*"
+	}
+	return pattern
+}
+
+// appliesTo reports whether the rule matches the request.
+func (r *rule) appliesTo(req *samplingRequest) bool {
+	return wildcardMatch(r.ServiceName, req.serviceName) &&
+		wildcardMatch(r.ServiceType, req.serviceType) &&
+		wildcardMatch(r.ResourceARN, req.resourceARN) &&
+		wildcardMatch(r.Host, req.host) &&
+		wildcardMatch(r.HTTPMethod, req.httpMethod) &&
+		wildcardMatch(r.URLPath, req.urlPath) &&
+		r.attributesMatch(req.attributes)
+}
+
+// attributesMatch reports whether all the attributes of the rule match an
+// attribute of the span.
+func (r *rule) attributesMatch(attrs []attribute.KeyValue) bool {
+	for key, pattern := range r.Attributes {
+		matched := false
+		for _, attr := range attrs {
+			if string(attr.Key) == key {
+				matched = wildcardMatch(pattern, attr.Value.Emit())
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// sample returns the sampling decision of the rule at now. The span is
+// sampled if the reservoir is not exhausted, and otherwise at the fixed rate
+// of the rule.
+func (r *rule) sample(p trace.SamplingParameters, now time.Time) trace.SamplingDecision {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.requests++
+	if ok, borrowed := r.reservoir.take(now); ok {
+		r.sampled++
+		if borrowed {
+			r.borrowed++
+		}
+		return trace.RecordAndSample
+	}
+	if r.ratioSampler.ShouldSample(p).Decision == trace.RecordAndSample {
+		r.sampled++
+		return trace.RecordAndSample
+	}
+	return trace.Drop
+}
+
+// statistics returns the sampling statistics of the rule since the last call
+// and resets them.
+func (r *rule) statistics(clientID string, now time.Time) *samplingStatisticsDocument {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	doc := &samplingStatisticsDocument{
+		ClientID:     clientID,
+		RuleName:     r.RuleName,
+		RequestCount: r.requests,
+		SampledCount: r.sampled,
+		BorrowCount:  r.borrowed,
+		Timestamp:    float64(now.UnixNano()) / float64(time.Second),
+	}
+	r.requests, r.sampled, r.borrowed = 0, 0, 0
+	return doc
+}
+
+// applyTarget updates the fixed rate and the reservoir quota of the rule with
+// its sampling target.
+func (r *rule) applyTarget(target *samplingTargetDocument) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if target.FixedRate != r.fixedRate {
+		r.fixedRate = target.FixedRate
+		r.ratioSampler = trace.TraceIDRatioBased(target.FixedRate)
+	}
+	if target.ReservoirQuota != nil {
+		r.reservoir.quota = *target.ReservoirQuota
+	}
+	if target.ReservoirQuotaTTL != nil {
+		sec, frac := math.Modf(*target.ReservoirQuotaTTL)
+		r.reservoir.expiresAt = time.Unix(int64(sec), int64(frac*float64(time.Second)))
+	}
+}
+
+// wildcardMatch reports whether text matches the case-insensitive pattern,
+// where '*' matches any sequence of characters and '?' matches a single
+// character.
+func wildcardMatch(pattern, text string) bool {
+	if pattern == "*" {
+		return true
+	}
+	pattern, text = strings.ToLower(pattern), strings.ToLower(text)
+
+	p, t := 0, 0
+	// Position of the last '*' in pattern, and of text when it was reached.
+	star, match := -1, 0
+	for t < len(text) {
+		switch {
+		case p < len(pattern) && (pattern[p] == '?' || pattern[p] == text[t]):
+			p++
+			t++
+		case p < len(pattern) && pattern[p] == '*':
+			star, match = p, t
+			p++
+		case star >= 0:
+			// Let the last '*' match one more character.
+			match++
+			p, t = star+1, match
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
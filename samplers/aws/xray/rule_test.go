@@ -0,0 +1,178 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package xray
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func TestWildcardMatch(t *testing.T) {
+	tests := []struct {
+		pattern, text string
+		want          bool
+	}{
+		{"*", "", true},
+		{"*", "anything", true},
+		{"", "", true},
+		{"", "a", false},
+		{"foo", "foo", true},
+		{"foo", "FOO", true},
+		{"foo", "foobar", false},
+		{"foo*", "foobar", true},
+		{"*bar", "foobar", true},
+		{"*bar", "foobaz", false},
+		{"f?o", "fao", true},
+		{"f?o", "fo", false},
+		{"/api/*/items", "/api/v1/items", true},
+		{"/api/*/items", "/api/v1/items/1", false},
+		{"*a*b*", "xxaxxbxx", true},
+		{"*a*b*", "xxbxxaxx", false},
+		{"a**", "a", true},
+		{"?", "", false},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.want, wildcardMatch(test.pattern, test.text), "pattern %q, text %q", test.pattern, test.text)
+	}
+}
+
+func TestReservoirQuota(t *testing.T) {
+	now := time.Unix(1000, 0)
+	r := reservoir{quota: 2, expiring: true, expiresAt: now.Add(time.Minute)}
+
+	for range 2 {
+		ok, borrowed := r.take(now)
+		assert.True(t, ok)
+		assert.False(t, borrowed)
+	}
+	ok, _ := r.take(now.Add(500 * time.Millisecond))
+	assert.False(t, ok, "quota exhausted")
+
+	ok, borrowed := r.take(now.Add(time.Second))
+	assert.True(t, ok, "quota of the next second")
+	assert.False(t, borrowed)
+}
+
+func TestReservoirBorrow(t *testing.T) {
+	now := time.Unix(1000, 0)
+	r := reservoir{quota: 5, expiring: true, expiresAt: now, borrow: true}
+
+	ok, borrowed := r.take(now)
+	assert.True(t, ok)
+	assert.True(t, borrowed, "expired quota")
+	ok, _ = r.take(now)
+	assert.False(t, ok, "a single span is borrowed each second")
+
+	ok, borrowed = r.take(now.Add(time.Second))
+	assert.True(t, ok)
+	assert.True(t, borrowed)
+
+	r.borrow = false
+	ok, _ = r.take(now.Add(2 * time.Second))
+	assert.False(t, ok, "borrowing disabled")
+}
+
+func TestReservoirNotExpiring(t *testing.T) {
+	r := reservoir{quota: 1}
+	ok, borrowed := r.take(time.Unix(1000, 0))
+	assert.True(t, ok)
+	assert.False(t, borrowed)
+}
+
+func TestRuleAppliesTo(t *testing.T) {
+	r := newRemoteRule(&samplingRule{
+		RuleName:    "rule",
+		ServiceName: "checkout-*",
+		ServiceType: "AWS::EC2::Instance",
+		Host:        "*.example.com",
+		HTTPMethod:  "POST",
+		URLPath:     "/api/*",
+		ResourceARN: "arn:aws:ec2:*",
+		Attributes:  map[string]string{"tenant": "gold-*"},
+		Version:     1,
+	})
+	matching := func() *samplingRequest {
+		return &samplingRequest{
+			serviceName: "checkout-api",
+			serviceType: "AWS::EC2::Instance",
+			resourceARN: "arn:aws:ec2:us-east-1:123456789012:instance/i-1",
+			host:        "shop.example.com",
+			httpMethod:  "post",
+			urlPath:     "/api/orders",
+			attributes:  []attribute.KeyValue{attribute.String("tenant", "gold-1")},
+		}
+	}
+	assert.True(t, r.appliesTo(matching()))
+
+	for name, modify := range map[string]func(*samplingRequest){
+		"service name": func(req *samplingRequest) { req.serviceName = "cart" },
+		"service type": func(req *samplingRequest) { req.serviceType = "AWS::ECS::Container" },
+		"resource ARN": func(req *samplingRequest) { req.resourceARN = "arn:aws:lambda:fn" },
+		"host":         func(req *samplingRequest) { req.host = "example.org" },
+		"HTTP method":  func(req *samplingRequest) { req.httpMethod = "GET" },
+		"URL path":     func(req *samplingRequest) { req.urlPath = "/health" },
+		"attribute value": func(req *samplingRequest) {
+			req.attributes = []attribute.KeyValue{attribute.String("tenant", "silver")}
+		},
+		"attribute missing":  func(req *samplingRequest) { req.attributes = nil },
+		"empty request host": func(req *samplingRequest) { req.host = "" },
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := matching()
+			modify(req)
+			assert.False(t, r.appliesTo(req))
+		})
+	}
+}
+
+func TestRuleSample(t *testing.T) {
+	now := time.Unix(1000, 0)
+	r := newRemoteRule(&samplingRule{RuleName: "rule", FixedRate: 0, ReservoirSize: 10, Version: 1})
+	p := trace.SamplingParameters{TraceID: oteltrace.TraceID{1}}
+
+	// The quota is not assigned yet, a span is borrowed.
+	assert.Equal(t, trace.RecordAndSample, r.sample(p, now))
+	assert.Equal(t, trace.Drop, r.sample(p, now))
+
+	quota, ttl := int64(2), float64(now.Add(time.Minute).Unix())
+	r.applyTarget(&samplingTargetDocument{RuleName: "rule", FixedRate: 0, ReservoirQuota: &quota, ReservoirQuotaTTL: &ttl})
+	next := now.Add(time.Second)
+	assert.Equal(t, trace.RecordAndSample, r.sample(p, next))
+	assert.Equal(t, trace.RecordAndSample, r.sample(p, next))
+	assert.Equal(t, trace.Drop, r.sample(p, next))
+
+	// Beyond the quota, spans are sampled at the fixed rate.
+	r.applyTarget(&samplingTargetDocument{RuleName: "rule", FixedRate: 1})
+	assert.Equal(t, trace.RecordAndSample, r.sample(p, next))
+
+	assert.Equal(t, &samplingStatisticsDocument{
+		ClientID:     "client",
+		RuleName:     "rule",
+		RequestCount: 6,
+		SampledCount: 4,
+		BorrowCount:  1,
+		Timestamp:    1001,
+	}, r.statistics("client", next))
+	stats := r.statistics("client", next)
+	assert.Zero(t, stats.RequestCount, "statistics reset")
+	assert.Zero(t, stats.SampledCount, "statistics reset")
+	assert.Zero(t, stats.BorrowCount, "statistics reset")
+}
+
+func TestLocalRule(t *testing.T) {
+	r := newLocalRule(LocalSamplingRule{HTTPMethod: "GET", URLPath: "/health", FixedTarget: 1, Rate: 0})
+	assert.True(t, r.appliesTo(&samplingRequest{serviceName: "svc", host: "any", httpMethod: "GET", urlPath: "/health"}))
+	assert.False(t, r.appliesTo(&samplingRequest{serviceName: "svc", httpMethod: "GET", urlPath: "/"}))
+
+	now := time.Unix(1000, 0)
+	p := trace.SamplingParameters{TraceID: oteltrace.TraceID{1}}
+	assert.Equal(t, trace.RecordAndSample, r.sample(p, now))
+	assert.Equal(t, trace.Drop, r.sample(p, now))
+	assert.Equal(t, trace.RecordAndSample, r.sample(p, now.Add(time.Second)))
+}
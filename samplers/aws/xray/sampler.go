@@ -0,0 +1,334 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package xray
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"math"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// Attributes of the older HTTP semantic conventions, matched when the
+// attributes of the current ones are not set.
+const (
+	httpMethodKey  = attribute.Key("http.method")
+	httpHostKey    = attribute.Key("http.host")
+	httpTargetKey  = attribute.Key("http.target")
+	httpURLKey     = attribute.Key("http.url")
+	netHostNameKey = attribute.Key("net.host.name")
+)
+
+// Sampler is a sampler applying the AWS X-Ray centralized sampling rules,
+// polled from the X-Ray service. The spans are sampled by the first rule they
+// match, by priority, within the reservoir quota of the rule assigned to this
+// client and at the fixed rate of the rule beyond it.
+//
+// The Sampler does not take the sampling decision of the parent span into
+// account, use it as the root sampler of [trace.ParentBased] to do so.
+type Sampler struct {
+	config
+
+	serviceName string
+	clientID    string
+	client      *xrayClient
+	localRules  []*rule
+	// clock returns the current time.
+	clock func() time.Time
+
+	mu             sync.RWMutex
+	rules          []*rule
+	rulesFetchedAt time.Time
+
+	closed   atomic.Bool
+	ctx      context.Context
+	cancel   context.CancelFunc
+	doneChan chan *sync.WaitGroup
+}
+
+// New returns a Sampler applying the X-Ray sampling rules for the service. It
+// periodically polls the sampling rules and the sampling targets of the
+// client until it is closed.
+//
+// An error is returned if the local sampling rules are invalid.
+func New(serviceName string, opts ...Option) (*Sampler, error) {
+	s, err := newSampler(serviceName, newConfig(opts...))
+	if err != nil {
+		return nil, err
+	}
+	go s.pollController()
+	return s, nil
+}
+
+// newSampler returns a Sampler that does not poll the X-Ray service yet.
+func newSampler(serviceName string, cfg config) (*Sampler, error) {
+	localRules, err := newLocalRules(cfg.localRules)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Sampler{
+		config:      cfg,
+		serviceName: serviceName,
+		clientID:    newClientID(),
+		client:      newClient(cfg.endpoint),
+		localRules:  localRules,
+		clock:       time.Now,
+		ctx:         ctx,
+		cancel:      cancel,
+		doneChan:    make(chan *sync.WaitGroup),
+	}, nil
+}
+
+// newClientID returns a random identifier of the client reporting the
+// sampling statistics.
+func newClientID() string {
+	var b [12]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// ShouldSample returns a sampling choice based on the passed sampling
+// parameters.
+func (s *Sampler) ShouldSample(p trace.SamplingParameters) trace.SamplingResult {
+	now := s.clock()
+	req := s.newSamplingRequest(p.Attributes)
+
+	decision := trace.Drop
+	if r := s.matchRule(req, now); r != nil {
+		decision = r.sample(p, now)
+	}
+	return trace.SamplingResult{
+		Decision:   decision,
+		Tracestate: oteltrace.SpanContextFromContext(p.ParentContext).TraceState(),
+	}
+}
+
+// Description returns a human-readable name for the Sampler.
+func (*Sampler) Description() string {
+	return "AWSXRayRemoteSampler{}"
+}
+
+// Close does a clean shutdown of the sampler, stopping any background
+// go-routines it may have started.
+func (s *Sampler) Close() {
+	if swapped := s.closed.CompareAndSwap(false, true); !swapped {
+		s.logger.Info("repeated attempt to close the sampler is ignored")
+		return
+	}
+
+	s.cancel()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	s.doneChan <- &wg
+	wg.Wait()
+}
+
+// matchRule returns the first rule matching the request. The local rules are
+// matched when the sampling rules were not fetched or are expired, or when
+// none of them match.
+func (s *Sampler) matchRule(req *samplingRequest, now time.Time) *rule {
+	s.mu.RLock()
+	rules := s.rules
+	if now.Sub(s.rulesFetchedAt) > rulesExpiration {
+		rules = nil
+	}
+	s.mu.RUnlock()
+
+	for _, r := range rules {
+		if r.appliesTo(req) {
+			return r
+		}
+	}
+	for _, r := range s.localRules {
+		if r.appliesTo(req) {
+			return r
+		}
+	}
+	return nil
+}
+
+// newSamplingRequest returns the sampling request of a span with attrs.
+func (s *Sampler) newSamplingRequest(attrs []attribute.KeyValue) *samplingRequest {
+	req := &samplingRequest{
+		serviceName: s.serviceName,
+		serviceType: s.serviceType,
+		resourceARN: s.resourceARN,
+		attributes:  attrs,
+	}
+
+	var target, fullURL string
+	for _, attr := range attrs {
+		switch attr.Key {
+		case semconv.HTTPRequestMethodKey:
+			req.httpMethod = attr.Value.AsString()
+		case httpMethodKey:
+			if req.httpMethod == "" {
+				req.httpMethod = attr.Value.AsString()
+			}
+		case semconv.ServerAddressKey:
+			req.host = attr.Value.AsString()
+		case httpHostKey, netHostNameKey:
+			if req.host == "" {
+				req.host = attr.Value.AsString()
+			}
+		case semconv.URLPathKey:
+			req.urlPath = attr.Value.AsString()
+		case httpTargetKey:
+			target = attr.Value.AsString()
+		case semconv.URLFullKey, httpURLKey:
+			fullURL = attr.Value.AsString()
+		case semconv.CloudResourceIDKey:
+			req.resourceARN = attr.Value.AsString()
+		}
+	}
+
+	if req.urlPath == "" {
+		switch {
+		case target != "":
+			req.urlPath, _, _ = strings.Cut(target, "?")
+		case fullURL != "":
+			if u, err := url.Parse(fullURL); err == nil {
+				req.urlPath = u.Path
+			}
+		}
+	}
+	return req
+}
+
+func (s *Sampler) pollController() {
+	s.updateRules()
+
+	rulesTicker := time.NewTicker(s.samplingRulesPollingInterval)
+	defer rulesTicker.Stop()
+	targetsInterval := defaultSamplingTargetsInterval
+	targetsTicker := time.NewTicker(targetsInterval)
+	defer targetsTicker.Stop()
+
+	for {
+		select {
+		case <-rulesTicker.C:
+			s.updateRules()
+		case <-targetsTicker.C:
+			if interval := s.updateTargets(); interval != targetsInterval {
+				targetsInterval = interval
+				targetsTicker.Reset(interval)
+			}
+		case wg := <-s.doneChan:
+			wg.Done()
+			return
+		}
+	}
+}
+
+// updateRules fetches the sampling rules. The sampling state of the rules
+// that are not modified is kept.
+func (s *Sampler) updateRules() {
+	samplingRules, err := s.client.getSamplingRules(s.ctx)
+	if err != nil {
+		s.logger.Error(err, "failed to fetch sampling rules")
+		return
+	}
+	now := s.clock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := make(map[string]*rule, len(s.rules))
+	for _, r := range s.rules {
+		current[r.RuleName] = r
+	}
+
+	rules := make([]*rule, 0, len(samplingRules))
+	for _, sr := range samplingRules {
+		// Only the version 1 of the sampling rules is supported.
+		if sr.Version != 1 {
+			s.logger.V(1).Info("ignoring sampling rule of unsupported version", "rule", sr.RuleName, "version", sr.Version)
+			continue
+		}
+		if r, ok := current[sr.RuleName]; ok && reflect.DeepEqual(r.samplingRule, *sr) {
+			rules = append(rules, r)
+			continue
+		}
+		rules = append(rules, newRemoteRule(sr))
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].Priority != rules[j].Priority {
+			return rules[i].Priority < rules[j].Priority
+		}
+		return rules[i].RuleName < rules[j].RuleName
+	})
+
+	s.rules = rules
+	s.rulesFetchedAt = now
+}
+
+// updateTargets reports the sampling statistics of the rules and applies the
+// sampling targets returned for them. The sampling rules are fetched again if
+// they were modified since they were last fetched. It returns the interval
+// after which the targets have to be fetched again.
+func (s *Sampler) updateTargets() time.Duration {
+	s.mu.RLock()
+	rules := s.rules
+	fetchedAt := s.rulesFetchedAt
+	s.mu.RUnlock()
+	if len(rules) == 0 {
+		return defaultSamplingTargetsInterval
+	}
+
+	now := s.clock()
+	statistics := make([]*samplingStatisticsDocument, 0, len(rules))
+	for _, r := range rules {
+		statistics = append(statistics, r.statistics(s.clientID, now))
+	}
+	output, err := s.client.getSamplingTargets(s.ctx, statistics)
+	if err != nil {
+		s.logger.Error(err, "failed to fetch sampling targets")
+		return defaultSamplingTargetsInterval
+	}
+
+	byName := make(map[string]*rule, len(rules))
+	for _, r := range rules {
+		byName[r.RuleName] = r
+	}
+	interval := defaultSamplingTargetsInterval
+	for _, target := range output.SamplingTargetDocuments {
+		if target == nil {
+			continue
+		}
+		if r, ok := byName[target.RuleName]; ok {
+			r.applyTarget(target)
+		}
+		// The X-Ray service asks to poll the targets of a rule sooner.
+		if target.Interval != nil && *target.Interval > 0 {
+			interval = min(interval, time.Duration(*target.Interval)*time.Second)
+		}
+	}
+	for _, u := range output.UnprocessedStatistics {
+		if u != nil {
+			s.logger.V(1).Info("sampling statistics not processed", "rule", u.RuleName, "code", u.ErrorCode, "message", u.Message)
+		}
+	}
+
+	if output.LastRuleModification != nil {
+		sec, frac := math.Modf(*output.LastRuleModification)
+		if time.Unix(int64(sec), int64(frac*float64(time.Second))).After(fetchedAt) {
+			s.updateRules()
+		}
+	}
+	return interval
+}
@@ -0,0 +1,371 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package xray
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// fakeXRay is a stand-in of the X-Ray sampling APIs.
+type fakeXRay struct {
+	*httptest.Server
+
+	mu           sync.Mutex
+	rules        []*samplingRule
+	targets      getSamplingTargetsOutput
+	rulesCalls   int
+	statistics   [][]*samplingStatisticsDocument
+	failRequests bool
+}
+
+func newFakeXRay(t *testing.T, rules ...*samplingRule) *fakeXRay {
+	f := &fakeXRay{rules: rules}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /GetSamplingRules", func(w http.ResponseWriter, _ *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if f.failRequests {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		f.rulesCalls++
+		var out getSamplingRulesOutput
+		for _, r := range f.rules {
+			out.SamplingRuleRecords = append(out.SamplingRuleRecords, &samplingRuleRecord{SamplingRule: r})
+		}
+		_ = json.NewEncoder(w).Encode(out)
+	})
+	mux.HandleFunc("POST /SamplingTargets", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if f.failRequests {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		var in getSamplingTargetsInput
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.statistics = append(f.statistics, in.SamplingStatisticsDocuments)
+		_ = json.NewEncoder(w).Encode(f.targets)
+	})
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeXRay) endpoint(t *testing.T) url.URL {
+	u, err := url.Parse(f.URL)
+	require.NoError(t, err)
+	return *u
+}
+
+func (f *fakeXRay) setFailRequests(fail bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failRequests = fail
+}
+
+var (
+	testDefaultRule = &samplingRule{
+		RuleName:      "Default",
+		Priority:      10000,
+		FixedRate:     0,
+		ReservoirSize: 1,
+		ServiceName:   "*",
+		ServiceType:   "*",
+		Host:          "*",
+		HTTPMethod:    "*",
+		URLPath:       "*",
+		ResourceARN:   "*",
+		Version:       1,
+	}
+	testOrdersRule = &samplingRule{
+		RuleName:    "orders",
+		Priority:    1,
+		FixedRate:   1,
+		ServiceName: "shop",
+		ServiceType: "*",
+		Host:        "*",
+		HTTPMethod:  "POST",
+		URLPath:     "/orders*",
+		ResourceARN: "*",
+		Version:     1,
+	}
+)
+
+func newTestSampler(t *testing.T, f *fakeXRay, opts ...Option) (*Sampler, *time.Time) {
+	s, err := newSampler("shop", newConfig(append([]Option{WithEndpoint(f.endpoint(t))}, opts...)...))
+	require.NoError(t, err)
+	now := time.Unix(1000, 0)
+	s.clock = func() time.Time { return now }
+	return s, &now
+}
+
+func sample(s *Sampler, attrs ...attribute.KeyValue) trace.SamplingDecision {
+	return s.ShouldSample(trace.SamplingParameters{
+		TraceID:    oteltrace.TraceID{1},
+		Attributes: attrs,
+	}).Decision
+}
+
+func TestSamplerRules(t *testing.T) {
+	f := newFakeXRay(t, testDefaultRule, testOrdersRule)
+	s, _ := newTestSampler(t, f)
+	s.updateRules()
+
+	require.Len(t, s.rules, 2)
+	assert.Equal(t, "orders", s.rules[0].RuleName, "rules sorted by priority")
+
+	order := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String("POST"),
+		semconv.URLPathKey.String("/orders/1"),
+	}
+	assert.Equal(t, trace.RecordAndSample, sample(s, order...))
+	assert.Equal(t, trace.RecordAndSample, sample(s, order...), "fixed rate of 1")
+
+	// The Default rule borrows a span and drops the others.
+	assert.Equal(t, trace.RecordAndSample, sample(s))
+	assert.Equal(t, trace.Drop, sample(s))
+}
+
+func TestSamplerRulesUnsupportedVersion(t *testing.T) {
+	rule := *testOrdersRule
+	rule.Version = 2
+	f := newFakeXRay(t, testDefaultRule, &rule)
+	s, _ := newTestSampler(t, f)
+	s.updateRules()
+
+	require.Len(t, s.rules, 1)
+	assert.Equal(t, "Default", s.rules[0].RuleName)
+}
+
+func TestSamplerLocalRules(t *testing.T) {
+	f := newFakeXRay(t, testDefaultRule)
+	f.setFailRequests(true)
+	s, now := newTestSampler(t, f, WithLocalSamplingRules(LocalSamplingRules{
+		Rules: []LocalSamplingRule{
+			{Description: "orders", HTTPMethod: "POST", URLPath: "/orders*", FixedTarget: 0, Rate: 1},
+		},
+		Default: LocalSamplingRule{FixedTarget: 0, Rate: 0},
+	}))
+
+	// The rules cannot be fetched, the local rules are used.
+	s.updateRules()
+	assert.Empty(t, s.rules)
+	assert.Equal(t, trace.RecordAndSample, sample(s, semconv.HTTPRequestMethodKey.String("POST"), semconv.URLPathKey.String("/orders")))
+	assert.Equal(t, trace.Drop, sample(s))
+
+	f.setFailRequests(false)
+	s.updateRules()
+	require.Len(t, s.rules, 1)
+	assert.Equal(t, trace.RecordAndSample, sample(s), "span borrowed by the Default rule")
+
+	// The rules are stale, the local rules are used again.
+	*now = now.Add(rulesExpiration + time.Second)
+	assert.Equal(t, trace.Drop, sample(s))
+}
+
+func TestSamplerTargets(t *testing.T) {
+	f := newFakeXRay(t, testDefaultRule, testOrdersRule)
+	s, now := newTestSampler(t, f)
+
+	assert.Equal(t, defaultSamplingTargetsInterval, s.updateTargets(), "no rules")
+	assert.Empty(t, f.statistics)
+
+	s.updateRules()
+	assert.Equal(t, trace.RecordAndSample, sample(s))
+	assert.Equal(t, trace.Drop, sample(s))
+
+	quota, ttl, interval := int64(2), float64(now.Add(time.Minute).Unix()), int64(5)
+	f.targets = getSamplingTargetsOutput{
+		SamplingTargetDocuments: []*samplingTargetDocument{{
+			RuleName:          "Default",
+			FixedRate:         0,
+			ReservoirQuota:    &quota,
+			ReservoirQuotaTTL: &ttl,
+			Interval:          &interval,
+		}},
+	}
+	assert.Equal(t, 5*time.Second, s.updateTargets())
+
+	require.Len(t, f.statistics, 1)
+	stats := make(map[string]*samplingStatisticsDocument)
+	for _, doc := range f.statistics[0] {
+		assert.Equal(t, s.clientID, doc.ClientID)
+		stats[doc.RuleName] = doc
+	}
+	require.Len(t, stats, 2)
+	assert.Equal(t, int64(2), stats["Default"].RequestCount)
+	assert.Equal(t, int64(1), stats["Default"].SampledCount)
+	assert.Equal(t, int64(1), stats["Default"].BorrowCount)
+	assert.Zero(t, stats["orders"].RequestCount)
+
+	*now = now.Add(time.Second)
+	assert.Equal(t, trace.RecordAndSample, sample(s))
+	assert.Equal(t, trace.RecordAndSample, sample(s))
+	assert.Equal(t, trace.Drop, sample(s), "quota exhausted")
+}
+
+func TestSamplerRulesModified(t *testing.T) {
+	f := newFakeXRay(t, testDefaultRule)
+	s, now := newTestSampler(t, f)
+	s.updateRules()
+	defaultRule := s.rules[0]
+	assert.Equal(t, 1, f.rulesCalls)
+
+	// The state of unmodified rules is kept.
+	s.updateRules()
+	assert.Same(t, defaultRule, s.rules[0])
+	assert.Equal(t, 2, f.rulesCalls)
+
+	modified := *testDefaultRule
+	modified.FixedRate = 1
+	f.rules = []*samplingRule{&modified, testOrdersRule}
+	lastModification := float64(now.Add(time.Second).Unix())
+	f.targets = getSamplingTargetsOutput{LastRuleModification: &lastModification}
+
+	*now = now.Add(2 * time.Second)
+	s.updateTargets()
+	assert.Equal(t, 3, f.rulesCalls, "modified rules fetched")
+	require.Len(t, s.rules, 2)
+	assert.NotSame(t, defaultRule, s.rules[1])
+	assert.Equal(t, 1.0, s.rules[1].fixedRate)
+
+	// Not modified since the rules were fetched.
+	s.updateTargets()
+	assert.Equal(t, 3, f.rulesCalls)
+}
+
+func TestSamplerRequest(t *testing.T) {
+	s, err := newSampler("shop", newConfig(
+		WithServiceType("AWS::EC2::Instance"),
+		WithResourceARN("arn:aws:ec2:instance"),
+	))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		attrs []attribute.KeyValue
+		want  samplingRequest
+	}{
+		{
+			name: "current conventions",
+			attrs: []attribute.KeyValue{
+				semconv.HTTPRequestMethodKey.String("GET"),
+				semconv.ServerAddressKey.String("example.com"),
+				semconv.URLPathKey.String("/path"),
+				semconv.CloudResourceIDKey.String("arn:aws:lambda:fn"),
+			},
+			want: samplingRequest{
+				httpMethod:  "GET",
+				host:        "example.com",
+				urlPath:     "/path",
+				resourceARN: "arn:aws:lambda:fn",
+			},
+		},
+		{
+			name: "older conventions",
+			attrs: []attribute.KeyValue{
+				httpMethodKey.String("PUT"),
+				httpHostKey.String("example.org"),
+				httpTargetKey.String("/target?q=1"),
+			},
+			want: samplingRequest{
+				httpMethod:  "PUT",
+				host:        "example.org",
+				urlPath:     "/target",
+				resourceARN: "arn:aws:ec2:instance",
+			},
+		},
+		{
+			name: "full URL",
+			attrs: []attribute.KeyValue{
+				semconv.URLFullKey.String("https://example.net/full/path?q=1"),
+			},
+			want: samplingRequest{
+				urlPath:     "/full/path",
+				resourceARN: "arn:aws:ec2:instance",
+			},
+		},
+		{
+			name: "current conventions precedence",
+			attrs: []attribute.KeyValue{
+				httpMethodKey.String("PUT"),
+				semconv.HTTPRequestMethodKey.String("GET"),
+				netHostNameKey.String("example.org"),
+				semconv.ServerAddressKey.String("example.com"),
+			},
+			want: samplingRequest{
+				httpMethod:  "GET",
+				host:        "example.com",
+				resourceARN: "arn:aws:ec2:instance",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			want := test.want
+			want.serviceName = "shop"
+			want.serviceType = "AWS::EC2::Instance"
+			want.attributes = test.attrs
+			assert.Equal(t, &want, s.newSamplingRequest(test.attrs))
+		})
+	}
+}
+
+func TestSamplerTracestate(t *testing.T) {
+	s, err := newSampler("shop", newConfig())
+	require.NoError(t, err)
+
+	ts, err := oteltrace.ParseTraceState("key=value")
+	require.NoError(t, err)
+	parent := oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+		TraceID:    oteltrace.TraceID{1},
+		SpanID:     oteltrace.SpanID{1},
+		TraceState: ts,
+	})
+	result := s.ShouldSample(trace.SamplingParameters{
+		ParentContext: oteltrace.ContextWithSpanContext(t.Context(), parent),
+		TraceID:       parent.TraceID(),
+	})
+	assert.Equal(t, ts, result.Tracestate)
+}
+
+func TestNew(t *testing.T) {
+	f := newFakeXRay(t, testDefaultRule)
+	s, err := New("shop", WithEndpoint(f.endpoint(t)), WithSamplingRulesPollingInterval(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, "AWSXRayRemoteSampler{}", s.Description())
+
+	assert.Eventually(t, func() bool {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		return len(s.rules) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	s.Close()
+	s.Close()
+}
+
+func TestNewInvalidLocalRules(t *testing.T) {
+	_, err := New("shop", WithLocalSamplingRules(LocalSamplingRules{
+		Default: LocalSamplingRule{Rate: 2},
+	}))
+	assert.ErrorIs(t, err, errInvalidLocalRule)
+}
@@ -0,0 +1,7 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package xray
+
+// Version is the current release version of the AWS X-Ray sampler module.
+const Version = "0.37.2"
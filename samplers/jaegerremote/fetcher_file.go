@@ -0,0 +1,228 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package jaegerremote
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	jaeger_api_v2 "github.com/jaegertracing/jaeger-idl/proto-gen/api_v2"
+)
+
+const (
+	defaultFileSamplingProbability = 0.001
+
+	probabilisticStrategyType = "probabilistic"
+	rateLimitingStrategyType  = "ratelimiting"
+)
+
+// fileStrategy is a sampling strategy of a strategies file.
+type fileStrategy struct {
+	Type  string  `json:"type"`
+	Param float64 `json:"param"`
+}
+
+// fileOperationStrategy is the sampling strategy of an operation of a
+// strategies file.
+type fileOperationStrategy struct {
+	fileStrategy
+
+	Operation string `json:"operation"`
+}
+
+// fileServiceStrategy is the sampling strategy of a service, or the default
+// strategy, of a strategies file.
+type fileServiceStrategy struct {
+	fileStrategy
+
+	Service             string                   `json:"service"`
+	OperationStrategies []*fileOperationStrategy `json:"operation_strategies"`
+}
+
+// fileStrategies is the content of a strategies file.
+type fileStrategies struct {
+	ServiceStrategies []*fileServiceStrategy `json:"service_strategies"`
+	DefaultStrategy   *fileServiceStrategy   `json:"default_strategy"`
+}
+
+// strategyStore holds the sampling strategies of a strategies file.
+type strategyStore struct {
+	defaultStrategy   *jaeger_api_v2.SamplingStrategyResponse
+	serviceStrategies map[string]*jaeger_api_v2.SamplingStrategyResponse
+}
+
+type fileSamplingStrategyFetcher struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	store   *strategyStore
+}
+
+// NewFileSamplingStrategyFetcher returns a SamplingStrategyFetcher reading the
+// sampling strategies from the local JSON file at path, in the format of the
+// strategies file of the Jaeger collector (--sampling.strategies-file). This
+// allows using remote sampling strategies without a collector.
+//
+// The file is read again when it is modified, so the strategies can be
+// updated without restarting the service. If it cannot be read or parsed,
+// the fetch fails and the current sampler is kept.
+func NewFileSamplingStrategyFetcher(path string) SamplingStrategyFetcher {
+	return &fileSamplingStrategyFetcher{path: path}
+}
+
+// Fetch returns the sampling strategy of the service in the JSON encoding of
+// the HTTP sampling endpoint.
+func (f *fileSamplingStrategyFetcher) Fetch(serviceName string) ([]byte, error) {
+	store, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+	strategy, ok := store.serviceStrategies[serviceName]
+	if !ok {
+		strategy = store.defaultStrategy
+	}
+	return marshalStrategy(strategy)
+}
+
+// load returns the strategies of the file, reading it again if it was
+// modified since it was last read.
+func (f *fileSamplingStrategyFetcher) load() (*strategyStore, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return nil, err
+	}
+	if f.store != nil && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return f.store, nil
+	}
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, err
+	}
+	var strategies fileStrategies
+	if err := json.Unmarshal(data, &strategies); err != nil {
+		return nil, fmt.Errorf("failed to parse sampling strategies file %s: %w", f.path, err)
+	}
+
+	f.store = newStrategyStore(&strategies)
+	f.modTime = info.ModTime()
+	f.size = info.Size()
+	return f.store, nil
+}
+
+// newStrategyStore returns the sampling strategies of a strategies file, like
+// the Jaeger collector does.
+func newStrategyStore(strategies *fileStrategies) *strategyStore {
+	store := &strategyStore{
+		defaultStrategy:   probabilisticStrategyResponse(defaultFileSamplingProbability),
+		serviceStrategies: make(map[string]*jaeger_api_v2.SamplingStrategyResponse),
+	}
+	if strategies.DefaultStrategy != nil {
+		store.defaultStrategy = parseServiceStrategy(strategies.DefaultStrategy)
+	}
+	defaultOperations := store.defaultStrategy.OperationSampling
+
+	for _, s := range strategies.ServiceStrategies {
+		strategy := parseServiceStrategy(s)
+		store.serviceStrategies[s.Service] = strategy
+
+		if defaultOperations == nil {
+			continue
+		}
+		if strategy.OperationSampling == nil {
+			// Use the default operation strategies, with the sampling
+			// probability of the service.
+			if strategy.ProbabilisticSampling == nil {
+				continue
+			}
+			operations := *defaultOperations
+			operations.DefaultSamplingProbability = strategy.ProbabilisticSampling.SamplingRate
+			strategy.OperationSampling = &operations
+			continue
+		}
+		// Add the default operation strategies of the operations without a
+		// strategy for the service.
+		strategy.OperationSampling.PerOperationStrategies = mergeOperationStrategies(
+			strategy.OperationSampling.PerOperationStrategies,
+			defaultOperations.PerOperationStrategies,
+		)
+	}
+	return store
+}
+
+func parseServiceStrategy(s *fileServiceStrategy) *jaeger_api_v2.SamplingStrategyResponse {
+	resp := parseStrategy(&s.fileStrategy)
+	if len(s.OperationStrategies) == 0 {
+		return resp
+	}
+
+	operations := &jaeger_api_v2.PerOperationSamplingStrategies{
+		DefaultSamplingProbability: defaultFileSamplingProbability,
+	}
+	if resp.ProbabilisticSampling != nil {
+		operations.DefaultSamplingProbability = resp.ProbabilisticSampling.SamplingRate
+	}
+	for _, op := range s.OperationStrategies {
+		// Only probabilistic strategies are supported for operations.
+		if op.Type != probabilisticStrategyType {
+			continue
+		}
+		operations.PerOperationStrategies = append(operations.PerOperationStrategies, &jaeger_api_v2.OperationSamplingStrategy{
+			Operation: op.Operation,
+			ProbabilisticSampling: &jaeger_api_v2.ProbabilisticSamplingStrategy{
+				SamplingRate: op.Param,
+			},
+		})
+	}
+	resp.OperationSampling = operations
+	return resp
+}
+
+func parseStrategy(s *fileStrategy) *jaeger_api_v2.SamplingStrategyResponse {
+	switch s.Type {
+	case probabilisticStrategyType:
+		return probabilisticStrategyResponse(s.Param)
+	case rateLimitingStrategyType:
+		return &jaeger_api_v2.SamplingStrategyResponse{
+			StrategyType: jaeger_api_v2.SamplingStrategyType_RATE_LIMITING,
+			RateLimitingSampling: &jaeger_api_v2.RateLimitingSamplingStrategy{
+				MaxTracesPerSecond: int32(s.Param),
+			},
+		}
+	default:
+		return probabilisticStrategyResponse(defaultFileSamplingProbability)
+	}
+}
+
+func probabilisticStrategyResponse(probability float64) *jaeger_api_v2.SamplingStrategyResponse {
+	return &jaeger_api_v2.SamplingStrategyResponse{
+		StrategyType: jaeger_api_v2.SamplingStrategyType_PROBABILISTIC,
+		ProbabilisticSampling: &jaeger_api_v2.ProbabilisticSamplingStrategy{
+			SamplingRate: probability,
+		},
+	}
+}
+
+// mergeOperationStrategies returns the operation strategies of a service
+// along with the default operation strategies of the other operations.
+func mergeOperationStrategies(service, defaults []*jaeger_api_v2.OperationSamplingStrategy) []*jaeger_api_v2.OperationSamplingStrategy {
+	operations := make(map[string]struct{}, len(service))
+	for _, s := range service {
+		operations[s.Operation] = struct{}{}
+	}
+	for _, s := range defaults {
+		if _, ok := operations[s.Operation]; !ok {
+			service = append(service, s)
+		}
+	}
+	return service
+}
@@ -0,0 +1,152 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package jaegerremote
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	jaeger_api_v2 "github.com/jaegertracing/jaeger-idl/proto-gen/api_v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testStrategiesFile = `{
+  "service_strategies": [
+    {
+      "service": "foo",
+      "type": "probabilistic",
+      "param": 0.8,
+      "operation_strategies": [
+        {"operation": "op1", "type": "probabilistic", "param": 0.2},
+        {"operation": "op2", "type": "ratelimiting", "param": 10}
+      ]
+    },
+    {"service": "bar", "type": "ratelimiting", "param": 5},
+    {"service": "baz", "type": "probabilistic", "param": 0.3}
+  ],
+  "default_strategy": {
+    "type": "probabilistic",
+    "param": 0.5,
+    "operation_strategies": [
+      {"operation": "/health", "type": "probabilistic", "param": 0},
+      {"operation": "op1", "type": "probabilistic", "param": 0.9}
+    ]
+  }
+}`
+
+func writeStrategiesFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func fetchFileStrategy(t *testing.T, fetcher SamplingStrategyFetcher, service string) *jaeger_api_v2.SamplingStrategyResponse {
+	t.Helper()
+	resp, err := fetcher.Fetch(service)
+	require.NoError(t, err)
+	strategy, err := new(samplingStrategyParserImpl).Parse(resp)
+	require.NoError(t, err)
+	return strategy.(*jaeger_api_v2.SamplingStrategyResponse)
+}
+
+func operationRates(ops *jaeger_api_v2.PerOperationSamplingStrategies) map[string]float64 {
+	rates := make(map[string]float64, len(ops.PerOperationStrategies))
+	for _, op := range ops.PerOperationStrategies {
+		rates[op.Operation] = op.ProbabilisticSampling.SamplingRate
+	}
+	return rates
+}
+
+func TestFileSamplingStrategyFetcher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "strategies.json")
+	writeStrategiesFile(t, path, testStrategiesFile)
+	fetcher := NewFileSamplingStrategyFetcher(path)
+
+	t.Run("service with operations", func(t *testing.T) {
+		s := fetchFileStrategy(t, fetcher, "foo")
+		assert.Equal(t, 0.8, s.GetProbabilisticSampling().GetSamplingRate())
+		ops := s.GetOperationSampling()
+		require.NotNil(t, ops)
+		assert.Equal(t, 0.8, ops.DefaultSamplingProbability)
+		// Rate limiting operation strategies are not supported, and the
+		// default operation strategies are merged.
+		assert.Equal(t, map[string]float64{"op1": 0.2, "/health": 0}, operationRates(ops))
+	})
+
+	t.Run("rate limiting service", func(t *testing.T) {
+		s := fetchFileStrategy(t, fetcher, "bar")
+		assert.Equal(t, int32(5), s.GetRateLimitingSampling().GetMaxTracesPerSecond())
+		assert.Nil(t, s.GetOperationSampling())
+	})
+
+	t.Run("probabilistic service", func(t *testing.T) {
+		s := fetchFileStrategy(t, fetcher, "baz")
+		ops := s.GetOperationSampling()
+		require.NotNil(t, ops)
+		assert.Equal(t, 0.3, ops.DefaultSamplingProbability)
+		assert.Equal(t, map[string]float64{"op1": 0.9, "/health": 0}, operationRates(ops))
+	})
+
+	t.Run("default", func(t *testing.T) {
+		s := fetchFileStrategy(t, fetcher, "unknown")
+		assert.Equal(t, 0.5, s.GetProbabilisticSampling().GetSamplingRate())
+		assert.Equal(t, 0.5, s.GetOperationSampling().GetDefaultSamplingProbability())
+	})
+}
+
+func TestFileSamplingStrategyFetcherDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "strategies.json")
+	writeStrategiesFile(t, path, `{"service_strategies": [{"service": "foo", "type": "unknown"}]}`)
+	fetcher := NewFileSamplingStrategyFetcher(path)
+
+	for _, service := range []string{"foo", "bar"} {
+		s := fetchFileStrategy(t, fetcher, service)
+		assert.Equal(t, defaultFileSamplingProbability, s.GetProbabilisticSampling().GetSamplingRate())
+		assert.Nil(t, s.GetOperationSampling())
+	}
+}
+
+func TestFileSamplingStrategyFetcherReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "strategies.json")
+	writeStrategiesFile(t, path, `{"default_strategy": {"type": "probabilistic", "param": 0.5}}`)
+	fetcher := NewFileSamplingStrategyFetcher(path)
+	assert.Equal(t, 0.5, fetchFileStrategy(t, fetcher, "foo").GetProbabilisticSampling().GetSamplingRate())
+
+	writeStrategiesFile(t, path, `{"default_strategy": {"type": "probabilistic", "param": 0.25}}`)
+	// Make sure the modification is detected on file systems with a coarse
+	// modification time.
+	later := time.Now().Add(time.Second)
+	require.NoError(t, os.Chtimes(path, later, later))
+	assert.Equal(t, 0.25, fetchFileStrategy(t, fetcher, "foo").GetProbabilisticSampling().GetSamplingRate())
+}
+
+func TestFileSamplingStrategyFetcherErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := NewFileSamplingStrategyFetcher(filepath.Join(dir, "missing.json")).Fetch("foo")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	path := filepath.Join(dir, "invalid.json")
+	writeStrategiesFile(t, path, `{`)
+	_, err = NewFileSamplingStrategyFetcher(path).Fetch("foo")
+	assert.ErrorContains(t, err, "failed to parse sampling strategies file")
+}
+
+func TestFileSamplingStrategyFetcherSampler(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "strategies.json")
+	writeStrategiesFile(t, path, testStrategiesFile)
+	t.Setenv("OTEL_TRACES_SAMPLER_ARG", "strategiesFile="+path)
+
+	sampler := New("bar", WithSamplingRefreshInterval(time.Hour))
+	defer sampler.Close()
+
+	sampler.UpdateSampler()
+	sampler.RLock()
+	defer sampler.RUnlock()
+	rl, ok := sampler.sampler.(*rateLimitingSampler)
+	require.True(t, ok, "unexpected sampler %T", sampler.sampler)
+	assert.Equal(t, 5.0, rl.maxTracesPerSecond)
+}
@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package jaegerremote
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+	jaeger_api_v2 "github.com/jaegertracing/jaeger-idl/proto-gen/api_v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type grpcSamplingStrategyFetcher struct {
+	client  jaeger_api_v2.SamplingManagerClient
+	timeout time.Duration
+	// conn is the connection of the fetcher, closed by Close if the
+	// fetcher created it.
+	conn *grpc.ClientConn
+}
+
+// NewGRPCSamplingStrategyFetcher returns a SamplingStrategyFetcher fetching
+// the sampling strategies with the Jaeger remote sampling gRPC API
+// (SamplingManager.GetSamplingStrategy) of the server of conn, e.g. a Jaeger
+// collector on port 14250. Each fetch times out after 10 seconds.
+//
+// The connection is not closed when the Sampler is closed, it is owned by the
+// caller.
+func NewGRPCSamplingStrategyFetcher(conn *grpc.ClientConn) SamplingStrategyFetcher {
+	return &grpcSamplingStrategyFetcher{
+		client:  jaeger_api_v2.NewSamplingManagerClient(conn),
+		timeout: defaultRemoteSamplingTimeout,
+	}
+}
+
+// newGRPCSamplingStrategyFetcherForTarget returns a grpcSamplingStrategyFetcher
+// owning an insecure connection to target.
+func newGRPCSamplingStrategyFetcherForTarget(target string) (*grpcSamplingStrategyFetcher, error) {
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	return &grpcSamplingStrategyFetcher{
+		client:  jaeger_api_v2.NewSamplingManagerClient(conn),
+		timeout: defaultRemoteSamplingTimeout,
+		conn:    conn,
+	}, nil
+}
+
+// Fetch returns the sampling strategy of the service in the JSON encoding of
+// the HTTP sampling endpoint.
+func (f *grpcSamplingStrategyFetcher) Fetch(serviceName string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), f.timeout)
+	defer cancel()
+
+	resp, err := f.client.GetSamplingStrategy(ctx, &jaeger_api_v2.SamplingStrategyParameters{
+		ServiceName: serviceName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return marshalStrategy(resp)
+}
+
+// Close closes the connection of the fetcher if it created it.
+func (f *grpcSamplingStrategyFetcher) Close() error {
+	if f.conn == nil {
+		return nil
+	}
+	return f.conn.Close()
+}
+
+// marshalStrategy returns the JSON encoding of the sampling strategy, as
+// returned by the HTTP sampling endpoint.
+func marshalStrategy(strategy proto.Message) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := new(jsonpb.Marshaler).Marshal(&buf, strategy); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package jaegerremote
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	jaeger_api_v2 "github.com/jaegertracing/jaeger-idl/proto-gen/api_v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+type testSamplingManagerServer struct {
+	jaeger_api_v2.UnimplementedSamplingManagerServer
+	strategies map[string]*jaeger_api_v2.SamplingStrategyResponse
+}
+
+func (s *testSamplingManagerServer) GetSamplingStrategy(_ context.Context, params *jaeger_api_v2.SamplingStrategyParameters) (*jaeger_api_v2.SamplingStrategyResponse, error) {
+	strategy, ok := s.strategies[params.ServiceName]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "unknown service")
+	}
+	return strategy, nil
+}
+
+// startTestSamplingManager starts a gRPC server serving the strategies and
+// returns its address.
+func startTestSamplingManager(t *testing.T, strategies map[string]*jaeger_api_v2.SamplingStrategyResponse) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	jaeger_api_v2.RegisterSamplingManagerServer(srv, &testSamplingManagerServer{strategies: strategies})
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+	return lis.Addr().String()
+}
+
+func TestGRPCSamplingStrategyFetcher(t *testing.T) {
+	addr := startTestSamplingManager(t, map[string]*jaeger_api_v2.SamplingStrategyResponse{
+		"svc": {
+			StrategyType: jaeger_api_v2.SamplingStrategyType_PROBABILISTIC,
+			OperationSampling: &jaeger_api_v2.PerOperationSamplingStrategies{
+				DefaultSamplingProbability: 0.5,
+				PerOperationStrategies: []*jaeger_api_v2.OperationSamplingStrategy{{
+					Operation:             "op",
+					ProbabilisticSampling: &jaeger_api_v2.ProbabilisticSamplingStrategy{SamplingRate: 0.1},
+				}},
+			},
+		},
+	})
+
+	fetcher, err := newGRPCSamplingStrategyFetcherForTarget(addr)
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, fetcher.Close()) })
+
+	resp, err := fetcher.Fetch("svc")
+	require.NoError(t, err)
+	strategy, err := new(samplingStrategyParserImpl).Parse(resp)
+	require.NoError(t, err)
+	ops := strategy.(*jaeger_api_v2.SamplingStrategyResponse).GetOperationSampling()
+	require.NotNil(t, ops)
+	assert.Equal(t, 0.5, ops.DefaultSamplingProbability)
+	require.Len(t, ops.PerOperationStrategies, 1)
+	assert.Equal(t, "op", ops.PerOperationStrategies[0].Operation)
+	assert.Equal(t, 0.1, ops.PerOperationStrategies[0].ProbabilisticSampling.SamplingRate)
+
+	_, err = fetcher.Fetch("unknown")
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestGRPCSamplingStrategyFetcherSampler(t *testing.T) {
+	addr := startTestSamplingManager(t, map[string]*jaeger_api_v2.SamplingStrategyResponse{
+		"svc": {
+			StrategyType:         jaeger_api_v2.SamplingStrategyType_RATE_LIMITING,
+			RateLimitingSampling: &jaeger_api_v2.RateLimitingSamplingStrategy{MaxTracesPerSecond: 5},
+		},
+	})
+	t.Setenv("OTEL_TRACES_SAMPLER_ARG", "grpcEndpoint="+addr)
+
+	sampler := New("svc", WithSamplingRefreshInterval(time.Hour))
+	defer sampler.Close()
+
+	sampler.UpdateSampler()
+	sampler.RLock()
+	defer sampler.RUnlock()
+	rl, ok := sampler.sampler.(*rateLimitingSampler)
+	require.True(t, ok, "unexpected sampler %T", sampler.sampler)
+	assert.Equal(t, 5.0, rl.maxTracesPerSecond)
+}
+
+func TestGRPCSamplingStrategyFetcherConnNotClosed(t *testing.T) {
+	conn, err := grpc.NewClient("passthrough:///localhost:1", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+
+	fetcher := NewGRPCSamplingStrategyFetcher(conn)
+	require.NoError(t, fetcher.(*grpcSamplingStrategyFetcher).Close())
+	// The connection of the caller is not closed by the fetcher.
+	assert.NoError(t, conn.Close())
+}
@@ -105,7 +105,9 @@ func (s *Sampler) ShouldSample(p trace.SamplingParameters) trace.SamplingResult
 }
 
 // Close does a clean shutdown of the sampler, stopping any background
-// go-routines it may have started.
+// go-routines it may have started. The gRPC connection created for the
+// grpcEndpoint argument of OTEL_TRACES_SAMPLER_ARG is closed. The fetcher set
+// with WithSamplingStrategyFetcher is not closed, it is owned by the caller.
 func (s *Sampler) Close() {
 	if swapped := s.closed.CompareAndSwap(0, 1); !swapped {
 		s.logger.Info("repeated attempt to close the sampler is ignored")
@@ -116,6 +118,12 @@ func (s *Sampler) Close() {
 	wg.Add(1)
 	s.doneChan <- &wg
 	wg.Wait()
+
+	if s.ownedFetcher != nil {
+		if err := s.ownedFetcher.Close(); err != nil {
+			s.logger.Error(err, "failed to close the sampling strategy fetcher")
+		}
+	}
 }
 
 // Description returns a human-readable name for the Sampler.
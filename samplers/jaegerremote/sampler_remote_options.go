@@ -20,6 +20,7 @@ package jaegerremote
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
@@ -34,17 +35,27 @@ type config struct {
 	samplingServerURL       string
 	samplingRefreshInterval time.Duration
 	samplingFetcher         SamplingStrategyFetcher
-	samplingParser          samplingStrategyParser
-	updaters                []samplerUpdater
-	posParams               perOperationSamplerParams
-	logger                  logr.Logger
-	attributesDisabled      bool
+	// grpcEndpoint is the target of the gRPC connection of the fetcher
+	// created by newConfig, for the grpcEndpoint argument of
+	// OTEL_TRACES_SAMPLER_ARG. It is unset if another fetcher is set after
+	// it, so no connection is created for a fetcher replaced.
+	grpcEndpoint string
+	// ownedFetcher is the fetcher created by newConfig, closed along with
+	// the Sampler.
+	ownedFetcher       io.Closer
+	samplingParser     samplingStrategyParser
+	updaters           []samplerUpdater
+	posParams          perOperationSamplerParams
+	logger             logr.Logger
+	attributesDisabled bool
 }
 
 func getEnvOptions() ([]Option, []error) {
 	var options []Option
 	// list of errors which will be logged once logger is set by the user
 	var errs []error
+	// fetcherKey is the argument setting the sampling strategy fetcher.
+	var fetcherKey string
 
 	rawEnvArgs := os.Getenv("OTEL_TRACES_SAMPLER_ARG")
 	if rawEnvArgs == "" {
@@ -78,6 +89,17 @@ func getEnvOptions() ([]Option, []error) {
 				continue
 			}
 			options = append(options, WithInitialSampler(trace.TraceIDRatioBased(samplingRate)))
+		case "grpcEndpoint", "strategiesFile":
+			if fetcherKey != "" {
+				errs = append(errs, fmt.Errorf("arguments %s and %s are mutually exclusive, %s is ignored", fetcherKey, key, key))
+				continue
+			}
+			fetcherKey = key
+			if key == "grpcEndpoint" {
+				options = append(options, withGRPCEndpoint(value))
+			} else {
+				options = append(options, WithSamplingStrategyFetcher(NewFileSamplingStrategyFetcher(value)))
+			}
 		default:
 			errs = append(errs, fmt.Errorf("invalid argument %s in OTEL_TRACE_SAMPLER_ARG", key))
 		}
@@ -108,6 +130,17 @@ func newConfig(options ...Option) config {
 		option.apply(&c)
 	}
 
+	if c.grpcEndpoint != "" {
+		fetcher, err := newGRPCSamplingStrategyFetcherForTarget(c.grpcEndpoint)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("grpcEndpoint parsing failed with :%w", err))
+			c.samplingFetcher = newHTTPSamplingStrategyFetcher(c.samplingServerURL)
+		} else {
+			c.samplingFetcher = fetcher
+			c.ownedFetcher = fetcher
+		}
+	}
+
 	for _, err := range errs {
 		c.logger.Error(err, "env variable parsing failure")
 	}
@@ -152,6 +185,7 @@ func WithInitialSampler(sampler trace.Sampler) Option {
 func WithSamplingServerURL(samplingServerURL string) Option {
 	return optionFunc(func(c *config) {
 		c.samplingServerURL = samplingServerURL
+		c.grpcEndpoint = ""
 		// The default port of jaeger agent is 5778, but there are other ports specified by the user, so the sampling address and fetch address are strongly bound
 		c.samplingFetcher = newHTTPSamplingStrategyFetcher(samplingServerURL)
 	})
@@ -191,9 +225,26 @@ func WithLogger(logger logr.Logger) Option {
 // WithSamplingStrategyFetcher creates an Option that initializes the sampling strategy fetcher.
 // Custom fetcher can be used for setting custom headers, timeouts, etc., or getting
 // sampling strategies from a different source, like files.
+//
+// See [NewGRPCSamplingStrategyFetcher] and [NewFileSamplingStrategyFetcher]
+// to fetch the sampling strategies with the Jaeger gRPC API or from a local
+// strategies file.
+//
+// The fetcher is owned by the caller, it is not closed when the Sampler is
+// closed.
 func WithSamplingStrategyFetcher(fetcher SamplingStrategyFetcher) Option {
 	return optionFunc(func(c *config) {
 		c.samplingFetcher = fetcher
+		c.grpcEndpoint = ""
+	})
+}
+
+// withGRPCEndpoint creates an Option that sets the sampling strategy fetcher
+// to a fetcher owning a gRPC connection to target. The connection is created
+// by newConfig, only if the fetcher is not replaced by a later option.
+func withGRPCEndpoint(target string) Option {
+	return optionFunc(func(c *config) {
+		c.grpcEndpoint = target
 	})
 }
 
@@ -630,6 +630,12 @@ func TestEnvVarSettingForNewTracer(t *testing.T) {
 				"invalid argument invalidKey in OTEL_TRACE_SAMPLER_ARG",
 			},
 		},
+		{
+			otelTraceSamplerArgs: "grpcEndpoint=localhost:14250,strategiesFile=strategies.json",
+			expErrs: []string{
+				"arguments grpcEndpoint and strategiesFile are mutually exclusive, strategiesFile is ignored",
+			},
+		},
 		{
 			// Make sure we don't override values provided in code
 			otelTraceSamplerArgs: "endpoint=http://localhost:14250,pollingIntervalMs=5000,initialSamplingRate=0.25",
@@ -663,6 +669,69 @@ func TestEnvVarSettingForNewTracer(t *testing.T) {
 		})
 	}
 
+	t.Run("Sampling strategy fetcher", func(t *testing.T) {
+		custom := &closerFetcher{}
+		for _, test := range []struct {
+			desc        string
+			args        string
+			codeOptions []Option
+			assert      func(*testing.T, SamplingStrategyFetcher)
+			owned       bool
+		}{
+			{
+				desc: "gRPC endpoint",
+				args: "grpcEndpoint=localhost:14250",
+				assert: func(t *testing.T, f SamplingStrategyFetcher) {
+					assert.IsType(t, &grpcSamplingStrategyFetcher{}, f)
+				},
+				owned: true,
+			},
+			{
+				desc: "gRPC endpoint first",
+				args: "grpcEndpoint=localhost:14250,strategiesFile=strategies.json",
+				assert: func(t *testing.T, f SamplingStrategyFetcher) {
+					assert.IsType(t, &grpcSamplingStrategyFetcher{}, f)
+				},
+				owned: true,
+			},
+			{
+				desc: "strategies file first",
+				args: "strategiesFile=strategies.json,grpcEndpoint=localhost:14250",
+				assert: func(t *testing.T, f SamplingStrategyFetcher) {
+					assert.IsType(t, &fileSamplingStrategyFetcher{}, f)
+				},
+			},
+			{
+				desc:        "fetcher provided in code",
+				args:        "grpcEndpoint=localhost:14250",
+				codeOptions: []Option{WithSamplingStrategyFetcher(custom)},
+				assert: func(t *testing.T, f SamplingStrategyFetcher) {
+					assert.Same(t, custom, f)
+				},
+			},
+			{
+				desc:        "server URL provided in code",
+				args:        "grpcEndpoint=localhost:14250",
+				codeOptions: []Option{WithSamplingServerURL("http://localhost:5778")},
+				assert: func(t *testing.T, f SamplingStrategyFetcher) {
+					assert.IsType(t, &httpSamplingStrategyFetcher{}, f)
+				},
+			},
+		} {
+			t.Run(test.desc, func(t *testing.T) {
+				t.Setenv("OTEL_TRACES_SAMPLER_ARG", test.args)
+				cfg := newConfig(test.codeOptions...)
+				test.assert(t, cfg.samplingFetcher)
+				if test.owned {
+					assert.Equal(t, cfg.samplingFetcher, cfg.ownedFetcher)
+					assert.NoError(t, cfg.ownedFetcher.Close())
+				} else {
+					assert.Nil(t, cfg.ownedFetcher, "no connection created")
+				}
+			})
+		}
+	})
+
 	t.Run("No-op when env var not set or empty", func(t *testing.T) {
 		for _, test := range []struct {
 			desc     string
@@ -692,3 +761,24 @@ func TestEnvVarSettingForNewTracer(t *testing.T) {
 		}
 	})
 }
+
+// closerFetcher is a SamplingStrategyFetcher recording whether it is closed.
+type closerFetcher struct {
+	closed bool
+}
+
+func (*closerFetcher) Fetch(string) ([]byte, error) {
+	return nil, errors.New("no strategy")
+}
+
+func (f *closerFetcher) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestSamplerCloseKeepsFetcher(t *testing.T) {
+	fetcher := &closerFetcher{}
+	sampler := New("svc", WithSamplingStrategyFetcher(fetcher), WithSamplingRefreshInterval(time.Hour))
+	sampler.Close()
+	assert.False(t, fetcher.closed, "fetcher owned by the caller closed")
+}
@@ -0,0 +1,309 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package consistent
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	// ScopeName is the instrumentation scope name of the metrics of the
+	// samplers.
+	ScopeName = "go.opentelemetry.io/contrib/samplers/probability/consistent"
+
+	defaultAdaptiveWindow       = 10 * time.Second
+	defaultAdaptiveMaxSpanNames = 256
+	// adaptiveBuckets is the number of buckets of the sliding window.
+	adaptiveBuckets = 10
+
+	spanNameKey = attribute.Key("span.name")
+)
+
+type (
+	// AdaptiveRateLimitedOption is an option to the AdaptiveRateLimited
+	// sampler.
+	AdaptiveRateLimitedOption interface {
+		applyAdaptive(*adaptiveConfig)
+	}
+
+	adaptiveConfig struct {
+		window        time.Duration
+		maxSpanNames  int
+		meterProvider metric.MeterProvider
+		clock         func() time.Time
+	}
+
+	adaptiveOptionFunc func(*adaptiveConfig)
+
+	adaptiveRateLimited struct {
+		// target is the number of spans sampled per second of each span
+		// name.
+		target float64
+		bucket time.Duration
+		clock  func() time.Time
+
+		mu           sync.Mutex
+		maxSpanNames int
+		rates        map[string]*spanRate
+		// other is the rate of the span names beyond maxSpanNames.
+		other *spanRate
+		// registration is the registration of the callback reporting the
+		// metrics, nil once unregistered.
+		registration metric.Registration
+
+		warning randomnessWarning
+	}
+
+	// spanRate estimates the rate of the spans of a span name over a
+	// sliding window, divided in buckets.
+	spanRate struct {
+		mu sync.Mutex
+		// counts are the number of spans of the adaptiveBuckets last
+		// buckets, along with the current one.
+		counts [adaptiveBuckets + 1]uint64
+		// current is the index of the current bucket in counts.
+		current     int
+		start       time.Time
+		bucketStart time.Time
+
+		// rate is the estimated number of spans per second.
+		rate float64
+		// threshold is the rejection threshold of the current bucket.
+		threshold uint64
+	}
+)
+
+func (fn adaptiveOptionFunc) applyAdaptive(cfg *adaptiveConfig) {
+	fn(cfg)
+}
+
+// WithAdaptiveWindow sets the duration of the sliding window over which the
+// rate of the spans is estimated. The sampling probability is updated
+// each tenth of the window. The default is 10 seconds.
+func WithAdaptiveWindow(window time.Duration) AdaptiveRateLimitedOption {
+	return adaptiveOptionFunc(func(cfg *adaptiveConfig) {
+		if window > 0 {
+			cfg.window = window
+		}
+	})
+}
+
+// WithAdaptiveMaxSpanNames sets the maximum number of span names whose rate
+// is tracked separately. The spans of the other span names share the same
+// target rate. The default is 256.
+func WithAdaptiveMaxSpanNames(maxSpanNames int) AdaptiveRateLimitedOption {
+	return adaptiveOptionFunc(func(cfg *adaptiveConfig) {
+		cfg.maxSpanNames = max(maxSpanNames, 0)
+	})
+}
+
+// WithAdaptiveMeterProvider sets the MeterProvider used to report the current
+// sampling probabilities and span rates. The global MeterProvider is used by
+// default.
+func WithAdaptiveMeterProvider(mp metric.MeterProvider) AdaptiveRateLimitedOption {
+	return adaptiveOptionFunc(func(cfg *adaptiveConfig) {
+		if mp != nil {
+			cfg.meterProvider = mp
+		}
+	})
+}
+
+// AdaptiveRateLimited samples up to a target number of traces per second of
+// each span name. Unlike a rate limiter, it samples a fraction of the spans,
+// adjusted each tenth of a sliding window to the rate of the spans estimated
+// over the window, so the decisions are consistent across services: the
+// sampling probability is the target divided by the estimated rate, up to 1.
+// Until the rate is first estimated, all the spans are sampled. With a target
+// of zero or less, no span is sampled.
+//
+// The decisions are made with the rejection threshold based consistent
+// probability sampling of the OpenTelemetry specification, like
+// ThresholdBased: this Sampler sets the OpenTelemetry tracestate th-value of
+// the sampled spans, so the number of spans they represent can be estimated
+// downstream, and unsets it for the others.
+//
+// The current sampling probability and the estimated span rate of each span
+// name are reported with the sampler.adaptive.probability and
+// sampler.adaptive.rate gauges, with the span.name attribute.
+//
+// The returned Sampler implements io.Closer: Close unregisters the callback
+// reporting its metrics. Close it when it is not used anymore, e.g. when it is
+// replaced, otherwise it keeps being reported by the MeterProvider and is not
+// garbage collected.
+//
+// To respect the parent trace's `SampledFlag`, this sampler should be
+// used as the root delegate of a `ParentThresholdBased` sampler.
+func AdaptiveRateLimited(tracesPerSecond float64, opts ...AdaptiveRateLimitedOption) sdktrace.Sampler {
+	cfg := adaptiveConfig{
+		window:        defaultAdaptiveWindow,
+		maxSpanNames:  defaultAdaptiveMaxSpanNames,
+		meterProvider: otel.GetMeterProvider(),
+		clock:         time.Now,
+	}
+	for _, opt := range opts {
+		opt.applyAdaptive(&cfg)
+	}
+
+	s := &adaptiveRateLimited{
+		target:       max(tracesPerSecond, 0),
+		bucket:       max(cfg.window/adaptiveBuckets, 1),
+		clock:        cfg.clock,
+		maxSpanNames: cfg.maxSpanNames,
+		rates:        make(map[string]*spanRate),
+		other:        new(spanRate),
+	}
+	if err := s.registerMetrics(cfg.meterProvider); err != nil {
+		otel.Handle(err)
+	}
+	return s
+}
+
+// ShouldSample implements "go.opentelemetry.io/otel/sdk/trace".Sampler.
+func (s *adaptiveRateLimited) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	threshold := s.spanRate(p.Name).update(s.clock(), s.target, s.bucket)
+	return sampleThreshold(p, threshold, &s.warning)
+}
+
+// Description returns "AdaptiveRateLimited{%g}" with the configured number
+// of traces per second.
+func (s *adaptiveRateLimited) Description() string {
+	return fmt.Sprintf("AdaptiveRateLimited{%g}", s.target)
+}
+
+// Close unregisters the callback reporting the metrics of the sampler. The
+// sampler can still be used after it is closed.
+func (s *adaptiveRateLimited) Close() error {
+	s.mu.Lock()
+	registration := s.registration
+	s.registration = nil
+	s.mu.Unlock()
+
+	if registration == nil {
+		return nil
+	}
+	return registration.Unregister()
+}
+
+// spanRate returns the rate of the spans with the name.
+func (s *adaptiveRateLimited) spanRate(name string) *spanRate {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r, ok := s.rates[name]; ok {
+		return r
+	}
+	if len(s.rates) >= s.maxSpanNames {
+		return s.other
+	}
+	r := new(spanRate)
+	s.rates[name] = r
+	return r
+}
+
+func (s *adaptiveRateLimited) registerMetrics(mp metric.MeterProvider) error {
+	meter := mp.Meter(ScopeName, metric.WithInstrumentationVersion(Version))
+	probability, err := meter.Float64ObservableGauge(
+		"sampler.adaptive.probability",
+		metric.WithUnit("1"),
+		metric.WithDescription("The current sampling probability of the spans."),
+	)
+	if err != nil {
+		return err
+	}
+	rate, err := meter.Float64ObservableGauge(
+		"sampler.adaptive.rate",
+		metric.WithUnit("{span}/s"),
+		metric.WithDescription("The estimated rate of the spans."),
+	)
+	if err != nil {
+		return err
+	}
+
+	s.registration, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		observe := func(r *spanRate, opts ...metric.ObserveOption) {
+			p, spanRate, ok := r.snapshot()
+			if !ok {
+				return
+			}
+			o.ObserveFloat64(probability, p, opts...)
+			o.ObserveFloat64(rate, spanRate, opts...)
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for name, r := range s.rates {
+			observe(r, metric.WithAttributes(spanNameKey.String(name)))
+		}
+		observe(s.other)
+		return nil
+	}, probability, rate)
+	return err
+}
+
+// update counts a span at now, and returns the rejection threshold sampling
+// target spans per second. The rate and the threshold are updated when a
+// bucket of the sliding window ends.
+func (r *spanRate) update(now time.Time, target float64, bucket time.Duration) uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.start.IsZero() {
+		r.start, r.bucketStart = now, now
+		r.threshold = rateThreshold(r.rate, target)
+	}
+	if elapsed := now.Sub(r.bucketStart); elapsed >= bucket {
+		n := int64(elapsed / bucket)
+		for i := int64(0); i < min(n, adaptiveBuckets+1); i++ {
+			r.current = (r.current + 1) % len(r.counts)
+			r.counts[r.current] = 0
+		}
+		r.bucketStart = r.bucketStart.Add(time.Duration(n) * bucket)
+
+		var total uint64
+		for _, c := range r.counts {
+			total += c
+		}
+		// The duration covered by the counts of the ended buckets.
+		covered := min(r.bucketStart.Sub(r.start), adaptiveBuckets*bucket)
+		r.rate = float64(total) / covered.Seconds()
+		r.threshold = rateThreshold(r.rate, target)
+	}
+
+	r.counts[r.current]++
+	return r.threshold
+}
+
+// rateThreshold returns the rejection threshold sampling target spans per
+// second of spans at rate per second.
+func rateThreshold(rate, target float64) uint64 {
+	if target == 0 {
+		// Nothing is sampled, even if no span was counted in the window.
+		return maxAdjustedCount
+	}
+	probability := 1.0
+	if rate > target {
+		probability = target / rate
+	}
+	return probabilityToThreshold(probability, maxThresholdDigits)
+}
+
+// snapshot returns the current sampling probability and span rate, if a span
+// was counted.
+func (r *spanRate) snapshot() (probability, rate float64, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.start.IsZero() {
+		return 0, 0, false
+	}
+	return thresholdToProbability(r.threshold), r.rate, true
+}
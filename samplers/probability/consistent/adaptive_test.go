@@ -0,0 +1,231 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package consistent
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/noop"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata/metricdatatest"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) option() AdaptiveRateLimitedOption {
+	return adaptiveOptionFunc(func(cfg *adaptiveConfig) {
+		cfg.clock = func() time.Time { return c.now }
+	})
+}
+
+func newTestAdaptive(t *testing.T, target float64, clock *testClock, opts ...AdaptiveRateLimitedOption) *adaptiveRateLimited {
+	t.Helper()
+	opts = append([]AdaptiveRateLimitedOption{WithAdaptiveMeterProvider(noop.NewMeterProvider()), clock.option()}, opts...)
+	s, ok := AdaptiveRateLimited(target, opts...).(*adaptiveRateLimited)
+	require.True(t, ok)
+	return s
+}
+
+// sampleAt samples n spans with the name evenly spread over d, and returns
+// the number of sampled spans.
+func sampleAt(s sdktrace.Sampler, clock *testClock, rnd *rand.Rand, name string, n int, d time.Duration) int {
+	sampled := 0
+	for range n {
+		var tid trace.TraceID
+		_, _ = rnd.Read(tid[:])
+		result := s.ShouldSample(sdktrace.SamplingParameters{
+			ParentContext: context.Background(),
+			TraceID:       tid,
+			Name:          name,
+		})
+		if result.Decision == sdktrace.RecordAndSample {
+			sampled++
+		}
+		clock.now = clock.now.Add(d / time.Duration(n))
+	}
+	return sampled
+}
+
+func TestAdaptiveRateLimitedDescription(t *testing.T) {
+	s := AdaptiveRateLimited(2.5, WithAdaptiveMeterProvider(noop.NewMeterProvider()))
+	assert.Equal(t, "AdaptiveRateLimited{2.5}", s.Description())
+	assert.Equal(t, "AdaptiveRateLimited{0}", AdaptiveRateLimited(-1).Description())
+}
+
+func TestAdaptiveRateLimitedConverges(t *testing.T) {
+	clock := &testClock{now: time.Unix(1000, 0)}
+	s := newTestAdaptive(t, 10, clock)
+	rnd := rand.New(rand.NewSource(1))
+
+	// All the spans are sampled until the rate is estimated.
+	assert.Equal(t, 100, sampleAt(s, clock, rnd, "span", 100, time.Second))
+
+	// 1000 spans per second.
+	sampleAt(s, clock, rnd, "span", 20000, 20*time.Second)
+	sampled := sampleAt(s, clock, rnd, "span", 10000, 10*time.Second)
+	assert.InDelta(t, 100, sampled, 30, "10 traces per second")
+
+	r := s.rates["span"]
+	probability, rate, ok := r.snapshot()
+	require.True(t, ok)
+	assert.InDelta(t, 1000, rate, 1)
+	assert.InDelta(t, 0.01, probability, 1e-4)
+
+	// The rate decreases below the target.
+	sampleAt(s, clock, rnd, "span", 50, 10*time.Second)
+	assert.Equal(t, 5, sampleAt(s, clock, rnd, "span", 5, time.Second))
+}
+
+func TestAdaptiveRateLimitedTraceState(t *testing.T) {
+	clock := &testClock{now: time.Unix(1000, 0)}
+	s := newTestAdaptive(t, 1, clock)
+	rnd := rand.New(rand.NewSource(1))
+	sampleAt(s, clock, rnd, "span", 40, 10*time.Second)
+
+	threshold := s.rates["span"].threshold
+	assert.Equal(t, probabilityToThreshold(0.25, maxThresholdDigits), threshold)
+
+	for _, rv := range []uint64{threshold - 1, threshold} {
+		state, err := trace.ParseTraceState("ot=rv:" + formatHex(rv))
+		require.NoError(t, err)
+		psc := trace.NewSpanContext(trace.SpanContextConfig{TraceState: state})
+		result := s.ShouldSample(sdktrace.SamplingParameters{
+			ParentContext: trace.ContextWithSpanContext(t.Context(), psc),
+			Name:          "span",
+		})
+		if rv < threshold {
+			assert.Equal(t, sdktrace.Drop, result.Decision)
+			assert.Equal(t, "rv:"+formatHex(rv), result.Tracestate.Get(traceStateKey))
+		} else {
+			assert.Equal(t, sdktrace.RecordAndSample, result.Decision)
+			// The probability is a power of two, the p-value is set too.
+			assert.Equal(t, "p:2;th:c;rv:"+formatHex(rv), result.Tracestate.Get(traceStateKey))
+		}
+	}
+}
+
+func TestAdaptiveRateLimitedSpanNames(t *testing.T) {
+	clock := &testClock{now: time.Unix(1000, 0)}
+	s := newTestAdaptive(t, 10, clock, WithAdaptiveMaxSpanNames(2))
+	rnd := rand.New(rand.NewSource(1))
+
+	for _, name := range []string{"a", "b", "c", "d"} {
+		sampleAt(s, clock, rnd, name, 10, time.Second)
+	}
+	assert.Len(t, s.rates, 2)
+	assert.Contains(t, s.rates, "a")
+	assert.Contains(t, s.rates, "b")
+	assert.Same(t, s.other, s.spanRate("c"))
+	assert.Same(t, s.other, s.spanRate("d"))
+}
+
+func TestAdaptiveRateLimitedIdle(t *testing.T) {
+	clock := &testClock{now: time.Unix(1000, 0)}
+	s := newTestAdaptive(t, 1, clock, WithAdaptiveWindow(time.Second))
+	rnd := rand.New(rand.NewSource(1))
+
+	sampleAt(s, clock, rnd, "span", 1000, time.Second)
+	assert.Less(t, sampleAt(s, clock, rnd, "span", 1000, time.Second), 10)
+
+	// Nothing is counted in the window anymore, all the spans are sampled.
+	clock.now = clock.now.Add(time.Hour)
+	sampleAt(s, clock, rnd, "span", 1, 0)
+	_, rate, _ := s.rates["span"].snapshot()
+	assert.Zero(t, rate)
+	assert.Equal(t, uint64(0), s.rates["span"].threshold)
+}
+
+func TestAdaptiveRateLimitedZeroTarget(t *testing.T) {
+	clock := &testClock{now: time.Unix(1000, 0)}
+	s := newTestAdaptive(t, 0, clock, WithAdaptiveWindow(time.Second))
+	rnd := rand.New(rand.NewSource(1))
+
+	assert.Zero(t, sampleAt(s, clock, rnd, "span", 100, time.Second), "before the rate is estimated")
+	assert.Zero(t, sampleAt(s, clock, rnd, "span", 100, time.Second), "after the rate is estimated")
+
+	// No span is counted in the window anymore.
+	clock.now = clock.now.Add(time.Hour)
+	assert.Zero(t, sampleAt(s, clock, rnd, "span", 100, time.Second), "idle window")
+	probability, _, ok := s.rates["span"].snapshot()
+	require.True(t, ok)
+	assert.Zero(t, probability)
+}
+
+func TestAdaptiveRateLimitedMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	clock := &testClock{now: time.Unix(1000, 0)}
+	s := AdaptiveRateLimited(5, WithAdaptiveMeterProvider(mp), WithAdaptiveMaxSpanNames(1), clock.option())
+	rnd := rand.New(rand.NewSource(1))
+
+	sampleAt(s, clock, rnd, "a", 100, 10*time.Second)
+	sampleAt(s, clock, rnd, "b", 1, 0)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(t.Context(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	sm := rm.ScopeMetrics[0]
+	assert.Equal(t, ScopeName, sm.Scope.Name)
+	assert.Equal(t, Version, sm.Scope.Version)
+
+	a := attribute.NewSet(spanNameKey.String("a"))
+	metricdatatest.AssertEqual(t, metricdata.Metrics{
+		Name:        "sampler.adaptive.probability",
+		Description: "The current sampling probability of the spans.",
+		Unit:        "1",
+		Data: metricdata.Gauge[float64]{
+			DataPoints: []metricdata.DataPoint[float64]{
+				{Attributes: a, Value: 0.5},
+				{Attributes: *attribute.EmptySet(), Value: 1},
+			},
+		},
+	}, sm.Metrics[0], metricdatatest.IgnoreTimestamp())
+	metricdatatest.AssertEqual(t, metricdata.Metrics{
+		Name:        "sampler.adaptive.rate",
+		Description: "The estimated rate of the spans.",
+		Unit:        "{span}/s",
+		Data: metricdata.Gauge[float64]{
+			DataPoints: []metricdata.DataPoint[float64]{
+				{Attributes: a, Value: 10},
+				{Attributes: *attribute.EmptySet(), Value: 0},
+			},
+		},
+	}, sm.Metrics[1], metricdatatest.IgnoreTimestamp())
+}
+
+func TestAdaptiveRateLimitedClose(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	clock := &testClock{now: time.Unix(1000, 0)}
+	s := AdaptiveRateLimited(5, WithAdaptiveMeterProvider(mp), clock.option())
+	rnd := rand.New(rand.NewSource(1))
+	sampleAt(s, clock, rnd, "a", 100, 10*time.Second)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(t.Context(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	closer, ok := s.(io.Closer)
+	require.True(t, ok, "sampler does not implement io.Closer")
+	require.NoError(t, closer.Close())
+	require.NoError(t, closer.Close(), "repeated Close")
+
+	require.NoError(t, reader.Collect(t.Context(), &rm))
+	assert.Empty(t, rm.ScopeMetrics, "metrics reported after Close")
+
+	// The sampler can still be used.
+	sampleAt(s, clock, rnd, "a", 1, 0)
+}
@@ -1,7 +1,15 @@
 // Copyright The OpenTelemetry Authors
 // SPDX-License-Identifier: Apache-2.0
 
-// Package consistent provides a consistent probability based sampler.
+// Package consistent provides consistent probability based samplers.
+//
+// ThresholdBased implements the rejection threshold based sampling of the
+// OpenTelemetry specification, using the th-value and rv-value of the
+// OpenTelemetry tracestate. ProbabilityBased implements the previous
+// power-of-two sampling, using the p-value and r-value. Both can be used in
+// the same system during a migration. AdaptiveRateLimited adjusts the
+// probability of the threshold sampling to sample a target number of traces
+// per second.
 package consistent
 
 import (
@@ -0,0 +1,257 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package consistent
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	thresholdSubkey  = "th"
+	randomnessSubkey = "rv"
+
+	// randomnessBits is the number of bits of the randomness value and of
+	// the rejection threshold.
+	randomnessBits = 56
+	// maxAdjustedCount is 2^56, the rejection threshold of the zero
+	// probability, which cannot be encoded.
+	maxAdjustedCount = uint64(1) << randomnessBits
+	randomnessMask   = maxAdjustedCount - 1
+	// maxThresholdDigits is the number of hex digits of a 56-bit value.
+	maxThresholdDigits = randomnessBits / 4
+)
+
+var (
+	errThresholdInconsistent = errors.New("otel tracestate: th-value is inconsistent with the randomness value")
+	errPresumedRandomness    = errors.New("otel tracestate: the random flag of the parent is not set and no rv-value is set, the trace ID is presumed random")
+)
+
+// thresholdTraceState is the OpenTelemetry tracestate entry of the threshold
+// sampling scheme (OTEP 235), along with the p-value and r-value of the
+// previous power-of-two scheme.
+type thresholdTraceState struct {
+	otelTraceState
+
+	// threshold is the rejection threshold, valid in the interval
+	// [0, 2^56). It is maxAdjustedCount if unset.
+	threshold uint64
+	// randomness is the explicit randomness value, valid in the interval
+	// [0, 2^56). It is maxAdjustedCount if unset.
+	randomness uint64
+}
+
+// parseThresholdTraceState parses the th-value and rv-value of the
+// tracestate, along with the p-value and r-value. The invalid values are
+// unset in the returned state and reported in the returned error.
+func parseThresholdTraceState(ts string, isSampled bool) (thresholdTraceState, error) {
+	otts, err := parseOTelTraceState(ts, isSampled)
+	tts := thresholdTraceState{
+		otelTraceState: otts,
+		threshold:      maxAdjustedCount,
+		randomness:     maxAdjustedCount,
+	}
+
+	var unknown []string
+	for _, kv := range otts.unknown {
+		key, value, _ := strings.Cut(kv, ":")
+		switch key {
+		case thresholdSubkey:
+			th, thErr := parseThreshold(value)
+			if thErr != nil {
+				err = errors.Join(err, thErr)
+				continue
+			}
+			tts.threshold = th
+		case randomnessSubkey:
+			rv, rvErr := parseRandomness(value)
+			if rvErr != nil {
+				err = errors.Join(err, rvErr)
+				continue
+			}
+			tts.randomness = rv
+		default:
+			unknown = append(unknown, kv)
+		}
+	}
+	tts.unknown = unknown
+
+	if tts.hasThreshold() && !isSampled {
+		// Note: the error ensures the parent-based sampler repairs the
+		// broken tracestate entry.
+		tts.threshold = maxAdjustedCount
+		err = errors.Join(err, parseError(thresholdSubkey, errTraceStateInconsistent))
+	}
+	return tts, err
+}
+
+// parseThreshold parses a th-value, 1 to 14 hex digits with the trailing
+// zeros removed.
+func parseThreshold(input string) (uint64, error) {
+	if input == "" || len(input) > maxThresholdDigits {
+		return maxAdjustedCount, parseError(thresholdSubkey, strconv.ErrSyntax)
+	}
+	value, err := strconv.ParseUint(input, 16, 64)
+	if err != nil {
+		return maxAdjustedCount, parseError(thresholdSubkey, err)
+	}
+	return value << (4 * (maxThresholdDigits - len(input))), nil
+}
+
+// parseRandomness parses an rv-value, exactly 14 hex digits.
+func parseRandomness(input string) (uint64, error) {
+	if len(input) != maxThresholdDigits {
+		return maxAdjustedCount, parseError(randomnessSubkey, strconv.ErrSyntax)
+	}
+	value, err := strconv.ParseUint(input, 16, 64)
+	if err != nil {
+		return maxAdjustedCount, parseError(randomnessSubkey, err)
+	}
+	return value, nil
+}
+
+func (tts thresholdTraceState) hasThreshold() bool {
+	return tts.threshold < maxAdjustedCount
+}
+
+func (tts thresholdTraceState) hasRandomness() bool {
+	return tts.randomness < maxAdjustedCount
+}
+
+// randomnessOf returns the randomness value of the trace: the explicit
+// rv-value, or a value consistent with the r-value of the power-of-two
+// scheme, or the 56 least significant bits of the trace ID.
+//
+// The returned presumed value reports whether the trace ID is presumed random:
+// the trace has no rv-value and the random flag of the parent psc is not set,
+// e.g. the parent follows the W3C Trace Context Level 1. As recommended by
+// the OpenTelemetry specification, the trace ID is used anyway. The trace ID
+// of a root span, generated by the SDK, is random and not presumed random.
+func (tts thresholdTraceState) randomnessOf(psc trace.SpanContext, traceID trace.TraceID) (randomness uint64, presumed bool) {
+	if tts.hasRandomness() {
+		return tts.randomness, false
+	}
+	presumed = psc.IsValid() && !psc.TraceFlags().IsRandom()
+	idRandomness := traceIDRandomness(traceID)
+	if tts.hasRValue() {
+		return legacyRandomness(tts.rvalue, idRandomness), presumed
+	}
+	return idRandomness, presumed
+}
+
+// randomnessWarning reports once that the randomness of a trace ID is
+// presumed.
+type randomnessWarning struct {
+	once sync.Once
+}
+
+func (w *randomnessWarning) report() {
+	w.once.Do(func() {
+		otel.Handle(errPresumedRandomness)
+	})
+}
+
+// traceIDRandomness returns the 56 least significant bits of the trace ID.
+func traceIDRandomness(traceID trace.TraceID) uint64 {
+	var r uint64
+	for _, b := range traceID[9:] {
+		r = r<<8 | uint64(b)
+	}
+	return r
+}
+
+// legacyRandomness returns a randomness value consistent with the r-value of
+// the power-of-two scheme: the r-value is the number of leading one bits of
+// the randomness value, the following bits are taken from idRandomness.
+//
+// A trace with this randomness value is sampled with the probability 2^-p,
+// i.e. with the threshold 2^56 - 2^(56-p), if and only if p <= r, like it is
+// with the r-value.
+func legacyRandomness(rvalue uint8, idRandomness uint64) uint64 {
+	if rvalue >= randomnessBits {
+		return randomnessMask
+	}
+	ones := randomnessMask &^ (randomnessMask >> rvalue)
+	return ones | idRandomness&(randomnessMask>>(rvalue+1))
+}
+
+// serialize returns the tracestate entry with the th-value and rv-value
+// followed by the p-value, r-value, and unknown keys.
+func (tts thresholdTraceState) serialize() string {
+	otts := tts.otelTraceState
+	otts.unknown = nil
+	if tts.hasThreshold() {
+		otts.unknown = append(otts.unknown, thresholdSubkey+":"+formatThreshold(tts.threshold))
+	}
+	if tts.hasRandomness() {
+		otts.unknown = append(otts.unknown, randomnessSubkey+":"+formatHex(tts.randomness))
+	}
+	otts.unknown = append(otts.unknown, tts.unknown...)
+	return otts.serialize()
+}
+
+// formatThreshold returns the th-value of a threshold, with the trailing
+// zeros removed.
+func formatThreshold(threshold uint64) string {
+	if threshold == 0 {
+		return "0"
+	}
+	return strings.TrimRight(formatHex(threshold), "0")
+}
+
+// formatHex returns the 14 hex digits of a 56-bit value.
+func formatHex(value uint64) string {
+	s := strconv.FormatUint(value, 16)
+	return strings.Repeat("0", maxThresholdDigits-len(s)) + s
+}
+
+// probabilityToThreshold returns the rejection threshold of the sampling
+// probability, rounded to the given number of hex digits. Probabilities
+// lesser than 2^-56 are treated as zero, with the threshold maxAdjustedCount.
+func probabilityToThreshold(fraction float64, precision int) uint64 {
+	if fraction >= 1 {
+		return 0
+	}
+	// Note: 2^56 * fraction is exact, rounding happens converting to an
+	// integer.
+	scaled := uint64(math.Round(math.Ldexp(fraction, randomnessBits)))
+	if fraction <= 0 || scaled == 0 {
+		return maxAdjustedCount
+	}
+	threshold := maxAdjustedCount - scaled
+
+	if precision > 0 && precision < maxThresholdDigits {
+		shift := 4 * (maxThresholdDigits - precision)
+		half := uint64(1) << (shift - 1)
+		threshold = (threshold + half) >> shift << shift
+		if threshold >= maxAdjustedCount {
+			// Do not round to the zero probability.
+			threshold = maxAdjustedCount - uint64(1)<<shift
+		}
+	}
+	return threshold
+}
+
+// thresholdToProbability returns the sampling probability of the rejection
+// threshold.
+func thresholdToProbability(threshold uint64) float64 {
+	return math.Ldexp(float64(maxAdjustedCount-threshold), -randomnessBits)
+}
+
+// thresholdToPValue returns the p-value of the power-of-two scheme equivalent
+// to the rejection threshold, if there is one.
+func thresholdToPValue(threshold uint64) (uint8, bool) {
+	adjusted := maxAdjustedCount - threshold
+	if adjusted == 0 || adjusted&(adjusted-1) != 0 {
+		return invalidValue, false
+	}
+	return uint8(randomnessBits - bits.TrailingZeros64(adjusted)), true //nolint:gosec // adjusted is a power of two lesser or equal to 2^56.
+}
@@ -0,0 +1,197 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package consistent
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	// ThresholdBasedOption is an option to the ThresholdBased sampler.
+	ThresholdBasedOption interface {
+		applyThreshold(*thresholdBasedConfig)
+	}
+
+	thresholdBasedConfig struct {
+		precision int
+	}
+
+	thresholdPrecision int
+
+	thresholdBased struct {
+		// threshold is the rejection threshold, maxAdjustedCount for the
+		// zero probability.
+		threshold uint64
+		warning   randomnessWarning
+	}
+
+	parentThresholdSampler struct {
+		delegate sdktrace.Sampler
+	}
+)
+
+// WithThresholdPrecision sets the number of hex digits, in the interval
+// [1, 14], the sampling threshold is rounded to. A lower precision shortens
+// the tracestate at the expense of the accuracy of the sampling probability.
+// By default, the full precision of 14 digits is used.
+func WithThresholdPrecision(digits int) ThresholdBasedOption {
+	return thresholdPrecision(digits)
+}
+
+func (p thresholdPrecision) applyThreshold(cfg *thresholdBasedConfig) {
+	cfg.precision = min(max(int(p), 1), maxThresholdDigits)
+}
+
+// ThresholdBased samples a given fraction of traces, using the rejection
+// threshold based consistent probability sampling of the OpenTelemetry
+// specification (OTEP 235). Unlike ProbabilityBased, any fraction is
+// supported, up to a precision of 2^-56.
+// - Fractions >= 1 will always sample.
+// - Fractions < 2^-56 are treated as zero.
+//
+// A span is sampled when the randomness value of its trace is greater than or
+// equal to the rejection threshold. The randomness value is the tracestate
+// rv-value, or the 56 least significant bits of the trace ID. When the parent
+// has neither the random trace flag nor an rv-value, e.g. it follows the W3C
+// Trace Context Level 1, the trace ID is presumed random, as recommended by
+// the specification, and an error is handled once with the global error
+// handler.
+// When the tracestate has the r-value of the power-of-two scheme, a
+// consistent randomness value is derived from it, so that ThresholdBased and
+// ProbabilityBased samplers make consistent decisions during a migration.
+//
+// This Sampler sets the OpenTelemetry tracestate th-value of the sampled
+// spans, and unsets it for the others. The p-value of the power-of-two scheme
+// is also set for probabilities that are powers of two, and unset otherwise.
+//
+// To respect the parent trace's `SampledFlag`, this sampler should be
+// used as the root delegate of a `ParentThresholdBased` sampler.
+func ThresholdBased(fraction float64, opts ...ThresholdBasedOption) sdktrace.Sampler {
+	cfg := thresholdBasedConfig{precision: maxThresholdDigits}
+	for _, opt := range opts {
+		opt.applyThreshold(&cfg)
+	}
+	return &thresholdBased{
+		threshold: probabilityToThreshold(fraction, cfg.precision),
+	}
+}
+
+// ShouldSample implements "go.opentelemetry.io/otel/sdk/trace".Sampler.
+func (ts *thresholdBased) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return sampleThreshold(p, ts.threshold, &ts.warning)
+}
+
+// sampleThreshold returns the sampling result of the rejection threshold,
+// with the th-value and p-value of the tracestate updated accordingly. The
+// warning is reported if the randomness of the trace ID is presumed.
+func sampleThreshold(p sdktrace.SamplingParameters, threshold uint64, warning *randomnessWarning) sdktrace.SamplingResult {
+	psc := trace.SpanContextFromContext(p.ParentContext)
+
+	// Note: this ignores whether psc.IsValid() because this
+	// allows other otel trace state keys to pass through even
+	// for root decisions.
+	state := psc.TraceState()
+
+	tts, err := parseThresholdTraceState(state.Get(traceStateKey), psc.IsSampled())
+	if err != nil {
+		// Note: a state.Insert(traceStateKey)
+		// follows, nothing else needs to be done here.
+		otel.Handle(err)
+	}
+
+	decision := sdktrace.Drop
+	tts.threshold = maxAdjustedCount
+	tts.pvalue = invalidValue
+	if threshold < maxAdjustedCount {
+		randomness, presumed := tts.randomnessOf(psc, p.TraceID)
+		if presumed {
+			warning.report()
+		}
+		if randomness >= threshold {
+			decision = sdktrace.RecordAndSample
+			tts.threshold = threshold
+			if pvalue, ok := thresholdToPValue(threshold); ok {
+				tts.pvalue = pvalue
+			}
+		}
+	}
+
+	value := tts.serialize()
+	if value != "" {
+		// Note: see the note in
+		// "go.opentelemetry.io/otel/trace".TraceState.Insert(). The
+		// error below is not a condition we're supposed to handle.
+		state, _ = state.Insert(traceStateKey, value)
+	} else {
+		state = state.Delete(traceStateKey)
+	}
+
+	return sdktrace.SamplingResult{
+		Decision:   decision,
+		Tracestate: state,
+	}
+}
+
+// Description returns "ThresholdBased{%g}" with the configured probability.
+func (ts *thresholdBased) Description() string {
+	return fmt.Sprintf("ThresholdBased{%g}", thresholdToProbability(ts.threshold))
+}
+
+// ParentThresholdBased is an implementation of the OpenTelemetry Trace
+// Sampler interface that provides additional checks for the tracestate
+// threshold sampling fields. It unsets the th-value of the parent when it is
+// inconsistent with the sampled flag or the randomness value of the trace,
+// and the p-value when it is inconsistent with the r-value.
+func ParentThresholdBased(root sdktrace.Sampler, samplers ...sdktrace.ParentBasedSamplerOption) sdktrace.Sampler {
+	return &parentThresholdSampler{
+		delegate: sdktrace.ParentBased(root, samplers...),
+	}
+}
+
+// ShouldSample implements "go.opentelemetry.io/otel/sdk/trace".Sampler.
+func (p *parentThresholdSampler) ShouldSample(params sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	psc := trace.SpanContextFromContext(params.ParentContext)
+
+	// Note: We do not check psc.IsValid(), i.e., we repair the tracestate
+	// with or without a parent TraceId and SpanId.
+	state := psc.TraceState()
+
+	tts, err := parseThresholdTraceState(state.Get(traceStateKey), psc.IsSampled())
+	// Note: the randomness presumed by the parent, if any, is presumed the
+	// same way here.
+	if randomness, _ := tts.randomnessOf(psc, params.TraceID); tts.hasThreshold() && randomness < tts.threshold {
+		tts.threshold = maxAdjustedCount
+		err = errors.Join(err, errThresholdInconsistent)
+	}
+	if err != nil {
+		otel.Handle(err)
+		value := tts.serialize()
+		if value != "" {
+			// Note: see the note in
+			// "go.opentelemetry.io/otel/trace".TraceState.Insert(). The
+			// error below is not a condition we're supposed to handle.
+			state, _ = state.Insert(traceStateKey, value)
+		} else {
+			state = state.Delete(traceStateKey)
+		}
+
+		// Fix the broken tracestate before calling the delegate.
+		params.ParentContext = trace.ContextWithSpanContext(params.ParentContext, psc.WithTraceState(state))
+	}
+
+	return p.delegate.ShouldSample(params)
+}
+
+// Description returns the same description as the built-in
+// ParentBased sampler, with "ParentBased" replaced by
+// "ParentThresholdBased".
+func (p *parentThresholdSampler) Description() string {
+	return "ParentThresholdBased" + strings.TrimPrefix(p.delegate.Description(), "ParentBased")
+}
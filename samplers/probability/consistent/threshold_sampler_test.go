@@ -0,0 +1,272 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package consistent
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func thresholdSamplingParameters(t *testing.T, tracestate string, sampled bool) sdktrace.SamplingParameters {
+	t.Helper()
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+
+	traceState := trace.TraceState{}
+	if tracestate != "" {
+		var err error
+		traceState, err = traceState.Insert(traceStateKey, tracestate)
+		require.NoError(t, err)
+	}
+	sccfg := trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsRandom,
+		TraceState: traceState,
+	}
+	if sampled {
+		sccfg.TraceFlags |= trace.FlagsSampled
+	}
+	return sdktrace.SamplingParameters{
+		ParentContext: trace.ContextWithSpanContext(t.Context(), trace.NewSpanContext(sccfg)),
+		TraceID:       traceID,
+		Name:          "test",
+		Kind:          trace.SpanKindServer,
+	}
+}
+
+func TestThresholdSamplerDescription(t *testing.T) {
+	for _, tc := range []struct {
+		prob   float64
+		opts   []ThresholdBasedOption
+		expect string
+	}{
+		{1, nil, "ThresholdBased{1}"},
+		{0, nil, "ThresholdBased{0}"},
+		{0.75, nil, "ThresholdBased{0.75}"},
+		{0.1, nil, "ThresholdBased{0.1}"},
+		{0.003, nil, "ThresholdBased{0.0030000000000000027}"},
+		{0x1p-56, nil, "ThresholdBased{1.3877787807814457e-17}"},
+		{0.1, []ThresholdBasedOption{WithThresholdPrecision(1)}, "ThresholdBased{0.125}"},
+		{0.1, []ThresholdBasedOption{WithThresholdPrecision(-1)}, "ThresholdBased{0.125}"},
+		{0.1, []ThresholdBasedOption{WithThresholdPrecision(20)}, "ThresholdBased{0.1}"},
+
+		// out-of-range
+		{1.01, nil, "ThresholdBased{1}"},
+		{-1, nil, "ThresholdBased{0}"},
+		{0x1p-58, nil, "ThresholdBased{0}"},
+	} {
+		s := ThresholdBased(tc.prob, tc.opts...)
+		require.Equal(t, tc.expect, s.Description(), "%#v", tc.prob)
+	}
+
+	parent := ParentThresholdBased(ThresholdBased(0.5))
+	require.Equal(t,
+		strings.Replace(sdktrace.ParentBased(ThresholdBased(0.5)).Description(), "ParentBased", "ParentThresholdBased", 1),
+		parent.Description(),
+	)
+}
+
+func TestThresholdSamplerBehavior(t *testing.T) {
+	// The randomness of the trace ID is 0xce929d0e0e4736.
+	for _, tc := range []struct {
+		name       string
+		prob       float64
+		tracestate string
+		sampled    bool
+		hasErrors  bool
+		wantSample bool
+		want       string
+	}{
+		{"always", 1, "", false, false, true, "p:0;th:0"},
+		{"half", 0.5, "", false, false, true, "p:1;th:8"},
+		{"quarter", 0.25, "a:b", false, false, true, "p:2;th:c;a:b"},
+		{"tenth", 0.1, "a:b", false, false, false, "a:b"},
+		{"never", 0, "", false, false, false, ""},
+		{"arbitrary", 0.2, "", true, false, true, "th:cccccccccccccc"},
+
+		// The rv-value takes precedence over the trace ID.
+		{"rv sampled", 0.1, "rv:f0000000000000", false, false, true, "th:e6666666666666;rv:f0000000000000"},
+		{"rv dropped", 0.5, "rv:10000000000000;th:8", true, false, false, "rv:10000000000000"},
+
+		// The r-value is converted to a consistent randomness.
+		{"r sampled", 0.125, "r:3", true, false, true, "p:3;r:3;th:e"},
+		{"r dropped", 0.125, "r:2", true, false, false, "r:2"},
+		{"r non power of two", 0.2, "r:3", true, false, true, "r:3;th:cccccccccccccc"},
+
+		// Errors are reported, and the invalid values unset.
+		{"invalid th", 0.5, "th:xyz", true, true, true, "p:1;th:8"},
+		{"invalid rv", 0.1, "rv:f0;th:8", true, true, false, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := &testErrorHandler{}
+			otel.SetErrorHandler(handler)
+
+			result := ThresholdBased(tc.prob).ShouldSample(thresholdSamplingParameters(t, tc.tracestate, tc.sampled))
+			require.Equal(t, tc.wantSample, result.Decision == sdktrace.RecordAndSample)
+			require.Equal(t, tc.want, result.Tracestate.Get(traceStateKey))
+			if tc.hasErrors {
+				require.NotEmpty(t, handler.Errors())
+			} else {
+				require.Empty(t, handler.Errors())
+			}
+		})
+	}
+}
+
+func TestThresholdSamplerPresumedRandomness(t *testing.T) {
+	withoutRandomFlag := func(t *testing.T, tracestate string) sdktrace.SamplingParameters {
+		t.Helper()
+		params := thresholdSamplingParameters(t, tracestate, true)
+		psc := trace.SpanContextFromContext(params.ParentContext)
+		psc = psc.WithTraceFlags(psc.TraceFlags().WithRandom(false))
+		params.ParentContext = trace.ContextWithSpanContext(t.Context(), psc)
+		return params
+	}
+
+	handler := &testErrorHandler{}
+	otel.SetErrorHandler(handler)
+
+	// The randomness of the trace ID is 0xce929d0e0e4736: the trace ID is
+	// presumed random, and used anyway.
+	sampler := ThresholdBased(0.25)
+	result := sampler.ShouldSample(withoutRandomFlag(t, ""))
+	require.Equal(t, sdktrace.RecordAndSample, result.Decision)
+	require.Equal(t, "p:2;th:c", result.Tracestate.Get(traceStateKey))
+	require.Equal(t, []error{errPresumedRandomness}, handler.Errors())
+
+	// The presumption is reported once per sampler.
+	result = sampler.ShouldSample(withoutRandomFlag(t, "r:1"))
+	require.Equal(t, sdktrace.Drop, result.Decision)
+	require.Len(t, handler.Errors(), 1)
+
+	// The rv-value is explicitly random.
+	handler = &testErrorHandler{}
+	otel.SetErrorHandler(handler)
+	result = ThresholdBased(0.25).ShouldSample(withoutRandomFlag(t, "rv:10000000000000"))
+	require.Equal(t, sdktrace.Drop, result.Decision)
+	require.Empty(t, handler.Errors())
+
+	// The trace ID of a root span is generated by the SDK.
+	result = ThresholdBased(0.25).ShouldSample(sdktrace.SamplingParameters{
+		ParentContext: t.Context(),
+		TraceID:       trace.TraceID{1},
+	})
+	require.Equal(t, sdktrace.Drop, result.Decision)
+	require.Empty(t, handler.Errors())
+}
+
+func TestThresholdSamplerLegacyConsistency(t *testing.T) {
+	// ThresholdBased samplers with power-of-two probabilities make the same
+	// decisions as ProbabilityBased samplers, for traces with an r-value.
+	rnd := rand.New(rand.NewSource(101333)) //nolint:gosec // G404: Use of weak random number generator (math/rand instead of crypto/rand) is ignored as this is a test.
+	for range 100 {
+		var traceID trace.TraceID
+		_, _ = rnd.Read(traceID[:])
+
+		root := ProbabilityBased(1, WithRandomSource(rnd)).ShouldSample(sdktrace.SamplingParameters{
+			ParentContext: t.Context(),
+			TraceID:       traceID,
+		})
+		sc := trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    traceID,
+			SpanID:     trace.SpanID{1},
+			TraceFlags: trace.FlagsSampled | trace.FlagsRandom,
+			TraceState: root.Tracestate,
+		})
+		params := sdktrace.SamplingParameters{
+			ParentContext: trace.ContextWithSpanContext(t.Context(), sc),
+			TraceID:       traceID,
+		}
+
+		for p := range 10 {
+			prob := math.Ldexp(1, -p)
+			legacy := ProbabilityBased(prob).ShouldSample(params)
+			threshold := ThresholdBased(prob).ShouldSample(params)
+			require.Equal(t, legacy.Decision, threshold.Decision, "p=%d", p)
+
+			legacyState, err := parseOTelTraceState(legacy.Tracestate.Get(traceStateKey), true)
+			require.NoError(t, err)
+			thresholdState, err := parseThresholdTraceState(threshold.Tracestate.Get(traceStateKey), true)
+			require.NoError(t, err)
+			require.Equal(t, legacyState.rvalue, thresholdState.rvalue)
+			if threshold.Decision == sdktrace.RecordAndSample {
+				require.Equal(t, legacyState.pvalue, thresholdState.pvalue)
+			}
+		}
+	}
+}
+
+func TestParentThresholdSampler(t *testing.T) {
+	parent := ParentThresholdBased(sdktrace.NeverSample())
+
+	// The randomness of the trace ID is 0xce929d0e0e4736.
+	for _, tc := range []struct {
+		tracestate string
+		sampled    bool
+		hasErrors  bool
+		want       string
+	}{
+		// Consistent tracestates are unchanged.
+		{"th:8", true, false, "th:8"},
+		{"th:c;a:b", true, false, "th:c;a:b"},
+		{"rv:f0000000000000;th:e", true, false, "rv:f0000000000000;th:e"},
+		{"r:5;p:3;th:e", true, false, "r:5;p:3;th:e"},
+		{"rv:10000000000000", false, false, "rv:10000000000000"},
+
+		// The th-value is unset when not sampled.
+		{"th:8;a:b", false, true, "a:b"},
+		// The th-value is unset when greater than the randomness.
+		{"th:e", true, true, ""},
+		{"rv:10000000000000;th:8", true, true, "rv:10000000000000"},
+		{"r:2;th:e", true, true, "r:2"},
+		// The p-value is unset when inconsistent with the r-value.
+		{"r:2;p:3;th:8", true, true, "r:2;th:8"},
+	} {
+		t.Run(testName(tc.tracestate), func(t *testing.T) {
+			handler := &testErrorHandler{}
+			otel.SetErrorHandler(handler)
+
+			result := parent.ShouldSample(thresholdSamplingParameters(t, tc.tracestate, tc.sampled))
+			require.Equal(t, tc.sampled, result.Decision == sdktrace.RecordAndSample)
+			require.Equal(t, tc.want, result.Tracestate.Get(traceStateKey))
+			if tc.hasErrors {
+				require.NotEmpty(t, handler.Errors())
+			} else {
+				require.Empty(t, handler.Errors())
+			}
+		})
+	}
+}
+
+func TestThresholdSamplerRate(t *testing.T) {
+	const (
+		prob    = 0.1
+		samples = 100000
+	)
+	sampler := ThresholdBased(prob)
+	rnd := rand.New(rand.NewSource(77777677777)) //nolint:gosec // G404: Use of weak random number generator (math/rand instead of crypto/rand) is ignored as this is a test.
+
+	var sampled int
+	for range samples {
+		var traceID trace.TraceID
+		_, _ = rnd.Read(traceID[:])
+		result := sampler.ShouldSample(sdktrace.SamplingParameters{
+			ParentContext: t.Context(),
+			TraceID:       traceID,
+		})
+		if result.Decision == sdktrace.RecordAndSample {
+			sampled++
+		}
+	}
+	// The standard deviation is sqrt(samples*prob*(1-prob)) ~= 95.
+	require.InDelta(t, prob*samples, sampled, 500)
+}
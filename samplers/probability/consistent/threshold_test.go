@@ -0,0 +1,155 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package consistent
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestParseThresholdTraceStateValid(t *testing.T) {
+	for _, tc := range []struct {
+		in         string
+		threshold  uint64
+		randomness uint64
+		pvalue     uint8
+		rvalue     uint8
+		out        string
+	}{
+		{"", maxAdjustedCount, maxAdjustedCount, invalidValue, invalidValue, ""},
+		{"th:0", 0, maxAdjustedCount, invalidValue, invalidValue, "th:0"},
+		{"th:8", 0x80000000000000, maxAdjustedCount, invalidValue, invalidValue, "th:8"},
+		{"th:c0", 0xc0000000000000, maxAdjustedCount, invalidValue, invalidValue, "th:c"},
+		{"th:e666", 0xe6660000000000, maxAdjustedCount, invalidValue, invalidValue, "th:e666"},
+		{"th:ffffffffffffff", 0xffffffffffffff, maxAdjustedCount, invalidValue, invalidValue, "th:ffffffffffffff"},
+		{"rv:00000000000000", maxAdjustedCount, 0, invalidValue, invalidValue, "rv:00000000000000"},
+		{"rv:ce929d0e0e4736;th:8", 0x80000000000000, 0xce929d0e0e4736, invalidValue, invalidValue, "th:8;rv:ce929d0e0e4736"},
+		{"th:8;a:b;rv:ce929d0e0e4736", 0x80000000000000, 0xce929d0e0e4736, invalidValue, invalidValue, "th:8;rv:ce929d0e0e4736;a:b"},
+		{"p:1;r:5;th:8", 0x80000000000000, maxAdjustedCount, 1, 5, "p:1;r:5;th:8"},
+	} {
+		t.Run(testName(tc.in), func(t *testing.T) {
+			tts, err := parseThresholdTraceState(tc.in, true)
+			require.NoError(t, err)
+			require.Equal(t, tc.threshold, tts.threshold)
+			require.Equal(t, tc.randomness, tts.randomness)
+			require.Equal(t, tc.pvalue, tts.pvalue)
+			if tc.rvalue == invalidValue {
+				require.False(t, tts.hasRValue())
+			} else {
+				require.Equal(t, tc.rvalue, tts.rvalue)
+			}
+			require.Equal(t, tc.out, tts.serialize())
+		})
+	}
+}
+
+func TestParseThresholdTraceStateInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		in      string
+		sampled bool
+		out     string
+	}{
+		{"th:", true, ""},
+		{"th:g", true, ""},
+		{"th:fffffffffffffff", true, ""},
+		{"th:8;a:b", false, "a:b"},
+		{"rv:123", true, ""},
+		{"rv:ce929d0e0e4736a", true, ""},
+		{"rv:ce929d0e0e473g;th:8", true, "th:8"},
+		{"r:10;p:20;th:8", true, "r:10;th:8"},
+	} {
+		t.Run(testName(tc.in), func(t *testing.T) {
+			tts, err := parseThresholdTraceState(tc.in, tc.sampled)
+			require.Error(t, err)
+			require.Equal(t, tc.out, tts.serialize())
+		})
+	}
+}
+
+func TestProbabilityToThreshold(t *testing.T) {
+	for _, tc := range []struct {
+		prob      float64
+		precision int
+		th        string
+	}{
+		{1, maxThresholdDigits, "0"},
+		{2, maxThresholdDigits, "0"},
+		{0.5, maxThresholdDigits, "8"},
+		{0.25, maxThresholdDigits, "c"},
+		{0.75, maxThresholdDigits, "4"},
+		{0.1, maxThresholdDigits, "e6666666666666"},
+		{0.1, 4, "e666"},
+		{0.1, 1, "e"},
+		{1.0 / 3, maxThresholdDigits, "aaaaaaaaaaaaac"},
+		{1.0 / 3, 4, "aaab"},
+		{0x1p-56, maxThresholdDigits, "ffffffffffffff"},
+		// Rounded to the smallest probability of the precision, not zero.
+		{0x1p-56, 2, "ff"},
+	} {
+		t.Run(strconv.FormatFloat(tc.prob, 'g', -1, 64), func(t *testing.T) {
+			threshold := probabilityToThreshold(tc.prob, tc.precision)
+			require.Equal(t, tc.th, formatThreshold(threshold))
+		})
+	}
+
+	for _, prob := range []float64{0, -1, 0x1p-58} {
+		require.Equal(t, maxAdjustedCount, probabilityToThreshold(prob, maxThresholdDigits), "%g", prob)
+	}
+}
+
+func TestThresholdToProbability(t *testing.T) {
+	require.Equal(t, 1.0, thresholdToProbability(0))
+	require.Equal(t, 0.5, thresholdToProbability(0x80000000000000))
+	require.Equal(t, 0.0, thresholdToProbability(maxAdjustedCount))
+	require.InDelta(t, 0.1, thresholdToProbability(probabilityToThreshold(0.1, maxThresholdDigits)), 1e-16)
+}
+
+func TestThresholdToPValue(t *testing.T) {
+	for p := range uint8(randomnessBits + 1) {
+		threshold := probabilityToThreshold(math.Ldexp(1, -int(p)), maxThresholdDigits)
+		pvalue, ok := thresholdToPValue(threshold)
+		require.True(t, ok)
+		require.Equal(t, p, pvalue)
+	}
+
+	for _, threshold := range []uint64{
+		probabilityToThreshold(0.75, maxThresholdDigits),
+		probabilityToThreshold(0.1, maxThresholdDigits),
+		maxAdjustedCount,
+	} {
+		_, ok := thresholdToPValue(threshold)
+		require.False(t, ok)
+	}
+}
+
+func TestTraceIDRandomness(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	require.Equal(t, uint64(0xce929d0e0e4736), traceIDRandomness(traceID))
+}
+
+func TestLegacyRandomness(t *testing.T) {
+	const idRandomness = randomnessMask
+	require.Equal(t, uint64(0x7fffffffffffff), legacyRandomness(0, idRandomness))
+	require.Equal(t, uint64(0x00000000000000), legacyRandomness(0, 0))
+	require.Equal(t, uint64(0xbfffffffffffff), legacyRandomness(1, idRandomness))
+	require.Equal(t, uint64(0xf0000000000000), legacyRandomness(4, 0))
+	require.Equal(t, uint64(0xfffffffffffffe), legacyRandomness(55, idRandomness))
+	require.Equal(t, randomnessMask, legacyRandomness(56, 0))
+	require.Equal(t, randomnessMask, legacyRandomness(62, 0))
+
+	// A trace is sampled with the probability 2^-p if and only if p <= r.
+	for r := range uint8(pZeroValue) {
+		for p := range uint8(randomnessBits + 1) {
+			threshold := probabilityToThreshold(math.Ldexp(1, -int(p)), maxThresholdDigits)
+			for _, idRandomness := range []uint64{0, 0x5555555555555, randomnessMask} {
+				sampled := legacyRandomness(r, idRandomness) >= threshold
+				require.Equal(t, p <= r, sampled, "r=%d p=%d", r, p)
+			}
+		}
+	}
+}